@@ -0,0 +1,66 @@
+// admin_handler.go: Handle administrative requests, such as backups of the whole database
+package handlers
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	apimiddleware "github.com/pacific-theta-tau/tt-db/api/middleware"
+	"github.com/pacific-theta-tau/tt-db/store"
+)
+
+// GET /api/admin/export
+//	@Summary		Export a backup of the database
+//	@Description	Download a ZIP archive with a versioned manifest.json and one JSON file per table (brothers, events, categories, attendance, semesters, statuses, merges, notes, attachment metadata, candidates...). Attachment contents are not included. Restore it with `ttdb restore`. Admins only
+//	@Tags			Admin
+//	@Produce		application/zip
+//	@Success		200		{file}		file
+//	@Failure		401		{object}	models.APIResponse
+//	@Failure		403		{object}	models.APIResponse
+//	@Failure		500		{object}	models.APIResponse
+//	@Failure		504		{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/admin/export [get]
+func (h *Handler) ExportBackup(w http.ResponseWriter, r *http.Request) {
+    // Backups hold every table, including officers-only notes and candidates' contact details
+    if !apimiddleware.CanAdminister(r.Context()) {
+        respondWithDenied(w, r, "Non-admin tried to export a backup", "Only admins can export backups")
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    // Read every table from the same snapshot so rows reference each other consistently
+    tx, err := h.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while starting backup transaction")
+        return
+    }
+    defer tx.Rollback()
+
+    backup, err := store.ExportBackup(ctx, tx)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while exporting backup")
+        return
+    }
+
+    // Build the archive before sending anything, so errors can still be reported as JSend
+    var archive bytes.Buffer
+    if err := store.WriteBackup(&archive, backup); err != nil {
+        respondWithInternalError(w, r, err, "Error while writing backup archive")
+        return
+    }
+    slog.InfoContext(r.Context(), "Exported backup", "schema_version", backup.Manifest.SchemaVersion, "tables", backup.Manifest.Tables)
+
+    filename := fmt.Sprintf("tt-db-backup-%s.zip", backup.Manifest.CreatedAt.Format("20060102-150405"))
+    w.Header().Set("Content-Type", "application/zip")
+    w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+    w.Header().Set("Content-Length", strconv.Itoa(archive.Len()))
+    w.WriteHeader(http.StatusOK)
+    archive.WriteTo(w)
+}
@@ -0,0 +1,464 @@
+// attachments_handler.go: Handle uploads and downloads of files attached to brothers and events
+package handlers
+
+import (
+	"bytes"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/go-chi/chi"
+	apimiddleware "github.com/pacific-theta-tau/tt-db/api/middleware"
+	"github.com/pacific-theta-tau/tt-db/api/models"
+	"github.com/pacific-theta-tau/tt-db/blob"
+	"github.com/pacific-theta-tau/tt-db/store"
+)
+
+// Reports whether a detected content type may be uploaded
+func allowedType(c blob.AttachmentConfig, detected *mimetype.MIME) bool {
+	for _, allowed := range c.AllowedTypes {
+		if detected.Is(allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// Bytes read to detect the content type of an upload
+const sniffLength = 3072
+
+// Room for multipart boundaries and part headers on top of the file
+const multipartOverhead = 64 << 10
+
+// What attachments belong to: brothers or events
+type attachmentOwner struct {
+	column store.AttachmentOwner
+	// URL parameter holding the owner's ID
+	param string
+	// Used in messages and blob keys
+	name string
+	path string
+}
+
+var (
+	brotherAttachments = attachmentOwner{store.BrotherAttachments, "id", "Brother", "brothers"}
+	eventAttachments   = attachmentOwner{store.EventAttachments, "eventID", "Event", "events"}
+)
+
+// Parses the owner ID and, when present, the attachment ID from the URL. Responds with an error and returns false if one is invalid
+func attachmentParams(w http.ResponseWriter, r *http.Request, owner attachmentOwner) (ownerID int, attachmentID int, ok bool) {
+    ownerID, err := strconv.Atoi(chi.URLParam(r, owner.param))
+    if err != nil {
+        respondWithInvalidParam(w, r, strings.ToLower(owner.name)+" ID", err)
+        return 0, 0, false
+    }
+    if param := chi.URLParam(r, "attachmentID"); param != "" {
+        attachmentID, err = strconv.Atoi(param)
+        if err != nil {
+            respondWithInvalidParam(w, r, "attachment ID", err)
+            return 0, 0, false
+        }
+    }
+    return ownerID, attachmentID, true
+}
+
+// Responds with 503 and returns false when no blob store is configured
+func (h *Handler) attachmentsEnabled(w http.ResponseWriter, r *http.Request) bool {
+    if h.blobs != nil {
+        return true
+    }
+    slog.WarnContext(r.Context(), "Attachment request without a blob store")
+    models.RespondWithError(w, http.StatusServiceUnavailable, "Attachments are not configured")
+    return false
+}
+
+// Responds with 404 and returns false if the brother or event doesn't exist
+func (h *Handler) attachmentOwnerExists(w http.ResponseWriter, r *http.Request, owner attachmentOwner, ownerID int) bool {
+    if owner.column == store.EventAttachments {
+        return h.eventExists(w, r, ownerID)
+    }
+    return h.brotherExists(w, r, ownerID)
+}
+
+// Responds with 404 for an attachment that doesn't exist on the brother or event
+func respondWithAttachmentNotFound(w http.ResponseWriter, r *http.Request, owner attachmentOwner, ownerID int, attachmentID int) {
+    errMsg := fmt.Sprintf("Attachment %d not found for %s ID %d", attachmentID, strings.ToLower(owner.name), ownerID)
+    slog.InfoContext(r.Context(), errMsg)
+    models.RespondWithFail(w, http.StatusNotFound, errMsg)
+}
+
+// Respond to a file over the size limit
+func respondWithFileTooLarge(w http.ResponseWriter, r *http.Request, maxSize int64) {
+    slog.InfoContext(r.Context(), "Attachment over the size limit", "max_size", maxSize)
+    models.RespondWithFail(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("file must be at most %d bytes", maxSize))
+}
+
+// Responds with 415 for an upload whose detected type isn't allowed
+func respondWithUnsupportedType(w http.ResponseWriter, r *http.Request, detected *mimetype.MIME, allowed []string) {
+    slog.InfoContext(r.Context(), "Attachment type not allowed", "content_type", detected.String())
+    models.RespondWithFail(w, http.StatusUnsupportedMediaType, fmt.Sprintf("file type %s is not allowed. Allowed types: %s", detected.String(), strings.Join(allowed, ", ")))
+}
+
+// Name to store for an uploaded file: the last path element without control characters or quotes
+func attachmentFileName(name string, detected *mimetype.MIME) string {
+    name = name[strings.LastIndexAny(name, `/\`)+1:]
+    name = strings.TrimSpace(strings.Map(func(c rune) rune {
+        if unicode.IsControl(c) || c == '"' {
+            return -1
+        }
+        return c
+    }, name))
+    if name == "" || name == "." || name == ".." {
+        name = "attachment" + detected.Extension()
+    }
+    if runes := []rune(name); len(runes) > 255 {
+        name = string(runes[:255])
+    }
+    return name
+}
+
+// Random blob key below the owner's prefix, e.g. "events/12/9f86d081884c7d65..."
+func attachmentBlobKey(owner attachmentOwner, ownerID int) (string, error) {
+    id := make([]byte, 16)
+    if _, err := rand.Read(id); err != nil {
+        return "", err
+    }
+    return fmt.Sprintf("%s/%d/%s", owner.path, ownerID, hex.EncodeToString(id)), nil
+}
+
+// Reader that fails once more than limit bytes are read
+type sizeLimitReader struct {
+    r        io.Reader
+    limit    int64
+    read     int64
+    exceeded bool
+}
+
+func (l *sizeLimitReader) Read(p []byte) (int, error) {
+    n, err := l.r.Read(p)
+    l.read += int64(n)
+    if l.read > l.limit {
+        l.exceeded = true
+        return n, errors.New("file is over the size limit")
+    }
+    return n, err
+}
+
+func (h *Handler) listAttachments(w http.ResponseWriter, r *http.Request, owner attachmentOwner) {
+    ownerID, _, ok := attachmentParams(w, r, owner)
+    if !ok || !h.attachmentOwnerExists(w, r, owner, ownerID) {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    attachments, err := store.ListAttachments(ctx, h.db, owner.column, ownerID)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while querying attachments")
+        return
+    }
+    models.RespondWithSuccess(w, http.StatusOK, attachments)
+}
+
+// Streams the "file" part of a multipart body to the blob store, then records it.
+// The content type is detected from the file's first bytes; the client's is ignored
+func (h *Handler) uploadAttachment(w http.ResponseWriter, r *http.Request, owner attachmentOwner) {
+    ownerID, _, ok := attachmentParams(w, r, owner)
+    if !ok || !h.attachmentsEnabled(w, r) || !h.attachmentOwnerExists(w, r, owner, ownerID) {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    r.Body = http.MaxBytesReader(w, r.Body, h.attachments.MaxSize+multipartOverhead)
+    reader, err := r.MultipartReader()
+    if err != nil {
+        slog.InfoContext(r.Context(), "Upload is not multipart", "error", err)
+        models.RespondWithFailCode(w, http.StatusBadRequest, models.CodeInvalidBody, "Request body must be multipart/form-data with a file field")
+        return
+    }
+    var part io.Reader
+    var fileName string
+    for {
+        p, err := reader.NextPart()
+        if err == io.EOF {
+            respondWithFieldError(w, r, "file", "required", "file is required")
+            return
+        }
+        var maxBytesErr *http.MaxBytesError
+        if errors.As(err, &maxBytesErr) {
+            respondWithFileTooLarge(w, r, h.attachments.MaxSize)
+            return
+        }
+        if err != nil {
+            slog.InfoContext(r.Context(), "Invalid multipart body", "error", err)
+            models.RespondWithFailCode(w, http.StatusBadRequest, models.CodeInvalidBody, "Request body must be multipart/form-data with a file field")
+            return
+        }
+        if p.FormName() == "file" {
+            part, fileName = p, p.FileName()
+            break
+        }
+    }
+
+    limited := &sizeLimitReader{r: part, limit: h.attachments.MaxSize}
+    head := make([]byte, sniffLength)
+    n, err := io.ReadFull(limited, head)
+    if limited.exceeded {
+        respondWithFileTooLarge(w, r, h.attachments.MaxSize)
+        return
+    }
+    if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+        respondWithInternalError(w, r, err, "Error while reading upload")
+        return
+    }
+    if n == 0 {
+        respondWithFieldError(w, r, "file", "required", "file must not be empty")
+        return
+    }
+    head = head[:n]
+    detected := mimetype.Detect(head)
+    if !allowedType(h.attachments, detected) {
+        respondWithUnsupportedType(w, r, detected, h.attachments.AllowedTypes)
+        return
+    }
+
+    key, err := attachmentBlobKey(owner, ownerID)
+    if err != nil {
+        respondWithInternalError(w, r, err, "Error while generating blob key")
+        return
+    }
+    content := io.MultiReader(bytes.NewReader(head), limited)
+    if err := h.blobs.Put(ctx, key, content, -1, detected.String()); err != nil {
+        h.blobs.Delete(ctx, key)
+        if limited.exceeded {
+            respondWithFileTooLarge(w, r, h.attachments.MaxSize)
+            return
+        }
+        respondWithInternalError(w, r, err, "Error while storing attachment")
+        return
+    }
+
+    attachment := models.Attachment{
+        FileName:    attachmentFileName(fileName, detected),
+        ContentType: detected.String(),
+        Size:        limited.read,
+        BlobKey:     key,
+    }
+    if user, ok := apimiddleware.UserFromContext(r.Context()); ok {
+        attachment.UploadedBy = user.Email
+    }
+    attachment, err = store.InsertAttachment(ctx, h.db, owner.column, ownerID, attachment)
+    if err != nil {
+        if err := h.blobs.Delete(ctx, key); err != nil {
+            slog.ErrorContext(r.Context(), "Error while removing unrecorded attachment", "blob_key", key, "error", err)
+        }
+        respondWithDBError(w, r, err, "Error while inserting attachment")
+        return
+    }
+    slog.InfoContext(r.Context(), "Stored attachment", "attachment_id", attachment.AttachmentID, "content_type", attachment.ContentType, "size", attachment.Size)
+
+    location := fmt.Sprintf("/api/%s/%d/attachments/%d", owner.path, ownerID, attachment.AttachmentID)
+    models.RespondWithCreated(w, location, attachment)
+}
+
+// Streams the contents of an attachment as a download
+func (h *Handler) downloadAttachment(w http.ResponseWriter, r *http.Request, owner attachmentOwner) {
+    ownerID, attachmentID, ok := attachmentParams(w, r, owner)
+    if !ok || !h.attachmentsEnabled(w, r) {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    attachment, err := store.GetAttachment(ctx, h.db, owner.column, ownerID, attachmentID)
+    if errors.Is(err, sql.ErrNoRows) {
+        respondWithAttachmentNotFound(w, r, owner, ownerID, attachmentID)
+        return
+    }
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while querying attachment")
+        return
+    }
+
+    content, err := h.blobs.Get(ctx, attachment.BlobKey)
+    if errors.Is(err, blob.ErrNotFound) {
+        slog.ErrorContext(r.Context(), "Attachment contents are missing from the blob store", "attachment_id", attachmentID, "blob_key", attachment.BlobKey)
+        models.RespondWithFail(w, http.StatusNotFound, "Attachment contents are missing")
+        return
+    }
+    if err != nil {
+        respondWithInternalError(w, r, err, "Error while reading attachment")
+        return
+    }
+    defer content.Close()
+
+    // Always download instead of rendering, so uploaded files can't run in the API's origin
+    w.Header().Set("Content-Type", attachment.ContentType)
+    w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+    w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+    w.Header().Set("X-Content-Type-Options", "nosniff")
+    w.WriteHeader(http.StatusOK)
+    if _, err := io.Copy(w, content); err != nil {
+        slog.WarnContext(r.Context(), "Attachment download interrupted", "attachment_id", attachmentID, "error", err)
+    }
+}
+
+// Deletes an attachment's record, then its contents
+func (h *Handler) deleteAttachment(w http.ResponseWriter, r *http.Request, owner attachmentOwner) {
+    ownerID, attachmentID, ok := attachmentParams(w, r, owner)
+    if !ok || !h.attachmentsEnabled(w, r) {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    attachment, err := store.DeleteAttachment(ctx, h.db, owner.column, ownerID, attachmentID)
+    if errors.Is(err, sql.ErrNoRows) {
+        respondWithAttachmentNotFound(w, r, owner, ownerID, attachmentID)
+        return
+    }
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while deleting attachment")
+        return
+    }
+    // The record is gone either way; a leftover blob is only wasted space
+    if err := h.blobs.Delete(ctx, attachment.BlobKey); err != nil {
+        slog.ErrorContext(r.Context(), "Error while removing attachment contents", "blob_key", attachment.BlobKey, "error", err)
+    }
+
+    slog.InfoContext(r.Context(), "Deleted attachment", "attachment_id", attachmentID)
+    w.WriteHeader(http.StatusNoContent)
+}
+
+// GET /api/brothers/{id}/attachments
+//	@Summary		Get attachments of a Brother
+//	@Description	Get the files attached to a Brother (headshots, resumes...), newest first
+//	@Tags			Attachments
+//	@Produce		json
+//	@Param			id		path		int		true	"Brother ID"
+//	@Success		200		{object}	models.APIResponse{data=[]models.Attachment}
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		404		{object}	models.APIResponse
+//	@Router			/api/brothers/{id}/attachments [get]
+func (h *Handler) GetBrotherAttachments(w http.ResponseWriter, r *http.Request) {
+    h.listAttachments(w, r, brotherAttachments)
+}
+
+// POST /api/brothers/{id}/attachments
+//	@Summary		Attach a file to a Brother
+//	@Description	Upload a file as multipart/form-data in the "file" field. Its type is detected from its contents and must be one of ATTACHMENTS_ALLOWED_TYPES
+//	@Tags			Attachments
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			id		path		int		true	"Brother ID"
+//	@Param			file	formData	file	true	"File to attach"
+//	@Success		201		{object}	models.APIResponse{data=models.Attachment}
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		404		{object}	models.APIResponse
+//	@Failure		413		{object}	models.APIResponse
+//	@Failure		415		{object}	models.APIResponse
+//	@Failure		422		{object}	models.APIResponse
+//	@Failure		503		{object}	models.APIResponse
+//	@Router			/api/brothers/{id}/attachments [post]
+func (h *Handler) UploadBrotherAttachment(w http.ResponseWriter, r *http.Request) {
+    h.uploadAttachment(w, r, brotherAttachments)
+}
+
+// GET /api/brothers/{id}/attachments/{attachmentID}
+//	@Summary		Download an attachment of a Brother
+//	@Tags			Attachments
+//	@Produce		octet-stream
+//	@Param			id				path		int		true	"Brother ID"
+//	@Param			attachmentID	path		int		true	"Attachment ID"
+//	@Success		200				{file}		file
+//	@Failure		404				{object}	models.APIResponse
+//	@Router			/api/brothers/{id}/attachments/{attachmentID} [get]
+func (h *Handler) DownloadBrotherAttachment(w http.ResponseWriter, r *http.Request) {
+    h.downloadAttachment(w, r, brotherAttachments)
+}
+
+// DELETE /api/brothers/{id}/attachments/{attachmentID}
+//	@Summary		Delete an attachment of a Brother
+//	@Tags			Attachments
+//	@Param			id				path		int		true	"Brother ID"
+//	@Param			attachmentID	path		int		true	"Attachment ID"
+//	@Success		204
+//	@Failure		404				{object}	models.APIResponse
+//	@Router			/api/brothers/{id}/attachments/{attachmentID} [delete]
+func (h *Handler) DeleteBrotherAttachment(w http.ResponseWriter, r *http.Request) {
+    h.deleteAttachment(w, r, brotherAttachments)
+}
+
+// GET /api/events/{eventID}/attachments
+//	@Summary		Get attachments of an Event
+//	@Description	Get the files attached to an Event (sign-in sheets, photos...), newest first
+//	@Tags			Attachments
+//	@Produce		json
+//	@Param			eventID	path		int		true	"Event ID"
+//	@Success		200		{object}	models.APIResponse{data=[]models.Attachment}
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		404		{object}	models.APIResponse
+//	@Router			/api/events/{eventID}/attachments [get]
+func (h *Handler) GetEventAttachments(w http.ResponseWriter, r *http.Request) {
+    h.listAttachments(w, r, eventAttachments)
+}
+
+// POST /api/events/{eventID}/attachments
+//	@Summary		Attach a file to an Event
+//	@Description	Upload a file as multipart/form-data in the "file" field. Its type is detected from its contents and must be one of ATTACHMENTS_ALLOWED_TYPES
+//	@Tags			Attachments
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			eventID	path		int		true	"Event ID"
+//	@Param			file	formData	file	true	"File to attach"
+//	@Success		201		{object}	models.APIResponse{data=models.Attachment}
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		404		{object}	models.APIResponse
+//	@Failure		413		{object}	models.APIResponse
+//	@Failure		415		{object}	models.APIResponse
+//	@Failure		422		{object}	models.APIResponse
+//	@Failure		503		{object}	models.APIResponse
+//	@Router			/api/events/{eventID}/attachments [post]
+func (h *Handler) UploadEventAttachment(w http.ResponseWriter, r *http.Request) {
+    h.uploadAttachment(w, r, eventAttachments)
+}
+
+// GET /api/events/{eventID}/attachments/{attachmentID}
+//	@Summary		Download an attachment of an Event
+//	@Tags			Attachments
+//	@Produce		octet-stream
+//	@Param			eventID			path		int		true	"Event ID"
+//	@Param			attachmentID	path		int		true	"Attachment ID"
+//	@Success		200				{file}		file
+//	@Failure		404				{object}	models.APIResponse
+//	@Router			/api/events/{eventID}/attachments/{attachmentID} [get]
+func (h *Handler) DownloadEventAttachment(w http.ResponseWriter, r *http.Request) {
+    h.downloadAttachment(w, r, eventAttachments)
+}
+
+// DELETE /api/events/{eventID}/attachments/{attachmentID}
+//	@Summary		Delete an attachment of an Event
+//	@Tags			Attachments
+//	@Param			eventID			path		int		true	"Event ID"
+//	@Param			attachmentID	path		int		true	"Attachment ID"
+//	@Success		204
+//	@Failure		404				{object}	models.APIResponse
+//	@Router			/api/events/{eventID}/attachments/{attachmentID} [delete]
+func (h *Handler) DeleteEventAttachment(w http.ResponseWriter, r *http.Request) {
+    h.deleteAttachment(w, r, eventAttachments)
+}
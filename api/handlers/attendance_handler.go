@@ -5,40 +5,22 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
     "strconv"
 
 	"github.com/go-chi/chi"
-	"github.com/go-playground/validator/v10"
 	"github.com/pacific-theta-tau/tt-db/api/models"
+	"github.com/pacific-theta-tau/tt-db/db"
+	"github.com/pacific-theta-tau/tt-db/logging"
+	"github.com/pacific-theta-tau/tt-db/store"
 )
 
 
 const ATTENDANCE_TABLE = "attendance"
 
-// Create a Attendance struct from row data
-func createAttendanceFromRow(row *sql.Rows) (models.Attendance, error) {
-    var attendance models.Attendance
-    err := row.Scan(
-        &attendance.BrotherID,
-        &attendance.EventID,
-        &attendance.AttendanceStatus,
-        &attendance.RollCall,
-        &attendance.FirstName,
-        &attendance.LastName,
-        &attendance.EventName,
-        &attendance.EventLocation,
-        &attendance.EventDate,
-        &attendance.EventCategory,
-    )
-    if err != nil {
-        return models.Attendance{}, err
-    }
-
-    return attendance, err
-}
-
+// Message for attendance statuses not found in models.AttendanceStatus
+const validAttendanceStatusMessage = "attendanceStatus must be one of: Present, Absent, Excused"
 
 // GET /api/attendance
 //	@Summary		Get all attendance records
@@ -48,37 +30,34 @@ func createAttendanceFromRow(row *sql.Rows) (models.Attendance, error) {
 //	@Failure		400		{object}	models.APIResponse
 //	@Router			/api/attendance [get]
 func (h *Handler) GetAllAttendanceRecords(w http.ResponseWriter, r *http.Request) {
-	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	ctx, cancel := requestContext(r)
 	defer cancel()
 
 	query := `
-    SELECT a.brotherID, a.eventID, a.attendanceStatus, b.rollCall, b.FirstName, b.LastName, e.EventName, e.eventLocation, e.eventDate, ec.categoryName
+    SELECT a.brotherID, a.eventID, a.attendanceStatus, b.rollCall, b.FirstName, b.LastName, e.EventName, e.eventLocation, e.eventDate, COALESCE(ec.categoryName, '')
     FROM attendance a
     JOIN brothers b ON b.brotherID = a.brotherID
     JOIN events e ON e.eventID = a.eventID
-    JOIN eventsCategory ec ON ec.categoryID = e.categoryID
+    LEFT JOIN eventsCategory ec ON ec.categoryID = e.categoryID
     `
 	rows, err := h.db.QueryContext(ctx, query)
 	if err != nil {
-        errMsg := fmt.Sprintf("Error while querying for Attendance records: %s", err.Error())
-        log.Print(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDBError(w, r, err, "Error while querying for Attendance records")
 		return
 	}
 
 	// Read rows from query to create Brother instances
     var attendance []*models.Attendance
 	for rows.Next() {
-		record, err := createAttendanceFromRow(rows)
+		record, err := store.ScanAttendance(rows)
 		if err != nil {
-            errMsg := fmt.Sprintf("Error while parsing Attendance records: %s", err.Error())
-            models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+            respondWithInternalError(w, r, err, "Error while parsing Attendance records")
 			return
 		}
         attendance = append(attendance, &record)
 	}
 
-    log.Println("\tQuery Successful")
+    slog.DebugContext(r.Context(), "Query successful")
     data := attendance
 
 	// Build HTTP response
@@ -106,46 +85,40 @@ func (h *Handler) GetAttendanceFromEventID(w http.ResponseWriter, r *http.Reques
     eventIDStr := chi.URLParam(r, "eventID")
     eventID, err := strconv.Atoi(eventIDStr)
     if err != nil {
-        errMsg := "Invalid event ID"
-        log.Printf(errMsg, err)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        respondWithInvalidParam(w, r, "event ID", err)
         return
     }
 
-    ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+    ctx, cancel := requestContext(r)
     defer cancel()
 
 	query := `
-    SELECT a.brotherID, a.eventID, a.attendanceStatus, b.rollCall, b.FirstName, b.LastName, e.EventName, e.eventLocation, e.eventDate, ec.categoryName
+    SELECT a.brotherID, a.eventID, a.attendanceStatus, b.rollCall, b.FirstName, b.LastName, e.EventName, e.eventLocation, e.eventDate, COALESCE(ec.categoryName, '')
     FROM attendance a
     JOIN brothers b ON b.brotherID = a.brotherID
     JOIN events e ON e.eventID = a.eventID
-    JOIN eventsCategory ec ON ec.categoryID = e.categoryID
+    LEFT JOIN eventsCategory ec ON ec.categoryID = e.categoryID
     WHERE a.eventID = $1
     `
-    log.Printf("\tEventID: %d", eventID)
+    slog.DebugContext(r.Context(), "Parsed event ID", "eventID", eventID)
     rows, err := h.db.QueryContext(ctx, query, eventID)
 	if err != nil {
-        errMsg := fmt.Sprintf("Error while querying for Attendance Record: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDBError(w, r, err, "Error while querying for Attendance Record")
 		return
 	}
 
 	// Read rows from query to create Brother instances
     var attendance []*models.Attendance
 	for rows.Next() {
-		record, err := createAttendanceFromRow(rows)
+		record, err := store.ScanAttendance(rows)
 		if err != nil {
-            errMsg := fmt.Sprintf("Error while parsing Attendance Record: %s", err.Error())
-            log.Println(errMsg)
-            models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+            respondWithInternalError(w, r, err, "Error while parsing Attendance Record")
 			return
 		}
         attendance = append(attendance, &record)
 	}
 
-    log.Println("\tQuery Successful")
+    slog.DebugContext(r.Context(), "Query successful")
     data := attendance
 
 	// Build HTTP response
@@ -157,11 +130,13 @@ func (h *Handler) GetAttendanceFromEventID(w http.ResponseWriter, r *http.Reques
 //	@Summary		Create attendance record
 //	@Description	Create attendance record
 //	@Tags		    Attendance	
-//	@Success		200		object		models.APIResponse
+//	@Success		201		object		models.APIResponse{data=models.Attendance}
 //	@Failure		400		{object}	models.APIResponse
+//	@Failure		409		{object}	models.APIResponse
+//	@Failure		422		{object}	models.APIResponse
 //	@Router			/api/attendance [post]
 func (h *Handler) CreateAttendance(w http.ResponseWriter, r *http.Request) {
-    ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+    ctx, cancel := requestContext(r)
     defer cancel()
         
     // Expected input in request body
@@ -172,52 +147,47 @@ func (h *Handler) CreateAttendance(w http.ResponseWriter, r *http.Request) {
     }
     err := json.NewDecoder(r.Body).Decode(&input)
     if err != nil {
-        errMsg := fmt.Sprintf("Error while parsing request body params: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        respondWithDecodeError(w, r, err)
         return
     }
 
     // Validate data provided in request body
-    validate := validator.New()
 	if err := validate.Struct(input); err != nil {
-        errMsg := fmt.Sprintf("Invalid body params: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        respondWithValidationError(w, r, err)
 		return
 	}
 
-    log.Printf("\tRequest Body: %+v", input)
+    slog.DebugContext(r.Context(), "Request body", "body", logging.Body(input))
 
     // Check for missing or zero values
 	if input.BrotherID == 0 || input.EventID == 0 {
-        errMsg := fmt.Sprintf("Missing brotherID or eventID", err.Error())
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        respondWithFieldError(w, r, "brotherID", "required", "brotherID and eventID are required")
 		return
 	}
 
     query := `
-    INSERT INTO attendance (brotherID, eventID, attendanceStatus)
-    VALUES ($1, $2, $3)
-    RETURNING *
-    `
+    WITH inserted AS (
+        INSERT INTO attendance (brotherID, eventID, attendanceStatus)
+        VALUES ($1, $2, $3)
+        RETURNING brotherID, eventID, attendanceStatus
+    )
+    ` + fmt.Sprintf(store.AttendanceRecordQuery, "inserted")
     
-    _, err = h.db.ExecContext(
+    row := h.db.QueryRowContext(
         ctx,
         query,
-        &input.BrotherID,
-        &input.EventID,
-        &input.AttendanceStatus,
+        input.BrotherID,
+        input.EventID,
+        input.AttendanceStatus,
     )
+    record, err := store.ScanAttendance(row)
     if err != nil {
-        errMsg := fmt.Sprintf("Error while inserting attendance record to table: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDBError(w, r, err, "Error while inserting attendance record to table")
 		return
 	}
 
-    models.RespondWithSuccess(w, http.StatusCreated, "")
+    location := fmt.Sprintf("/api/events/%d/attendance", record.EventID)
+    models.RespondWithCreated(w, location, record)
 }
 
 
@@ -228,7 +198,7 @@ func (h *Handler) CreateAttendance(w http.ResponseWriter, r *http.Request) {
 //	@Failure		400		{object}	models.APIResponse
 //	@Router			/api/attendance [delete]
 func (h *Handler) DeleteAttendanceRecord(w http.ResponseWriter, r *http.Request) {
-    ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+    ctx, cancel := requestContext(r)
     defer cancel()
 
     // Expected data in request body
@@ -238,27 +208,20 @@ func (h *Handler) DeleteAttendanceRecord(w http.ResponseWriter, r *http.Request)
     }   
     err := json.NewDecoder(r.Body).Decode(&requestBody)
     if err != nil {
-        errMsg := fmt.Sprintf("Error while parsing request body params: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        respondWithDecodeError(w, r, err)
         return
     }
     // Validate data provided in request body
-    validate := validator.New()
 	if err := validate.Struct(requestBody); err != nil {
-        errMsg := fmt.Sprintf("Invalid or missing params in request body params: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        respondWithValidationError(w, r, err)
 		return
 	}
 
-    log.Printf("\tRequest Body: %+v", requestBody)
+    slog.DebugContext(r.Context(), "Request body", "body", logging.Body(requestBody))
 
     // Check for missing or zero values
 	if requestBody.BrotherID == 0 || requestBody.EventID == 0 {
-        errMsg := "Missing BrotherID and/or EventID in request body params"
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        respondWithFieldError(w, r, "brotherID", "required", "brotherID and eventID are required")
 		return
 	}
 
@@ -273,9 +236,7 @@ func (h *Handler) DeleteAttendanceRecord(w http.ResponseWriter, r *http.Request)
         requestBody.EventID,
     )
     if err != nil {
-        errMsg := fmt.Sprintf("Error while deleting attendance record: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDBError(w, r, err, "Error while deleting attendance record")
 		return
 	}
 
@@ -286,11 +247,11 @@ func (h *Handler) DeleteAttendanceRecord(w http.ResponseWriter, r *http.Request)
 //	@Summary		Update attendance record
 //	@Description	Update attendance record
 //	@Tags		    Attendance	
-//	@Success		200		object		models.APIResponse
+//	@Success		200		object		models.APIResponse{data=models.Attendance}
 //	@Failure		400		{object}	models.APIResponse
 //	@Router			/api/attendance [put]
 func (h *Handler) UpdateAttendanceRecord(w http.ResponseWriter, r *http.Request) {
-    ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+    ctx, cancel := requestContext(r)
     defer cancel()
 
     var requestBody struct {
@@ -302,59 +263,42 @@ func (h *Handler) UpdateAttendanceRecord(w http.ResponseWriter, r *http.Request)
     // Unmarshal request body data
     err := json.NewDecoder(r.Body).Decode(&requestBody)
     if err != nil {
-        errMsg := fmt.Sprintf("Failed to parse request body params: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusBadRequest, errMsg)
+        respondWithDecodeError(w, r, err)
         return
     }
 
     // Validate data provided in request body
-    validate := validator.New()
 	if err := validate.Struct(requestBody); err != nil {
-        errMsg := fmt.Sprintf("Invalid request body params: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        respondWithValidationError(w, r, err)
 		return
 	}
 
-    log.Printf("\tRequest Body: %+v", requestBody)
+    slog.DebugContext(r.Context(), "Request body", "body", logging.Body(requestBody))
 
     // Check for missing or zero values
 	if requestBody.BrotherID == 0 || requestBody.EventID == 0 {
-        errMsg := fmt.Sprintf("Invalid brotherID or eventID", err.Error())
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        respondWithFieldError(w, r, "brotherID", "required", "brotherID and eventID are required")
 		return
 	}
     // validate attendance status
     _, ok := models.AttendanceStatus[requestBody.AttendanceStatus]; if !ok {
-        // TODO: print valid statues dynamically instead of hardcoding
-        errMsg := "Invalid attendance status. Must be one of: 'present', 'absent', or 'excused'"
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        respondWithFieldError(w, r, "attendanceStatus", "oneof", validAttendanceStatusMessage)
 		return
     }
 
-    query := `
-    UPDATE attendance
-    SET attendanceStatus = $1
-    WHERE brotherID = $2 AND eventID = $3
-    `
-    _, err = h.db.QueryContext(
-        ctx,
-        query,
-        requestBody.AttendanceStatus,
-        requestBody.BrotherID,
-        requestBody.EventID,
-    )
+    record, err := updateAttendanceStatus(ctx, h.db, requestBody.BrotherID, requestBody.EventID, requestBody.AttendanceStatus)
+    if err == sql.ErrNoRows {
+        errMsg := fmt.Sprintf("No attendance record found for brotherID %d and eventID %d", requestBody.BrotherID, requestBody.EventID)
+        slog.InfoContext(r.Context(), errMsg)
+        models.RespondWithFail(w, http.StatusNotFound, errMsg)
+        return
+    }
     if err != nil {
-        errMsg := fmt.Sprintf("Error while updating attendance record: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDBError(w, r, err, "Error while updating attendance record")
 		return
 	}
 
-    models.RespondWithSuccess(w, http.StatusOK, "")
+    models.RespondWithSuccess(w, http.StatusOK, record)
 }
 
 
@@ -362,26 +306,22 @@ func (h *Handler) UpdateAttendanceRecord(w http.ResponseWriter, r *http.Request)
 //	@Description	Update attendance using specific resource endpoint
 //	@Tags		    Attendance
 //  @Param          eventID      path        string true "EventID"
-//	@Success		200		object		models.APIResponse
+//	@Success		200		object		models.APIResponse{data=models.Attendance}
 //	@Failure		400		{object}	models.APIResponse
 //	@Router			/api/events/{eventID}/attendance [patch]
 func (h *Handler) UpdateAttendanceByEventID(w http.ResponseWriter, r *http.Request) {
-    ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+    ctx, cancel := requestContext(r)
     defer cancel()
 
     // Parse url params
     eventID := chi.URLParam(r, "eventID")
     if eventID == "" {
-        errMsg := fmt.Sprintf("Missing eventID in url params.")
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusBadRequest, errMsg)
+        respondWithInvalidParam(w, r, "event ID", fmt.Errorf("missing eventID"))
         return
     }
     eventIDInt, err := strconv.Atoi(eventID)
     if err != nil {
-        errMsg := fmt.Sprintf("Failed to convert eventID to int. eventID: %s; Error: %s", eventID, err.Error())
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusInternalServerError, errMsg)
+        respondWithInvalidParam(w, r, "event ID", err)
         return
     }
 
@@ -393,61 +333,56 @@ func (h *Handler) UpdateAttendanceByEventID(w http.ResponseWriter, r *http.Reque
 
     err = json.NewDecoder(r.Body).Decode(&requestBody)
     if err != nil {
-        errMsg := fmt.Sprintf("Failed to parse request body params: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusBadRequest, errMsg)
+        respondWithDecodeError(w, r, err)
         return
     }
 
     // Validate data provided in request body
-    validate := validator.New()
 	if err := validate.Struct(requestBody); err != nil {
-        errMsg := fmt.Sprintf("Invalid request body params: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        respondWithValidationError(w, r, err)
 		return
 	}
 
-    log.Printf("\tRequest Body: %+v", requestBody)
+    slog.DebugContext(r.Context(), "Request body", "body", logging.Body(requestBody))
 
     // Check for missing or zero values
 	if requestBody.BrotherID == 0 || eventIDInt == 0 {
-        errMsg := fmt.Sprintf("Invalid brotherID or eventID", err.Error())
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        respondWithFieldError(w, r, "brotherID", "required", "brotherID is required")
 		return
 	}
 
     // validate attendance status
     _, ok := models.AttendanceStatus[requestBody.AttendanceStatus]; if !ok {
-        // TODO: print valid statues dynamically instead of hardcoding
-        errMsg := "Invalid attendance status. Must be one of: 'present', 'absent', or 'excused'"
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        respondWithFieldError(w, r, "attendanceStatus", "oneof", validAttendanceStatusMessage)
 		return
     }
 
     // Query database
-    query := `
-    UPDATE attendance
-    SET attendanceStatus = $1
-    WHERE brotherID = $2 AND eventID = $3
-    `
-    log.Println("Query:\n", query)
-    _, err = h.db.QueryContext(
-        ctx,
-        query,
-        requestBody.AttendanceStatus,
-        requestBody.BrotherID,
-        eventIDInt,
-    )
+    record, err := updateAttendanceStatus(ctx, h.db, requestBody.BrotherID, eventIDInt, requestBody.AttendanceStatus)
+    if err == sql.ErrNoRows {
+        errMsg := fmt.Sprintf("No attendance record found for brotherID %d and eventID %d", requestBody.BrotherID, eventIDInt)
+        slog.InfoContext(r.Context(), errMsg)
+        models.RespondWithFail(w, http.StatusNotFound, errMsg)
+        return
+    }
     if err != nil {
-        errMsg := fmt.Sprintf("Error while updating attendance record: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDBError(w, r, err, "Error while updating attendance record")
 		return
 	}
 
-    // TODO: return updated row
-    models.RespondWithSuccess(w, http.StatusOK, "")
+    models.RespondWithSuccess(w, http.StatusOK, record)
+}
+
+// Helper function to update the status of an attendance record and return the updated record.
+// Returns sql.ErrNoRows if there is no record for the brother and event
+func updateAttendanceStatus(ctx context.Context, conn *db.DB, brotherID int, eventID int, status string) (models.Attendance, error) {
+    query := `
+    WITH updated AS (
+        UPDATE attendance
+        SET attendanceStatus = $1
+        WHERE brotherID = $2 AND eventID = $3
+        RETURNING brotherID, eventID, attendanceStatus
+    )
+    ` + fmt.Sprintf(store.AttendanceRecordQuery, "updated")
+    return store.ScanAttendance(conn.QueryRowContext(ctx, query, status, brotherID, eventID))
 }
@@ -0,0 +1,33 @@
+// auth_handler.go: Handle sign in requests
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	apimiddleware "github.com/pacific-theta-tau/tt-db/api/middleware"
+	"github.com/pacific-theta-tau/tt-db/api/models"
+)
+
+// POST /api/login
+//	@Summary		Sign in
+//	@Description	Check Basic credentials (email and password) and return the signed in user. Every other endpoint takes the same credentials, so clients use this to check them before storing them.
+//	@Description	Clients are locked out with 429 after repeated failed sign ins (LOGIN_MAX_FAILURES within LOGIN_FAILURE_WINDOW)
+//	@Tags			Auth
+//	@Produce		json
+//	@Success		200		{object}	models.APIResponse{data=models.User}
+//	@Failure		401		{object}	models.APIResponse
+//	@Failure		429		{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/login [post]
+func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
+    // apimiddleware.Authenticate has already rejected wrong credentials
+    user, ok := apimiddleware.UserFromContext(r.Context())
+    if !ok {
+        apimiddleware.RespondUnauthorized(w, "Sign in with your email and password")
+        return
+    }
+    slog.InfoContext(r.Context(), "Signed in", "user_id", user.UserID)
+
+    models.RespondWithSuccess(w, http.StatusOK, user)
+}
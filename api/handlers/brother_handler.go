@@ -2,83 +2,69 @@
 package handlers
 
 import (
-	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
-	"time"
     "strconv"
+    "strings"
 
 	"github.com/go-chi/chi"
-	"github.com/go-playground/validator/v10"
+	apimiddleware "github.com/pacific-theta-tau/tt-db/api/middleware"
 	"github.com/pacific-theta-tau/tt-db/api/models"
+	"github.com/pacific-theta-tau/tt-db/logging"
+	"github.com/pacific-theta-tau/tt-db/store"
 )
 
 
 const brothers_table = "brothers"
 
-// Helper function to scan SQL row and create new Brother instance
-// TODO: move this to models/brother.go
-func createBrotherFromRow(row *sql.Rows) (models.Brother, error) {
-	var brother models.Brother
-	err := row.Scan(
-        &brother.BrotherID,
-		&brother.RollCall,
-		&brother.FirstName,
-		&brother.LastName,
-		&brother.Major,
-		&brother.Status,
-		&brother.Class,
-		&brother.Email,
-		&brother.PhoneNumber,
-		&brother.BadStanding,
-	)
-	if err != nil {
-		return models.Brother{}, err
-	}
-
-	return brother, err
-}
-
 //	@Summary		Get all Brothers data
-//	@Description	Get data from all Brother records in `Brothers` table
+//	@Description	Get data from all Brother records in `Brothers` table. Filter on custom fields with `cf.<name>=value`, and bound number and date fields with `cf.<name>.min` and `cf.<name>.max`
 //	@Tags			Brothers
+//	@Param			tag		query		[]string	false	"Only brothers with every one of these tags"	collectionFormat(multi)
+//	@Param			cf.name	query		string	false	"Custom field filter, e.g. cf.shirtSize=M or cf.gradYear.min=2025"
 //	@Success		200		object		models.APIResponse{data=models.Brother}
 //	@Failure		400		{object}	models.APIResponse
 //	@Router			/api/brothers [get]
 func (h *Handler) GetAllBrothers(w http.ResponseWriter, r *http.Request) {
-	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	ctx, cancel := requestContext(r)
 	defer cancel()
 
-	// TODO: explicitly type columns
-	query := "SELECT * FROM " + brothers_table
-    log.Printf("Query:\n%s", query)
-	rows, err := h.db.QueryContext(ctx, query)
+    filter := store.BrotherFilter{Tags: r.URL.Query()["tag"]}
+    if hasCustomFieldFilters(r) {
+        fields, err := store.CustomFields(ctx, h.db)
+        if err != nil {
+            respondWithDBError(w, r, err, "Error while querying custom fields")
+            return
+        }
+        filter.CustomFields, err = store.ParseCustomFieldFilters(fields, r.URL.Query())
+        if err != nil {
+            slog.InfoContext(r.Context(), "Invalid custom field filter", "error", err)
+            models.RespondWithFailCode(w, http.StatusBadRequest, models.CodeInvalidParameter, err.Error())
+            return
+        }
+    }
+
+	brothers, err := store.ListBrothers(ctx, h.db, filter)
 	if err != nil {
-        errMsg := fmt.Sprintf("Error while querying rows in Brother's table: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDBError(w, r, err, "Error while querying rows in Brother's table")
 		return
 	}
 
-	// Read rows from query to create Brother instances
-	var brothers []*models.Brother
-	for rows.Next() {
-		brother, err := createBrotherFromRow(rows)
-        if err != nil {
-            errMsg := fmt.Sprintf("Error creating Brother object from row: '%s'\n", err.Error())
-			log.Println(errMsg)
-            models.RespondWithError(w, http.StatusInternalServerError, errMsg)
-			return
-		}
-		brothers = append(brothers, &brother)
-	}
+    models.RespondWithSuccess(w, http.StatusOK, brothers)
+}
 
-    data := brothers
-    models.RespondWithSuccess(w, http.StatusOK, data)
+// Reports whether the request filters brothers on custom fields, so definitions are only loaded when needed
+func hasCustomFieldFilters(r *http.Request) bool {
+    for param := range r.URL.Query() {
+        if strings.HasPrefix(param, "cf.") {
+            return true
+        }
+    }
+    return false
 }
 
 // Query brothers by ID
@@ -90,37 +76,30 @@ func (h *Handler) GetAllBrothers(w http.ResponseWriter, r *http.Request) {
 //	@Failure		400		{object}	models.APIResponse
 //	@Router			/api/brothers/{id} [get]
 func (h *Handler) GetBrotherByID(w http.ResponseWriter, r *http.Request) {
-    fmt.Println("\nGetBrrotherByID called")
     brotherIDStr := chi.URLParam(r, "id")
     brotherID, err := strconv.Atoi(brotherIDStr)
     if err != nil {
-        errMsg := fmt.Sprintf("Invalid brother ID: %v", err.Error())
-        log.Printf(errMsg)
-        models.RespondWithError(w, http.StatusBadRequest, errMsg)
+        respondWithInvalidParam(w, r, "brother ID", err)
         return
     }
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	ctx, cancel := requestContext(r)
 	defer cancel()
 
-    query := "SELECT * FROM brothers WHERE brotherID = $1"
-    log.Printf("Query:\n%s", query)
+    query := "SELECT " + store.BrotherColumns + " FROM brothers WHERE brotherID = $1"
+    slog.DebugContext(r.Context(), "Query", "sql", query)
     row, err := h.db.QueryContext(ctx, query, brotherID)
 	if err != nil {
-        errMsg := fmt.Sprintf("Erro while querying for Brother with ID %d: %s", brotherID, err.Error())
-		log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDBError(w, r, err, fmt.Sprintf("Error while querying for Brother with ID %d", brotherID))
 		return
 	}
 
     // Scan rows to create Brother instance
 	var brother models.Brother
 	for row.Next() {
-		brother, err = createBrotherFromRow(row)
+		brother, err = store.ScanBrother(row)
 		if err != nil {
-            errMsg := fmt.Sprintf("Erro while parsing rows: %s", err.Error())
-			log.Println(errMsg)
-            models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+            respondWithInternalError(w, r, err, "Error while parsing rows")
 			return
 		}
 	}
@@ -128,8 +107,8 @@ func (h *Handler) GetBrotherByID(w http.ResponseWriter, r *http.Request) {
     // postgres returns 0 if row not found
     if brother.BrotherID == 0 {
         errMsg := fmt.Sprintf("Brother ID %d not found", brotherID)
-        log.Printf(errMsg)
-        models.RespondWithError(w, http.StatusBadRequest, errMsg)
+        slog.InfoContext(r.Context(), errMsg)
+        models.RespondWithFail(w, http.StatusNotFound, errMsg)
         return
     }
 
@@ -140,59 +119,75 @@ func (h *Handler) GetBrotherByID(w http.ResponseWriter, r *http.Request) {
 
 // Add new brother entry to database
 //	@Summary		Create Brother record
-//	@Description	Create a new Brother record row for `Brothers` table
+//	@Description	Create a new Brother record row for `Brothers` table. The pledge class is set by classID, or by className, which creates the class if none has the name. rollCall must be in the class's roll call range
 //	@Tags			Brothers
 //	@Param			body_params body	models.Brother true	"Values for new record"
-//	@Success		200		object		models.APIResponse
+//	@Success		201		object		models.APIResponse{data=models.Brother}
 //	@Failure		400		{object}	models.APIResponse
+//	@Failure		409		{object}	models.APIResponse
+//	@Failure		422		{object}	models.APIResponse
 //	@Router			/api/brothers [post]
 func (h *Handler) AddBrother(w http.ResponseWriter, r *http.Request) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	ctx, cancel := requestContext(r)
 	defer cancel()
 
 	var brother models.Brother
 	err := json.NewDecoder(r.Body).Decode(&brother)
 	if err != nil {
-        errMsg := fmt.Sprint("Error decoding request body", err.Error())
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusInternalServerError, errMsg)
+        respondWithDecodeError(w, r, err)
         return
 	}
 
 	// Validate brothers struct
-	validate := validator.New()
 	if err := validate.Struct(brother); err != nil {
-        errMsg := fmt.Sprint("Error decoding request body: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        respondWithValidationError(w, r, err)
 		return
 	}
 
-	query := `
-	INSERT INTO brothers (rollCall, firstName, lastName, major, status, className, email, phoneNumber, badStanding)
-	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) returning *
-	`
-	_, err = h.db.ExecContext(
-		ctx,
-		query,
-		brother.RollCall,
-		brother.FirstName,
-		brother.LastName,
-		brother.Major,
-		brother.Status,
-		brother.Class,
-		brother.Email,
-		brother.PhoneNumber,
-		brother.BadStanding,
-	)
+    fields, err := store.CustomFields(ctx, h.db)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while querying custom fields")
+        return
+    }
+    customFields, fieldErrors := store.ValidateCustomFieldValues(fields, brother.CustomFields, false)
+    if len(fieldErrors) > 0 {
+        slog.InfoContext(r.Context(), "Invalid custom field values", "errors", len(fieldErrors))
+        models.RespondWithValidationErrors(w, fieldErrors)
+        return
+    }
+    brother.CustomFields = customFields
+
+    // The class is resolved in the same transaction, as an unknown className creates the class
+    tx, err := h.db.BeginTx(ctx, nil)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while starting transaction")
+        return
+    }
+    defer tx.Rollback()
+
+    fieldErrors, err = store.ResolveBrotherClass(ctx, tx, &brother)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while resolving pledge class")
+        return
+    }
+    if len(fieldErrors) > 0 {
+        slog.InfoContext(r.Context(), "Invalid pledge class", "errors", len(fieldErrors))
+        models.RespondWithValidationErrors(w, fieldErrors)
+        return
+    }
+
+	created, err := store.InsertBrother(ctx, tx, brother)
 	if err != nil {
-        errMsg := fmt.Sprint("Error during query: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusInternalServerError, errMsg)
+        respondWithDBError(w, r, err, "Error while inserting brother")
 		return
 	}
+    if err := tx.Commit(); err != nil {
+        respondWithDBError(w, r, err, "Error while committing brother")
+        return
+    }
 
-    models.RespondWithSuccess(w, http.StatusCreated, "")
+    location := fmt.Sprintf("/api/brothers/%d", created.BrotherID)
+    models.RespondWithCreated(w, location, created)
 }
 
 //	@Summary		Delete Brother by Roll Call
@@ -203,39 +198,31 @@ func (h *Handler) AddBrother(w http.ResponseWriter, r *http.Request) {
 //	@Failure		400		{object}	models.APIResponse
 //	@Router			/api/brothers/{id} [delete]
 func (h *Handler) RemoveBrother(w http.ResponseWriter, r *http.Request) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	ctx, cancel := requestContext(r)
 	defer cancel()
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-        errMsg := fmt.Sprint("Error parsing request body: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusInternalServerError, errMsg)
-
+        respondWithInternalError(w, r, err, "Error reading request body")
 		return
 	}
 
 	var requestBody map[string]interface{}
 	if err = json.Unmarshal(body, &requestBody); err != nil {
-        errMsg := fmt.Sprint("Error validating body params: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        respondWithDecodeError(w, r, err)
 		return
 	}
 
 	rollCall, ok := requestBody["rollCall"]
 	if !ok {
-        errMsg := "Roll Call missing in body params: %s"
-        models.RespondWithError(w, http.StatusBadRequest, errMsg)
+        respondWithFieldError(w, r, "rollCall", "required", "rollCall is required")
 		return
 	}
 
 	query := fmt.Sprintf("DELETE FROM %s WHERE rollCall = $1", brothers_table)
 	_, err = h.db.ExecContext(ctx, query, rollCall)
 	if err != nil {
-        errMsg := fmt.Sprint("Error while querying for brother with Roll Call %s: %s", rollCall, err.Error())
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDBError(w, r, err, fmt.Sprintf("Error while deleting brother with Roll Call %v", rollCall))
 		return
 	}
 
@@ -245,52 +232,49 @@ func (h *Handler) RemoveBrother(w http.ResponseWriter, r *http.Request) {
 
 
 //	@Summary		Update Brother record
-//	@Description	Update one or more fields for Brother record 
+//	@Description	Update one or more fields for Brother record. customFields values are merged into the stored ones, and null removes a value.
+//	@Description	classID or className change the pledge class like on creation; a null classID removes it. rollCall must stay in the class's roll call range
 //	@Tags			Brothers
 //	@Param			body_params body    models.Brother  true	"Values to update for Brother"
-//	@Success		200		object		models.APIResponse
+//	@Success		200		object		models.APIResponse{data=models.Brother}
 //	@Failure		400		{object}	models.APIResponse
+//	@Failure		409		{object}	models.APIResponse
+//	@Failure		422		{object}	models.APIResponse
 //	@Router			/api/brothers/{id} [patch]
 /* PATCH /api/brothers/{id} */
 func (h *Handler) UpdateBrother(w http.ResponseWriter, r *http.Request) {
-	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	ctx, cancel := requestContext(r)
 	defer cancel()
 
     // TODO: parse body params using JSON NewDecoder()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-        errMsg := fmt.Sprint("Error reading request body %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithInternalError(w, r, err, "Error reading request body")
 		return
 	}
 
 	var requestBody map[string]interface{}
 	if err = json.Unmarshal(body, &requestBody); err != nil {
-        errMsg := fmt.Sprintf("Error decoding JSON: %s", err.Error())
-        log.Printf(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDecodeError(w, r, err)
 		return
 	}
 
 	// rollCall, ok := requestBody["rollCall"]
 	brotherID := chi.URLParam(r, "id")
-	if brotherID == "0" {
-        errMsg := fmt.Sprintf("Missing urlParam brotherID: %s", brotherID)
-        log.Printf(errMsg)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+	if _, err := strconv.Atoi(brotherID); err != nil {
+        respondWithInvalidParam(w, r, "brother ID", err)
 		return
 	}
 
-	// Format query with each param in request body
+	// Format query with each param in request body, binding every value as a parameter after the brother ID
 	// TODO: add validator checks for Body params
-	query := fmt.Sprintf("UPDATE %s SET", brothers_table)
+	args := []interface{}{brotherID}
+	var setClauses []string
 	columns := []string{
 		"firstName",
 		"lastName",
         "major",
 		"status",
-		"class",
 		"email",
 		"phoneNumber",
 		"badStanding",
@@ -304,37 +288,144 @@ func (h *Handler) UpdateBrother(w http.ResponseWriter, r *http.Request) {
 
         switch v := newColumnValue.(type) {
         case float64: // Numbers in JSON decode as float64 by default
-            query += fmt.Sprintf(" %s = '%d',", column, int(v))
-        case int:
-            query += fmt.Sprintf(" %s = '%d',", column, v)
+            args = append(args, int(v))
         case string:
-            query += fmt.Sprintf(" %s = '%s',", column, v)
+            args = append(args, v)
         default:
-            log.Printf("Unsupported type for column %s: %T", column, v)
+            slog.InfoContext(r.Context(), "Unsupported type for column", "column", column, "type", fmt.Sprintf("%T", v))
+            continue
         }
-
-        //query += fmt.Sprintf(" %s = '%s',", column, newColumnValue)
+        setClauses = append(setClauses, fmt.Sprintf("%s = $%d", column, len(args)))
 	}
 
-	// remove trailling comma
-	query = query[:len(query)-1] + " WHERE brotherID = $1"
+    // Custom fields are merged into the stored ones; null removes a value
+    if rawFields, ok := requestBody["customFields"]; ok {
+        values, isObject := rawFields.(map[string]interface{})
+        if !isObject {
+            respondWithFieldError(w, r, "customFields", "type", "customFields must be an object")
+            return
+        }
+        fields, err := store.CustomFields(ctx, h.db)
+        if err != nil {
+            respondWithDBError(w, r, err, "Error while querying custom fields")
+            return
+        }
+        customFields, fieldErrors := store.ValidateCustomFieldValues(fields, values, true)
+        if len(fieldErrors) > 0 {
+            slog.InfoContext(r.Context(), "Invalid custom field values", "errors", len(fieldErrors))
+            models.RespondWithValidationErrors(w, fieldErrors)
+            return
+        }
+        encoded, err := json.Marshal(customFields)
+        if err != nil {
+            respondWithInternalError(w, r, err, "Error encoding custom fields")
+            return
+        }
+        args = append(args, string(encoded))
+        setClauses = append(setClauses, fmt.Sprintf("customFields = jsonb_strip_nulls(customFields || $%d::jsonb)", len(args)))
+    }
 
-	_, err = h.db.ExecContext(ctx, query, brotherID)
+    tx, err := h.db.BeginTx(ctx, nil)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while starting transaction")
+        return
+    }
+    defer tx.Rollback()
+
+    // A new class or roll call is checked against the class's roll call range
+    _, hasClassID := requestBody["classID"]
+    _, hasClassName := requestBody["className"]
+    _, hasRollCall := requestBody["rollCall"]
+    if hasClassID || hasClassName || hasRollCall {
+        current, err := store.ScanBrother(tx.QueryRowContext(ctx, "SELECT "+store.BrotherColumns+" FROM brothers WHERE brotherID = $1 FOR UPDATE", brotherID))
+        if err == sql.ErrNoRows {
+            errMsg := fmt.Sprintf("Brother ID %s not found", brotherID)
+            slog.InfoContext(r.Context(), errMsg)
+            models.RespondWithFail(w, http.StatusNotFound, errMsg)
+            return
+        }
+        if err != nil {
+            respondWithDBError(w, r, err, "Error while querying brother")
+            return
+        }
+        if fieldError, ok := applyClassChanges(&current, requestBody); !ok {
+            respondWithFieldError(w, r, fieldError.Field, fieldError.Rule, fieldError.Message)
+            return
+        }
+        fieldErrors, err := store.ResolveBrotherClass(ctx, tx, &current)
+        if err != nil {
+            respondWithDBError(w, r, err, "Error while resolving pledge class")
+            return
+        }
+        if len(fieldErrors) > 0 {
+            slog.InfoContext(r.Context(), "Invalid pledge class", "errors", len(fieldErrors))
+            models.RespondWithValidationErrors(w, fieldErrors)
+            return
+        }
+        args = append(args, current.ClassID, current.Class)
+        setClauses = append(setClauses, fmt.Sprintf("classID = $%d", len(args)-1), fmt.Sprintf("className = $%d", len(args)))
+    }
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE brotherID = $1 RETURNING %s", brothers_table, strings.Join(setClauses, ", "), store.BrotherColumns)
+
+	brother, err := store.ScanBrother(tx.QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+        errMsg := fmt.Sprintf("Brother ID %s not found", brotherID)
+        slog.InfoContext(r.Context(), errMsg)
+        models.RespondWithFail(w, http.StatusNotFound, errMsg)
+		return
+	}
 	if err != nil {
-        errMsg := fmt.Sprintf("Error while querying `%s`: %s", query, err.Error())
-        log.Printf(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDBError(w, r, err, fmt.Sprintf("Error while querying `%s`", query))
 		return
 	}
+    if err := tx.Commit(); err != nil {
+        respondWithDBError(w, r, err, "Error while committing brother")
+        return
+    }
 
-	// w.Write([]byte(fmt.Sprintf("Updated brother with brotherID %s successfully", brotherID)))
-    models.RespondWithSuccess(w, http.StatusOK, "")
+    models.RespondWithSuccess(w, http.StatusOK, brother)
+}
+
+// Applies the rollCall, classID and className of a PATCH body to a brother. A classID takes precedence over a
+// className and a null classID removes the class. Returns the error and false for a value of the wrong type
+func applyClassChanges(brother *models.Brother, requestBody map[string]interface{}) (models.FieldError, bool) {
+    if value, ok := requestBody["rollCall"]; ok {
+        rollCall, isNumber := value.(float64)
+        if !isNumber {
+            return models.FieldError{Field: "rollCall", Rule: "type", Message: "rollCall must be a number"}, false
+        }
+        brother.RollCall = int(rollCall)
+    }
+    if value, ok := requestBody["className"]; ok {
+        className, isString := value.(string)
+        if !isString {
+            return models.FieldError{Field: "className", Rule: "type", Message: "className must be a string"}, false
+        }
+        brother.ClassID = nil
+        brother.Class = className
+    }
+    if value, ok := requestBody["classID"]; ok {
+        switch classID := value.(type) {
+        case nil:
+            if _, hasClassName := requestBody["className"]; !hasClassName {
+                brother.ClassID = nil
+                brother.Class = ""
+            }
+        case float64:
+            id := int(classID)
+            brother.ClassID = &id
+        default:
+            return models.FieldError{Field: "classID", Rule: "type", Message: "classID must be a number"}, false
+        }
+    }
+    return models.FieldError{}, true
 }
 
 
 // POST /api/brothers/{id}/statuses
 //	@Summary		Get status history of a Brother
-//	@Description	Get all status recorded for Brother
+//	@Description	Get all status recorded for Brother, with the notes on the Brother. Officers-only notes are left out for members
 //	@Tags			Brothers
 //	@Param			id		path		int     true	"Brother ID"
 //	@Success		200		object		models.APIResponse
@@ -342,49 +433,47 @@ func (h *Handler) UpdateBrother(w http.ResponseWriter, r *http.Request) {
 //	@Router			/api/brothers/{id}/statuses [get]
 /* /api/brothers/{id}/statuses */
 func (h *Handler) GetBrotherStatusHistory(w http.ResponseWriter, r *http.Request) {
-    log.Println("\n\nCalled get brother status history")
     brotherIDStr := chi.URLParam(r, "id")
     brotherID, err := strconv.Atoi(brotherIDStr)
     if err != nil {
-        errMsg := fmt.Sprintf("Invalid brother ID: %v", err.Error())
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        respondWithInvalidParam(w, r, "brother ID", err)
         return
     }
 
-    ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+    ctx, cancel := requestContext(r)
 	defer cancel()
 
     query := `
-    SELECT
-        brotherID, rollCall, firstName, lastName, major, status, className, email, phoneNumber, badStanding
+    SELECT ` + store.BrotherColumns + `
     FROM brothers b
     WHERE b.brotherID = $1
     `
-    log.Printf("Querying for brother:\n%s", query)
+    slog.DebugContext(r.Context(), "Query", "sql", query)
     row, err := h.db.QueryContext(ctx, query, brotherID)
 	if err != nil {
-        errMsg := fmt.Sprintf("Error while querying for brother data: `%s`\n", err.Error())
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDBError(w, r, err, "Error while querying for brother data")
 		return
 	}
     defer row.Close()
 
 	var brother models.Brother
 	for row.Next() {
-		brother, err = createBrotherFromRow(row)
+		brother, err = store.ScanBrother(row)
 		if err != nil {
-            errMsg := fmt.Sprintf("Error creating Brother object from row: '%s'\n", err.Error())
-			log.Println(errMsg)
-            models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+            respondWithInternalError(w, r, err, "Error creating Brother object from row")
 			return
 		}
 	}
+    if err := row.Err(); err != nil {
+        respondWithDBError(w, r, err, "Error while reading brother row")
+        return
+    }
+    // Release the connection before the next query
+    row.Close()
     if brother.BrotherID == 0 {
-        errMsg := fmt.Sprintf("Brother ID %d not found", brother.BrotherID)
-        log.Printf(errMsg)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        errMsg := fmt.Sprintf("Brother ID %d not found", brotherID)
+        slog.InfoContext(r.Context(), errMsg)
+        models.RespondWithFail(w, http.StatusNotFound, errMsg)
         return
     }
 
@@ -395,27 +484,35 @@ func (h *Handler) GetBrotherStatusHistory(w http.ResponseWriter, r *http.Request
     JOIN semester s ON s.semesterID = bs.semesterID
     WHERE brotherID = $1
     `
-    log.Printf("Querying for status and semester:\n%s\n", query)
-    row, err = h.db.QueryContext(ctx, query, brotherID)
+    slog.DebugContext(r.Context(), "Query", "sql", query)
+    statusRows, err := h.db.QueryContext(ctx, query, brotherID)
     if err != nil {
-        errMsg := fmt.Sprintf("Error while querying for status and semester: %s", err.Error())
-		fmt.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDBError(w, r, err, "Error while querying for status and semester")
 		return
 	}
+    defer statusRows.Close()
 
 	var brotherStatuses []*models.Status
-	for row.Next() {
-        status, err := models.CreateStatusFromRow(row)
+	for statusRows.Next() {
+        status, err := models.CreateStatusFromRow(statusRows)
 		if err != nil {
-            errMsg := fmt.Sprintf("Error creating Status object from row: %s", err.Error())
-			log.Println(errMsg)
-            models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+            respondWithInternalError(w, r, err, "Error creating Status object from row")
 			return
 		}
         brotherStatuses= append(brotherStatuses, &status)
 	}
-    log.Println("Parsed semesterLabel and status successfully\n")
+    if err := statusRows.Err(); err != nil {
+        respondWithDBError(w, r, err, "Error while reading status and semester rows")
+        return
+    }
+    slog.DebugContext(r.Context(), "Parsed semesterLabel and status successfully")
+
+    // Notes explain the statuses (reasons for Inactive, co-op company...). Members only get notes visible to all
+    notes, err := store.BrotherNotes(ctx, h.db, brotherID, apimiddleware.CanSeeOfficerData(r.Context()))
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while querying brother notes")
+        return
+    }
     
     // Write response
     response := map[string]interface{}{
@@ -424,9 +521,11 @@ func (h *Handler) GetBrotherStatusHistory(w http.ResponseWriter, r *http.Request
         "lastName": brother.LastName,
         "rollCall": brother.RollCall,
         "class": brother.Class,
+        "customFields": brother.CustomFields,
         "statuses": brotherStatuses,
+        "notes": notes,
     }
-    log.Printf("Response: %v\n", response)
+    slog.DebugContext(r.Context(), "Response", "body", logging.Body(response))
 
     models.RespondWithSuccess(w, http.StatusOK, response)
 }
@@ -439,11 +538,13 @@ func (h *Handler) GetBrotherStatusHistory(w http.ResponseWriter, r *http.Request
 //	@Produce		json
 //	@Param			body_params body		handlers.CreateBrotherStatus.RequestBody	true	"Values for new record"
 //	@Param			id		path		int											true	"Brother ID"
-//	@Success		200		object		models.APIResponse
+//	@Success		201		object		models.APIResponse{data=models.StatusRecord}
 //	@Failure		400		{object}	models.APIResponse
+//	@Failure		409		{object}	models.APIResponse
+//	@Failure		422		{object}	models.APIResponse
 //	@Router			/api/brothers/{id}/statuses [post]
 func (h *Handler) CreateBrotherStatus(w http.ResponseWriter, r *http.Request) {
-    ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+    ctx, cancel := requestContext(r)
 	defer cancel()
 
     // Expected request body data
@@ -456,42 +557,24 @@ func (h *Handler) CreateBrotherStatus(w http.ResponseWriter, r *http.Request) {
     // Parse body
     err := json.NewDecoder(r.Body).Decode(&requestBody)
     if err != nil {
-        errMsg := fmt.Sprintf("Error while parsing request body data: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDecodeError(w, r, err)
         return
     }
     // Validate received data
-    validate := validator.New()
     if err := validate.Struct(requestBody); err != nil {
-        errMsg := fmt.Sprintf("Invalid Input: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        respondWithValidationError(w, r, err)
         return
     }
 
     // Create new row for brotherStatus
-    query := `
-    INSERT INTO brotherStatus (brotherID, semesterID, status)
-    VALUES ($1, $2, $3)
-    `
-    log.Printf("Insert query:\n%s\n", query)
-    _, err = h.db.QueryContext(
-        ctx,
-        query,
-        requestBody.BrotherID,
-        requestBody.SemesterID,
-        requestBody.Status,
-    )
-
+    statusRecord, err := store.InsertBrotherStatus(ctx, h.db, requestBody.BrotherID, requestBody.SemesterID, requestBody.Status)
     if err != nil {
-        errMsg := fmt.Sprintf("Query error:\n\t'%s'\n", err.Error())
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDBError(w, r, err, "Error while inserting brother status")
 		return
 	}
 
-    models.RespondWithSuccess(w, http.StatusCreated, "")
+    location := fmt.Sprintf("/api/brothers/%d/statuses", statusRecord.BrotherID)
+    models.RespondWithCreated(w, location, statusRecord)
 }
 
 //	@Tags			Brothers
@@ -502,7 +585,7 @@ func (h *Handler) CreateBrotherStatus(w http.ResponseWriter, r *http.Request) {
 //	@Router			/api/brothers/count [get]
 /* GET /api/brothers/count */
 func (h *Handler) GetBrothersCount(w http.ResponseWriter, r *http.Request) {
-    ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+    ctx, cancel := requestContext(r)
 	defer cancel()
 
     // query for total row counts in brothers table
@@ -518,8 +601,7 @@ func (h *Handler) GetBrothersCount(w http.ResponseWriter, r *http.Request) {
         &count,
     )  
     if err != nil {
-        errMsg := fmt.Sprintf("Error parsing brothers count query result from row: '%s'\n", err.Error())
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDBError(w, r, err, "Error parsing brothers count query result from row")
         return
     }
 
@@ -535,7 +617,7 @@ func (h *Handler) GetBrothersCount(w http.ResponseWriter, r *http.Request) {
 //	@failure		400	{string}	string																	"error"
 //	@Router			/api/brothers/majors/count [get]
 func (h *Handler) GetBrothersMajorsCount(w http.ResponseWriter, r *http.Request) {
-    ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+    ctx, cancel := requestContext(r)
 	defer cancel()
 
     query := `
@@ -545,8 +627,7 @@ func (h *Handler) GetBrothersMajorsCount(w http.ResponseWriter, r *http.Request)
     `
     rows, err := h.db.QueryContext(ctx, query)
     if err != nil {
-        errMsg := fmt.Sprintf("Error during query: '%s'\n", err.Error())
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDBError(w, r, err, "Error while querying major counts")
         return
 	}
 
@@ -562,8 +643,7 @@ func (h *Handler) GetBrothersMajorsCount(w http.ResponseWriter, r *http.Request)
             &curRow.Count,
         )   
         if err != nil {
-            errMsg := fmt.Sprintf("Error parsing major count query result from row: '%s'\n", err.Error())
-            models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+            respondWithInternalError(w, r, err, "Error parsing major count query result from row")
 			return
 		}
         majorCounts = append(majorCounts, &curRow)
@@ -580,7 +660,7 @@ func (h *Handler) GetBrothersMajorsCount(w http.ResponseWriter, r *http.Request)
 //	@failure		400		{string}	models.APIResponse														"error"
 //	@Router			/api/brothers/statuses [get]
 func (h *Handler) GetAllBrotherStatuses(w http.ResponseWriter, r *http.Request) {
-    ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+    ctx, cancel := requestContext(r)
     defer cancel()
     semester := r.URL.Query().Get("semester")
 
@@ -607,23 +687,19 @@ func (h *Handler) GetAllBrotherStatuses(w http.ResponseWriter, r *http.Request)
     } 
 
     // Error handling query
-    fmt.Printf("Querying for all semester statuses:\n%s\n", query)
+    slog.DebugContext(r.Context(), "Query", "sql", query)
     if err != nil {
-        errMsg := fmt.Sprintf("Error while querying for all brother statuses: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDBError(w, r, err, "Error while querying for all brother statuses")
         return
     }
 
-    log.Println("Parsing brother objects\n")
+    slog.DebugContext(r.Context(), "Parsing brother objects")
     // Parse query rows
     var brotherStatuses []*models.BrotherStatus
     for rows.Next(){
         brotherStatus, err := models.CreateBrotherStatusFromRow(rows)
         if err != nil {
-            errMsg := fmt.Sprintf("Error while parsing brotherStatus query: '%s'", err.Error())
-            log.Println(errMsg)
-            models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+            respondWithInternalError(w, r, err, "Error while parsing brotherStatus query")
             return
         }
         brotherStatuses = append(brotherStatuses, &brotherStatus)
@@ -642,43 +718,31 @@ func (h *Handler) GetAllBrotherStatuses(w http.ResponseWriter, r *http.Request)
 //	@failure		400		{string}	models.APIResponse														"error"
 //	@Router			/api/brothers/statuses/count [get]
 func (h *Handler) GetBrotherStatusCount(w http.ResponseWriter, r *http.Request) {
-    ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+    ctx, cancel := requestContext(r)
 	defer cancel()
 
-    // Get query params
-    queryStatus := ""
+    // Get query params. Empty filters match every row
     status := r.URL.Query().Get("status")
-    log.Printf("Received query param status: %s", status)
-    if status != "" {
-        queryStatus = fmt.Sprintf("WHERE bs.status = '%s'", status)
-    }
-
-    querySemester := ""
+    slog.DebugContext(r.Context(), "Received query param", "status", status)
     semester := r.URL.Query().Get("semester")
-    if semester != "" {
-        querySemester = fmt.Sprintf("WHERE s.semesterLabel = %s", semester)
-    }
-    // Check for "all"
 
-    query := fmt.Sprintf(`
+    query := `
     SELECT s.semesterLabel, COUNT(*) AS count
     FROM brotherStatus bs
     JOIN semester s ON bs.semesterID = s.semesterID
-    %s
-    %s
+    WHERE ($1 = '' OR bs.status::text = $1)
+      AND ($2 = '' OR s.semesterLabel = $2)
     GROUP BY s.semesterLabel;
-    `, queryStatus, querySemester)
-    log.Printf("Query:\n%s", query)
+    `
+    slog.DebugContext(r.Context(), "Query", "sql", query)
     //{
     //    data: [
     //        {'semester': 'Fall 2022', actives: 20, co-op: 20, etc...}
     //    ]
     //}
-    rows, err := h.db.QueryContext(ctx, query)
+    rows, err := h.db.QueryContext(ctx, query, status, semester)
     if err != nil {
-        errMsg := fmt.Sprintf("Error during query: '%s'\n", err.Error())
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDBError(w, r, err, "Error while querying status counts")
         return
 	}
 
@@ -694,14 +758,12 @@ func (h *Handler) GetBrotherStatusCount(w http.ResponseWriter, r *http.Request)
             &curRow.Count,
         )   
         if err != nil {
-            errMsg := fmt.Sprintf("Error parsing major count query result from row: '%s'\n", err.Error())
-            log.Println(errMsg)
-            models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+            respondWithInternalError(w, r, err, "Error parsing status count query result from row")
 			return
 		}
         semesterCounts = append(semesterCounts, &curRow)
     }
-    log.Printf("Semester counts: %v:", semesterCounts)
+    slog.DebugContext(r.Context(), "Semester counts", "counts", logging.Body(semesterCounts))
 
     models.RespondWithSuccess(w, http.StatusOK, semesterCounts)
 }
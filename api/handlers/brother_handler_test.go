@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -11,8 +13,10 @@ import (
 
 	"github.com/go-chi/chi"
 	"github.com/joho/godotenv"
+	apimiddleware "github.com/pacific-theta-tau/tt-db/api/middleware"
 	"github.com/pacific-theta-tau/tt-db/api/models"
 	"github.com/pacific-theta-tau/tt-db/db"
+	"github.com/pacific-theta-tau/tt-db/store"
 )
 
 var handler *Handler
@@ -25,7 +29,9 @@ func TestMain(m *testing.M) {
 		log.Fatal("Error loading .env file. Make sure to have setup the appropriate .env file")
 	}
 	testdb := db.NewPostgresDB(os.Getenv("DATABASE_URL"))
-	testdb.Connect()
+	if err := testdb.Connect(); err != nil {
+		log.Fatal(err)
+	}
 	defer testdb.Conn.Close()
 	handler = NewHandler(testdb.Conn)
 
@@ -43,6 +49,80 @@ func checkResponseCode(t *testing.T, expected int, actual int) {
 	}
 }
 
+// Creates a request with body encoded as JSON
+func newJSONRequest(t *testing.T, method string, url string, body interface{}) *http.Request {
+	t.Helper()
+	content, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := http.NewRequest(method, url, bytes.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+// Returns req as sent by a signed in user with the role
+func withRole(req *http.Request, role string) *http.Request {
+	user := models.User{UserID: 1, Email: role + "@example.com", Role: role}
+	return req.WithContext(apimiddleware.WithUser(req.Context(), user))
+}
+
+// Decodes the data of a JSend response into data
+func decodeData(t *testing.T, rr *httptest.ResponseRecorder, data interface{}) {
+	t.Helper()
+	response := struct {
+		Data json.RawMessage `json:"data"`
+	}{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response body: %v", err)
+	}
+	if err := json.Unmarshal(response.Data, data); err != nil {
+		t.Fatalf("failed to parse response data: %v", err)
+	}
+}
+
+// Inserts a brother with the next free roll call and deletes them when the test ends
+func insertTestBrother(t *testing.T, firstName string) models.Brother {
+	t.Helper()
+	ctx := context.Background()
+	var rollCall int
+	if err := handler.db.QueryRowContext(ctx, `SELECT COALESCE(max(rollCall), 0) + 1 FROM brothers`).Scan(&rollCall); err != nil {
+		t.Fatal(err)
+	}
+	brother, err := store.InsertBrother(ctx, handler.db, models.Brother{
+		RollCall:  rollCall,
+		FirstName: firstName,
+		LastName:  "Test",
+		Major:     "Computer Science",
+		Status:    "Active",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		handler.db.ExecContext(context.Background(), `DELETE FROM brothers WHERE brotherID = $1`, brother.BrotherID)
+	})
+	return brother
+}
+
+// Inserts an event and deletes it when the test ends
+func insertTestEvent(t *testing.T) int {
+	t.Helper()
+	var eventID int
+	err := handler.db.QueryRowContext(context.Background(), `
+	INSERT INTO events (eventName, eventLocation, eventDate) VALUES ('Test event', 'Test hall', '2031-09-01')
+	RETURNING eventID`).Scan(&eventID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		handler.db.ExecContext(context.Background(), `DELETE FROM events WHERE eventID = $1`, eventID)
+	})
+	return eventID
+}
+
 // Test GET request for /api/brothers
 func TestGetAllBrothers(t *testing.T) {
 	// Init chi router and handler function
@@ -93,3 +173,22 @@ func TestUpdateBrother(t *testing.T) {
 
 	// TODO: check if expected changes were made
 }
+
+func TestUpdateBrotherBindsValues(t *testing.T) {
+	brother := insertTestBrother(t, "Quoted")
+	router := chi.NewRouter()
+	router.Patch("/api/brothers/{id}", handler.UpdateBrother)
+
+	// A quote in a value is stored as is instead of ending the statement
+	lastName := "O'Brien', badStanding = '1"
+	req := newJSONRequest(t, "PATCH", fmt.Sprintf("/api/brothers/%d", brother.BrotherID), map[string]string{"lastName": lastName})
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	checkResponseCode(t, http.StatusOK, rr.Code)
+
+	var updated models.Brother
+	decodeData(t, rr, &updated)
+	if updated.LastName != lastName || updated.BadStanding != 0 {
+		t.Errorf("Expected only the last name to change. Got %+v", updated)
+	}
+}
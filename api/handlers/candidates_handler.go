@@ -0,0 +1,725 @@
+// candidates_handler.go: Handle requests for rush candidates, their rush event attendance and their conversion to Brothers
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	apimiddleware "github.com/pacific-theta-tau/tt-db/api/middleware"
+	"github.com/pacific-theta-tau/tt-db/api/models"
+	"github.com/pacific-theta-tau/tt-db/store"
+)
+
+// Responds with 401 or 403 and returns false unless the signed in user is an officer or admin. Candidates' details are only for officers
+func canManageCandidates(w http.ResponseWriter, r *http.Request) bool {
+    if apimiddleware.CanSeeOfficerData(r.Context()) {
+        return true
+    }
+    respondWithDenied(w, r, "Member tried to access rush candidates", "Only officers can access rush candidates")
+    return false
+}
+
+// Responds with 404 for a candidate that doesn't exist
+func respondWithCandidateNotFound(w http.ResponseWriter, r *http.Request, candidateID int) {
+    errMsg := fmt.Sprintf("Candidate %d not found", candidateID)
+    slog.InfoContext(r.Context(), errMsg)
+    models.RespondWithFail(w, http.StatusNotFound, errMsg)
+}
+
+// Reads the optional ?stage= and ?semester= filters. Responds with an error and returns false if one is invalid
+func candidateFilters(w http.ResponseWriter, r *http.Request) (stage string, semester string, ok bool) {
+    stage = r.URL.Query().Get("stage")
+    if stage != "" && !slices.Contains(models.CandidateStages, stage) {
+        respondWithInvalidParam(w, r, "stage", fmt.Errorf("unknown stage %q", stage))
+        return "", "", false
+    }
+    semester = r.URL.Query().Get("semester")
+    if semester != "" {
+        if _, _, err := store.SemesterDates(semester); err != nil {
+            respondWithInvalidParam(w, r, "semester", err)
+            return "", "", false
+        }
+    }
+    return stage, semester, true
+}
+
+// Trims and checks the values of a candidate. Responds with an error and returns false if they are invalid
+func validCandidate(w http.ResponseWriter, r *http.Request, candidate *models.Candidate) bool {
+    candidate.FirstName = strings.TrimSpace(candidate.FirstName)
+    candidate.LastName = strings.TrimSpace(candidate.LastName)
+    candidate.Email = strings.TrimSpace(candidate.Email)
+    candidate.Semester = strings.TrimSpace(candidate.Semester)
+    if err := validate.Struct(candidate); err != nil {
+        respondWithValidationError(w, r, err)
+        return false
+    }
+    if candidate.Semester != "" {
+        if _, _, err := store.SemesterDates(candidate.Semester); err != nil {
+            respondWithFieldError(w, r, "semester", "semester", err.Error())
+            return false
+        }
+    }
+    return true
+}
+
+// Parses the candidate ID in the URL and checks the candidate exists. Responds with an error and returns false otherwise
+func (h *Handler) candidateParam(w http.ResponseWriter, r *http.Request) (int, bool) {
+    candidateID, ok := idParam(w, r, "candidateID", "candidate ID")
+    if !ok {
+        return 0, false
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    if _, err := store.GetCandidate(ctx, h.db, candidateID); errors.Is(err, sql.ErrNoRows) {
+        respondWithCandidateNotFound(w, r, candidateID)
+        return 0, false
+    } else if err != nil {
+        respondWithDBError(w, r, err, "Error while querying candidate")
+        return 0, false
+    }
+    return candidateID, true
+}
+
+// GET /api/candidates
+//	@Summary		Get rush candidates
+//	@Description	Get candidates, newest first. Officers only
+//	@Tags			Candidates
+//	@Produce		json
+//	@Param			stage		query		string	false	"Only candidates at this stage"	Enums(interested, attended, bid_extended, accepted, pledged)
+//	@Param			semester	query		string	false	"Only candidates rushing this semester, e.g. Fall 2024"
+//	@Success		200			{object}	models.APIResponse{data=[]models.Candidate}
+//	@Failure		400			{object}	models.APIResponse
+//	@Failure		401			{object}	models.APIResponse
+//	@Failure		403			{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/candidates [get]
+func (h *Handler) GetCandidates(w http.ResponseWriter, r *http.Request) {
+    if !canManageCandidates(w, r) {
+        return
+    }
+    stage, semester, ok := candidateFilters(w, r)
+    if !ok {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    candidates, err := store.Candidates(ctx, h.db, stage, semester)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while querying candidates")
+        return
+    }
+
+    models.RespondWithSuccess(w, http.StatusOK, candidates)
+}
+
+// GET /api/candidates/pipeline
+//	@Summary		Get the rush pipeline
+//	@Description	Get the number of candidates at each stage, in pipeline order. Officers only
+//	@Tags			Candidates
+//	@Produce		json
+//	@Param			semester	query		string	false	"Only candidates rushing this semester, e.g. Fall 2024"
+//	@Success		200			{object}	models.APIResponse{data=[]models.StageCount}
+//	@Failure		400			{object}	models.APIResponse
+//	@Failure		401			{object}	models.APIResponse
+//	@Failure		403			{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/candidates/pipeline [get]
+func (h *Handler) GetCandidatePipeline(w http.ResponseWriter, r *http.Request) {
+    if !canManageCandidates(w, r) {
+        return
+    }
+    _, semester, ok := candidateFilters(w, r)
+    if !ok {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    counts, err := store.StageCounts(ctx, h.db, semester)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while counting candidates")
+        return
+    }
+
+    models.RespondWithSuccess(w, http.StatusOK, counts)
+}
+
+// GET /api/candidates/{candidateID}
+//	@Summary		Get a rush candidate
+//	@Description	Officers only
+//	@Tags			Candidates
+//	@Produce		json
+//	@Param			candidateID	path		int		true	"Candidate ID"
+//	@Success		200			{object}	models.APIResponse{data=models.Candidate}
+//	@Failure		400			{object}	models.APIResponse
+//	@Failure		401			{object}	models.APIResponse
+//	@Failure		403			{object}	models.APIResponse
+//	@Failure		404			{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/candidates/{candidateID} [get]
+func (h *Handler) GetCandidate(w http.ResponseWriter, r *http.Request) {
+    if !canManageCandidates(w, r) {
+        return
+    }
+    candidateID, ok := idParam(w, r, "candidateID", "candidate ID")
+    if !ok {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    candidate, err := store.GetCandidate(ctx, h.db, candidateID)
+    if errors.Is(err, sql.ErrNoRows) {
+        respondWithCandidateNotFound(w, r, candidateID)
+        return
+    }
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while querying candidate")
+        return
+    }
+
+    models.RespondWithSuccess(w, http.StatusOK, candidate)
+}
+
+// POST /api/candidates
+//	@Summary		Add a rush candidate
+//	@Description	Add a prospective member, at the interested stage unless another stage is given. Officers only
+//	@Tags			Candidates
+//	@Accept			json
+//	@Produce		json
+//	@Param			body_params body		models.Candidate	true	"Candidate to add"
+//	@Success		201		{object}	models.APIResponse{data=models.Candidate}
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		401		{object}	models.APIResponse
+//	@Failure		403		{object}	models.APIResponse
+//	@Failure		422		{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/candidates [post]
+func (h *Handler) CreateCandidate(w http.ResponseWriter, r *http.Request) {
+    if !canManageCandidates(w, r) {
+        return
+    }
+
+    var candidate models.Candidate
+    if err := json.NewDecoder(r.Body).Decode(&candidate); err != nil {
+        respondWithDecodeError(w, r, err)
+        return
+    }
+    if !validCandidate(w, r, &candidate) {
+        return
+    }
+    if candidate.Stage == models.StagePledged {
+        respondWithFieldError(w, r, "stage", "convert", "Candidates become pledged when they are converted to Brothers")
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    created, err := store.InsertCandidate(ctx, h.db, candidate)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while inserting candidate")
+        return
+    }
+
+    location := fmt.Sprintf("/api/candidates/%d", created.CandidateID)
+    models.RespondWithCreated(w, location, created)
+}
+
+// PATCH /api/candidates/{candidateID}
+//	@Summary		Update a rush candidate
+//	@Description	Change the details or stage of a candidate, e.g. {"stage": "bid_extended"}. Stages can move back, but only conversion makes a candidate pledged. Officers only
+//	@Tags			Candidates
+//	@Accept			json
+//	@Produce		json
+//	@Param			body_params	body		handlers.UpdateCandidate.RequestBody	true	"Values to change"
+//	@Param			candidateID	path		int										true	"Candidate ID"
+//	@Success		200			{object}	models.APIResponse{data=models.Candidate}
+//	@Failure		400			{object}	models.APIResponse
+//	@Failure		401			{object}	models.APIResponse
+//	@Failure		403			{object}	models.APIResponse
+//	@Failure		404			{object}	models.APIResponse
+//	@Failure		409			{object}	models.APIResponse
+//	@Failure		422			{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/candidates/{candidateID} [patch]
+func (h *Handler) UpdateCandidate(w http.ResponseWriter, r *http.Request) {
+    if !canManageCandidates(w, r) {
+        return
+    }
+    candidateID, ok := idParam(w, r, "candidateID", "candidate ID")
+    if !ok {
+        return
+    }
+
+    // Expected request body data. Omitted values are left unchanged
+    type RequestBody struct {
+        FirstName   *string `json:"firstName"`
+        LastName    *string `json:"lastName"`
+        Email       *string `json:"email"`
+        PhoneNumber *string `json:"phoneNumber"`
+        Major       *string `json:"major"`
+        Semester    *string `json:"semester"`
+        Stage       *string `json:"stage"`
+    }
+    var requestBody RequestBody
+    if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+        respondWithDecodeError(w, r, err)
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    tx, err := h.db.BeginTx(ctx, nil)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while starting transaction")
+        return
+    }
+    defer tx.Rollback()
+
+    candidate, err := store.LockCandidate(ctx, tx, candidateID)
+    if errors.Is(err, sql.ErrNoRows) {
+        respondWithCandidateNotFound(w, r, candidateID)
+        return
+    }
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while querying candidate")
+        return
+    }
+
+    for _, change := range []struct {
+        value  *string
+        target *string
+    }{
+        {requestBody.FirstName, &candidate.FirstName},
+        {requestBody.LastName, &candidate.LastName},
+        {requestBody.Email, &candidate.Email},
+        {requestBody.PhoneNumber, &candidate.PhoneNumber},
+        {requestBody.Major, &candidate.Major},
+        {requestBody.Semester, &candidate.Semester},
+    } {
+        if change.value != nil {
+            *change.target = *change.value
+        }
+    }
+    if requestBody.Stage != nil && *requestBody.Stage != candidate.Stage {
+        if candidate.BrotherID != nil {
+            errMsg := fmt.Sprintf("Candidate %d is already Brother ID %d", candidateID, *candidate.BrotherID)
+            slog.InfoContext(r.Context(), errMsg)
+            models.RespondWithFailCode(w, http.StatusConflict, models.CodeConflict, errMsg)
+            return
+        }
+        if *requestBody.Stage == models.StagePledged {
+            respondWithFieldError(w, r, "stage", "convert", "Candidates become pledged when they are converted to Brothers")
+            return
+        }
+        candidate.Stage = *requestBody.Stage
+    }
+    if !validCandidate(w, r, &candidate) {
+        return
+    }
+
+    updated, err := store.UpdateCandidate(ctx, tx, candidate)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while updating candidate")
+        return
+    }
+    if err := tx.Commit(); err != nil {
+        respondWithDBError(w, r, err, "Error while committing candidate")
+        return
+    }
+
+    models.RespondWithSuccess(w, http.StatusOK, updated)
+}
+
+// DELETE /api/candidates/{candidateID}
+//	@Summary		Delete a rush candidate
+//	@Description	Delete a candidate and their rush attendance. A Brother converted from them is kept with their attendance. Officers only
+//	@Tags			Candidates
+//	@Produce		json
+//	@Param			candidateID	path		int		true	"Candidate ID"
+//	@Success		200			{object}	models.APIResponse{data=models.Candidate}
+//	@Failure		400			{object}	models.APIResponse
+//	@Failure		401			{object}	models.APIResponse
+//	@Failure		403			{object}	models.APIResponse
+//	@Failure		404			{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/candidates/{candidateID} [delete]
+func (h *Handler) DeleteCandidate(w http.ResponseWriter, r *http.Request) {
+    if !canManageCandidates(w, r) {
+        return
+    }
+    candidateID, ok := idParam(w, r, "candidateID", "candidate ID")
+    if !ok {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    tx, err := h.db.BeginTx(ctx, nil)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while starting transaction")
+        return
+    }
+    defer tx.Rollback()
+
+    deleted, err := store.DeleteCandidate(ctx, tx, candidateID)
+    if errors.Is(err, sql.ErrNoRows) {
+        respondWithCandidateNotFound(w, r, candidateID)
+        return
+    }
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while deleting candidate")
+        return
+    }
+    if err := tx.Commit(); err != nil {
+        respondWithDBError(w, r, err, "Error while committing candidate deletion")
+        return
+    }
+    slog.InfoContext(r.Context(), "Deleted candidate", "candidate_id", deleted.CandidateID)
+
+    models.RespondWithSuccess(w, http.StatusOK, deleted)
+}
+
+// GET /api/candidates/{candidateID}/attendance
+//	@Summary		Get the rush attendance of a candidate
+//	@Description	Get the events a candidate was recorded at, oldest first. Officers only
+//	@Tags			Candidates
+//	@Produce		json
+//	@Param			candidateID	path		int		true	"Candidate ID"
+//	@Success		200			{object}	models.APIResponse{data=[]models.CandidateAttendance}
+//	@Failure		400			{object}	models.APIResponse
+//	@Failure		401			{object}	models.APIResponse
+//	@Failure		403			{object}	models.APIResponse
+//	@Failure		404			{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/candidates/{candidateID}/attendance [get]
+func (h *Handler) GetCandidateAttendance(w http.ResponseWriter, r *http.Request) {
+    if !canManageCandidates(w, r) {
+        return
+    }
+    candidateID, ok := h.candidateParam(w, r)
+    if !ok {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    records, err := store.CandidateAttendanceOf(ctx, h.db, candidateID)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while querying candidate attendance")
+        return
+    }
+
+    models.RespondWithSuccess(w, http.StatusOK, records)
+}
+
+// PUT /api/candidates/{candidateID}/attendance/{eventID}
+//	@Summary		Record the attendance of a candidate
+//	@Description	Record whether a candidate attended a rush event, replacing any previous record. The record is also the Brother's once the candidate is converted. Present moves an interested candidate to the attended stage. Officers only
+//	@Tags			Candidates
+//	@Accept			json
+//	@Produce		json
+//	@Param			body_params	body		handlers.SetCandidateAttendance.RequestBody	true	"Attendance status"
+//	@Param			candidateID	path		int											true	"Candidate ID"
+//	@Param			eventID		path		int											true	"Event ID"
+//	@Success		200			{object}	models.APIResponse{data=models.CandidateAttendance}
+//	@Failure		400			{object}	models.APIResponse
+//	@Failure		401			{object}	models.APIResponse
+//	@Failure		403			{object}	models.APIResponse
+//	@Failure		404			{object}	models.APIResponse
+//	@Failure		422			{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/candidates/{candidateID}/attendance/{eventID} [put]
+func (h *Handler) SetCandidateAttendance(w http.ResponseWriter, r *http.Request) {
+    if !canManageCandidates(w, r) {
+        return
+    }
+    candidateID, ok := h.candidateParam(w, r)
+    if !ok {
+        return
+    }
+    eventID, ok := idParam(w, r, "eventID", "event ID")
+    if !ok {
+        return
+    }
+
+    // Expected request body data
+    type RequestBody struct {
+        AttendanceStatus string `json:"attendanceStatus" validate:"required"`
+    }
+    var requestBody RequestBody
+    if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+        respondWithDecodeError(w, r, err)
+        return
+    }
+    if err := validate.Struct(requestBody); err != nil {
+        respondWithValidationError(w, r, err)
+        return
+    }
+    if !models.AttendanceStatus[requestBody.AttendanceStatus] {
+        respondWithFieldError(w, r, "attendanceStatus", "oneof", validAttendanceStatusMessage)
+        return
+    }
+    if !h.eventExists(w, r, eventID) {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    tx, err := h.db.BeginTx(ctx, nil)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while starting transaction")
+        return
+    }
+    defer tx.Rollback()
+
+    record, err := store.SetCandidateAttendance(ctx, tx, candidateID, eventID, requestBody.AttendanceStatus)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while recording candidate attendance")
+        return
+    }
+    if err := tx.Commit(); err != nil {
+        respondWithDBError(w, r, err, "Error while committing candidate attendance")
+        return
+    }
+
+    models.RespondWithSuccess(w, http.StatusOK, record)
+}
+
+// DELETE /api/candidates/{candidateID}/attendance/{eventID}
+//	@Summary		Remove the attendance of a candidate
+//	@Description	Remove the record of a candidate at an event, which is also the record of the Brother converted from them. The candidate's stage is left as is. Officers only
+//	@Tags			Candidates
+//	@Param			candidateID	path		int		true	"Candidate ID"
+//	@Param			eventID		path		int		true	"Event ID"
+//	@Success		204
+//	@Failure		400			{object}	models.APIResponse
+//	@Failure		401			{object}	models.APIResponse
+//	@Failure		403			{object}	models.APIResponse
+//	@Failure		404			{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/candidates/{candidateID}/attendance/{eventID} [delete]
+func (h *Handler) RemoveCandidateAttendance(w http.ResponseWriter, r *http.Request) {
+    if !canManageCandidates(w, r) {
+        return
+    }
+    candidateID, ok := idParam(w, r, "candidateID", "candidate ID")
+    if !ok {
+        return
+    }
+    eventID, ok := idParam(w, r, "eventID", "event ID")
+    if !ok {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    removed, err := store.RemoveCandidateAttendance(ctx, h.db, candidateID, eventID)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while removing candidate attendance")
+        return
+    }
+    if !removed {
+        errMsg := fmt.Sprintf("Candidate %d has no attendance for event %d", candidateID, eventID)
+        slog.InfoContext(r.Context(), errMsg)
+        models.RespondWithFail(w, http.StatusNotFound, errMsg)
+        return
+    }
+
+    w.WriteHeader(http.StatusNoContent)
+}
+
+// GET /api/events/{eventID}/candidates
+//	@Summary		Get the candidates at an event
+//	@Description	Get the rush attendance of candidates at an event, by name. Officers only
+//	@Tags			Candidates
+//	@Produce		json
+//	@Param			eventID	path		int		true	"Event ID"
+//	@Success		200		{object}	models.APIResponse{data=[]models.CandidateAttendance}
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		401		{object}	models.APIResponse
+//	@Failure		403		{object}	models.APIResponse
+//	@Failure		404		{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/events/{eventID}/candidates [get]
+func (h *Handler) GetEventCandidates(w http.ResponseWriter, r *http.Request) {
+    if !canManageCandidates(w, r) {
+        return
+    }
+    eventID, ok := idParam(w, r, "eventID", "event ID")
+    if !ok || !h.eventExists(w, r, eventID) {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    records, err := store.EventCandidates(ctx, h.db, eventID)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while querying event candidates")
+        return
+    }
+
+    models.RespondWithSuccess(w, http.StatusOK, records)
+}
+
+// POST /api/candidates/{candidateID}/convert
+//	@Summary		Convert a candidate to a Brother
+//	@Description	Create the Brother record of a candidate who accepted their bid, with their first semester status, and link their rush attendance to it.
+//	@Description	Name, email and phone number come from the candidate; major and semester default to the candidate's and status to Active. The semester must already exist. The candidate moves to the pledged stage. Officers only
+//	@Tags			Candidates
+//	@Accept			json
+//	@Produce		json
+//	@Param			body_params	body		models.CandidateConversion	true	"Values for the new Brother"
+//	@Param			candidateID	path		int							true	"Candidate ID"
+//	@Success		201			{object}	models.APIResponse{data=models.ConvertedCandidate}
+//	@Failure		400			{object}	models.APIResponse
+//	@Failure		401			{object}	models.APIResponse
+//	@Failure		403			{object}	models.APIResponse
+//	@Failure		404			{object}	models.APIResponse
+//	@Failure		409			{object}	models.APIResponse
+//	@Failure		422			{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/candidates/{candidateID}/convert [post]
+func (h *Handler) ConvertCandidate(w http.ResponseWriter, r *http.Request) {
+    if !canManageCandidates(w, r) {
+        return
+    }
+    candidateID, ok := idParam(w, r, "candidateID", "candidate ID")
+    if !ok {
+        return
+    }
+
+    var conversion models.CandidateConversion
+    if err := json.NewDecoder(r.Body).Decode(&conversion); err != nil {
+        respondWithDecodeError(w, r, err)
+        return
+    }
+    if err := validate.Struct(conversion); err != nil {
+        respondWithValidationError(w, r, err)
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    tx, err := h.db.BeginTx(ctx, nil)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while starting transaction")
+        return
+    }
+    defer tx.Rollback()
+
+    candidate, err := store.LockCandidate(ctx, tx, candidateID)
+    if errors.Is(err, sql.ErrNoRows) {
+        respondWithCandidateNotFound(w, r, candidateID)
+        return
+    }
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while querying candidate")
+        return
+    }
+    if candidate.BrotherID != nil {
+        errMsg := fmt.Sprintf("Candidate %d was already converted to Brother ID %d", candidateID, *candidate.BrotherID)
+        slog.InfoContext(r.Context(), errMsg)
+        models.RespondWithFailCode(w, http.StatusConflict, models.CodeConflict, errMsg)
+        return
+    }
+    if !store.CanConvert(candidate.Stage) {
+        errMsg := fmt.Sprintf("Candidate %d is at the %s stage and must accept a bid before becoming a Brother", candidateID, candidate.Stage)
+        slog.InfoContext(r.Context(), errMsg)
+        models.RespondWithFailCode(w, http.StatusConflict, models.CodeConflict, errMsg)
+        return
+    }
+
+    semester := strings.TrimSpace(conversion.Semester)
+    if semester == "" {
+        semester = candidate.Semester
+    }
+    if _, _, err := store.SemesterDates(semester); err != nil {
+        respondWithFieldError(w, r, "semester", "semester", err.Error())
+        return
+    }
+    semesterID, err := store.SemesterIDByLabel(ctx, tx, semester)
+    if errors.Is(err, sql.ErrNoRows) {
+        respondWithFieldError(w, r, "semester", "exists", fmt.Sprintf("Semester %s does not exist", semester))
+        return
+    }
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while querying semester")
+        return
+    }
+
+    // Check the Brother like AddBrother does
+    brother := store.BrotherFromCandidate(candidate, conversion)
+    if err := validate.Struct(brother); err != nil {
+        respondWithValidationError(w, r, err)
+        return
+    }
+    fields, err := store.CustomFields(ctx, tx)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while querying custom fields")
+        return
+    }
+    customFields, fieldErrors := store.ValidateCustomFieldValues(fields, brother.CustomFields, false)
+    if len(fieldErrors) == 0 {
+        brother.CustomFields = customFields
+        fieldErrors, err = store.ResolveBrotherClass(ctx, tx, &brother)
+        if err != nil {
+            respondWithDBError(w, r, err, "Error while resolving pledge class")
+            return
+        }
+    }
+    if len(fieldErrors) > 0 {
+        slog.InfoContext(r.Context(), "Invalid Brother for candidate", "candidate_id", candidateID, "errors", len(fieldErrors))
+        models.RespondWithValidationErrors(w, fieldErrors)
+        return
+    }
+
+    created, err := store.InsertBrother(ctx, tx, brother)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while inserting brother")
+        return
+    }
+    status, err := store.InsertBrotherStatus(ctx, tx, created.BrotherID, semesterID, created.Status)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while inserting brother status")
+        return
+    }
+    converted, linked, err := store.ConvertCandidate(ctx, tx, candidateID, created.BrotherID)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while converting candidate")
+        return
+    }
+    if err := tx.Commit(); err != nil {
+        respondWithDBError(w, r, err, "Error while committing candidate conversion")
+        return
+    }
+    slog.InfoContext(r.Context(), "Converted candidate", "candidate_id", candidateID, "brother_id", created.BrotherID, "attendance_linked", linked)
+
+    location := fmt.Sprintf("/api/brothers/%d", created.BrotherID)
+    models.RespondWithCreated(w, location, models.ConvertedCandidate{
+        Candidate:        converted,
+        Brother:          created,
+        Status:           status,
+        AttendanceLinked: linked,
+    })
+}
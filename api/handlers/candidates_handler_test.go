@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/pacific-theta-tau/tt-db/api/models"
+	"github.com/pacific-theta-tau/tt-db/store"
+)
+
+// Rush semester of the candidates in these tests
+const testRushSemester = "Fall 2024"
+
+// Makes sure testRushSemester exists, deleting it when the test ends if it had to be created
+func ensureTestSemester(t *testing.T) {
+	t.Helper()
+	ctx := context.Background()
+	_, err := store.SemesterIDByLabel(ctx, handler.db, testRushSemester)
+	if err == nil {
+		return
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Fatal(err)
+	}
+	semester, err := store.CreateSemester(ctx, handler.db, testRushSemester)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		handler.db.ExecContext(context.Background(), `DELETE FROM semester WHERE semesterID = $1`, semester.SemesterID)
+	})
+}
+
+func TestConvertCandidate(t *testing.T) {
+	ctx := context.Background()
+	ensureTestSemester(t)
+	eventID := insertTestEvent(t)
+	candidate, err := store.InsertCandidate(ctx, handler.db, models.Candidate{
+		FirstName: "Rush",
+		LastName:  "Test",
+		Major:     "Computer Science",
+		Semester:  testRushSemester,
+		Stage:     models.StageAccepted,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var converted models.ConvertedCandidate
+	t.Cleanup(func() {
+		handler.db.ExecContext(context.Background(), `DELETE FROM candidates WHERE candidateID = $1`, candidate.CandidateID)
+		handler.db.ExecContext(context.Background(), `DELETE FROM brothers WHERE brotherID = $1`, converted.Brother.BrotherID)
+	})
+
+	router := chi.NewRouter()
+	router.Put("/api/candidates/{candidateID}/attendance/{eventID}", handler.SetCandidateAttendance)
+	router.Post("/api/candidates/{candidateID}/convert", handler.ConvertCandidate)
+	attendanceURL := fmt.Sprintf("/api/candidates/%d/attendance/%d", candidate.CandidateID, eventID)
+	convertURL := fmt.Sprintf("/api/candidates/%d/convert", candidate.CandidateID)
+
+	req := newJSONRequest(t, "PUT", attendanceURL, map[string]string{"attendanceStatus": "Present"})
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, withRole(req, models.RoleOfficer))
+	checkResponseCode(t, http.StatusOK, rr.Code)
+
+	var rollCall int
+	if err := handler.db.QueryRowContext(ctx, `SELECT COALESCE(max(rollCall), 0) + 1 FROM brothers`).Scan(&rollCall); err != nil {
+		t.Fatal(err)
+	}
+	conversion := models.CandidateConversion{RollCall: rollCall}
+
+	// Members can't convert candidates
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, withRole(newJSONRequest(t, "POST", convertURL, conversion), models.RoleMember))
+	checkResponseCode(t, http.StatusForbidden, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, withRole(newJSONRequest(t, "POST", convertURL, conversion), models.RoleOfficer))
+	checkResponseCode(t, http.StatusCreated, rr.Code)
+	decodeData(t, rr, &converted)
+
+	if converted.Candidate.Stage != models.StagePledged || converted.Candidate.BrotherID == nil || *converted.Candidate.BrotherID != converted.Brother.BrotherID {
+		t.Errorf("Expected the candidate to be pledged and linked to the new brother. Got %+v", converted.Candidate)
+	}
+	if converted.Brother.FirstName != "Rush" || converted.Brother.Status != "Active" || converted.Status.SemesterLabel != testRushSemester {
+		t.Errorf("Expected an active brother from the candidate in %s. Got %+v with status %+v", testRushSemester, converted.Brother, converted.Status)
+	}
+	if converted.AttendanceLinked != 1 {
+		t.Errorf("Expected 1 attendance record linked. Got %d", converted.AttendanceLinked)
+	}
+	// The rush record is now the brother's too
+	var status string
+	err = handler.db.QueryRowContext(ctx, `SELECT attendanceStatus FROM attendance WHERE brotherID = $1 AND candidateID = $2 AND eventID = $3`,
+		converted.Brother.BrotherID, candidate.CandidateID, eventID).Scan(&status)
+	if err != nil || status != "Present" {
+		t.Errorf("Expected the brother to share the candidate's attendance. Got %q (%v)", status, err)
+	}
+
+	// A candidate is converted only once
+	conversion.RollCall++
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, withRole(newJSONRequest(t, "POST", convertURL, conversion), models.RoleOfficer))
+	checkResponseCode(t, http.StatusConflict, rr.Code)
+}
+
+func TestDeleteConvertedCandidateKeepsAttendance(t *testing.T) {
+	ctx := context.Background()
+	brother := insertTestBrother(t, "Converted")
+	eventID := insertTestEvent(t)
+	candidate, err := store.InsertCandidate(ctx, handler.db, models.Candidate{FirstName: "Converted", LastName: "Test", Semester: testRushSemester})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		handler.db.ExecContext(context.Background(), `DELETE FROM candidates WHERE candidateID = $1`, candidate.CandidateID)
+	})
+	if _, _, err := store.ConvertCandidate(ctx, handler.db, candidate.CandidateID, brother.BrotherID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.SetCandidateAttendance(ctx, handler.db, candidate.CandidateID, eventID, "Present"); err != nil {
+		t.Fatal(err)
+	}
+
+	router := chi.NewRouter()
+	router.Delete("/api/candidates/{candidateID}", handler.DeleteCandidate)
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("/api/candidates/%d", candidate.CandidateID), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, withRole(req, models.RoleOfficer))
+	checkResponseCode(t, http.StatusOK, rr.Code)
+
+	var candidateID sql.NullInt64
+	err = handler.db.QueryRowContext(ctx, `SELECT candidateID FROM attendance WHERE brotherID = $1 AND eventID = $2`,
+		brother.BrotherID, eventID).Scan(&candidateID)
+	if err != nil || candidateID.Valid {
+		t.Errorf("Expected the brother to keep the attendance without the candidate. Got %v (%v)", candidateID, err)
+	}
+}
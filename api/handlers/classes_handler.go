@@ -0,0 +1,402 @@
+// classes_handler.go: Handle requests for pledge classes and their members
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	apimiddleware "github.com/pacific-theta-tau/tt-db/api/middleware"
+	"github.com/pacific-theta-tau/tt-db/api/models"
+	"github.com/pacific-theta-tau/tt-db/store"
+)
+
+// Responds with 401 or 403 and returns false unless the signed in user is an officer or admin. Only officers change pledge classes
+func canWriteClasses(w http.ResponseWriter, r *http.Request) bool {
+    if apimiddleware.CanSeeOfficerData(r.Context()) {
+        return true
+    }
+    respondWithDenied(w, r, "Member tried to change a pledge class", "Only officers can change pledge classes")
+    return false
+}
+
+// Responds with 404 for a pledge class that doesn't exist
+func respondWithClassNotFound(w http.ResponseWriter, r *http.Request, classID int) {
+    errMsg := fmt.Sprintf("Pledge class %d not found", classID)
+    slog.InfoContext(r.Context(), errMsg)
+    models.RespondWithFail(w, http.StatusNotFound, errMsg)
+}
+
+// Normalizes the name and checks the values of a pledge class. Responds with an error and returns false if they are invalid
+func validClass(w http.ResponseWriter, r *http.Request, class *models.PledgeClass) bool {
+    if err := validate.Struct(class); err != nil {
+        respondWithValidationError(w, r, err)
+        return false
+    }
+    name, err := store.NormalizeClassName(class.Name)
+    if err != nil {
+        respondWithFieldError(w, r, "name", "greek", err.Error())
+        return false
+    }
+    class.Name = name
+    if fieldErrors := store.ValidatePledgeClass(*class); len(fieldErrors) > 0 {
+        slog.InfoContext(r.Context(), "Invalid pledge class", "errors", len(fieldErrors))
+        models.RespondWithValidationErrors(w, fieldErrors)
+        return false
+    }
+    return true
+}
+
+// Checks the roll call range of a class against the other classes and its members.
+// Responds with an error and returns false if it overlaps another class or leaves members out
+func (h *Handler) classRangeFits(w http.ResponseWriter, r *http.Request, q store.Querier, class models.PledgeClass) bool {
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    overlapping, err := store.OverlappingClass(ctx, q, class)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while checking roll call ranges")
+        return false
+    }
+    if overlapping != "" {
+        errMsg := fmt.Sprintf("Roll calls %d to %d overlap the range of the %s class", *class.FirstRollCall, *class.LastRollCall, overlapping)
+        slog.InfoContext(r.Context(), errMsg)
+        models.RespondWithFailCode(w, http.StatusConflict, models.CodeConflict, errMsg)
+        return false
+    }
+
+    outside, err := store.CountOutOfRange(ctx, q, class)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while checking roll calls of class members")
+        return false
+    }
+    if outside > 0 {
+        respondWithFieldError(w, r, "firstRollCall", "range", fmt.Sprintf("%d members of the class have a roll call outside %d to %d", outside, *class.FirstRollCall, *class.LastRollCall))
+        return false
+    }
+    return true
+}
+
+// Locks pledge class ranges against other writers until the transaction ends, so range checks stay valid until the class is saved
+func lockClasses(r *http.Request, tx store.Querier) error {
+    ctx, cancel := requestContext(r)
+    defer cancel()
+    _, err := tx.ExecContext(ctx, `LOCK TABLE pledgeClasses IN SHARE ROW EXCLUSIVE MODE`)
+    return err
+}
+
+// GET /api/classes
+//	@Summary		Get pledge classes
+//	@Description	Get every pledge class in roll call order. Classes without a roll call range come last
+//	@Tags			Classes
+//	@Produce		json
+//	@Success		200		{object}	models.APIResponse{data=[]models.PledgeClass}
+//	@Router			/api/classes [get]
+func (h *Handler) GetClasses(w http.ResponseWriter, r *http.Request) {
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    classes, err := store.PledgeClasses(ctx, h.db)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while querying pledge classes")
+        return
+    }
+
+    models.RespondWithSuccess(w, http.StatusOK, classes)
+}
+
+// GET /api/classes/{classID}
+//	@Summary		Get a pledge class
+//	@Tags			Classes
+//	@Produce		json
+//	@Param			classID	path		int		true	"Class ID"
+//	@Success		200		{object}	models.APIResponse{data=models.PledgeClass}
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		404		{object}	models.APIResponse
+//	@Router			/api/classes/{classID} [get]
+func (h *Handler) GetClass(w http.ResponseWriter, r *http.Request) {
+    classID, ok := idParam(w, r, "classID", "class ID")
+    if !ok {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    class, err := store.GetPledgeClass(ctx, h.db, classID)
+    if errors.Is(err, sql.ErrNoRows) {
+        respondWithClassNotFound(w, r, classID)
+        return
+    }
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while querying pledge class")
+        return
+    }
+
+    models.RespondWithSuccess(w, http.StatusOK, class)
+}
+
+// POST /api/classes
+//	@Summary		Create a pledge class
+//	@Description	Create a pledge class named with Greek letters, e.g. "Alpha Beta". Roll call ranges can't overlap. Officers only
+//	@Tags			Classes
+//	@Accept			json
+//	@Produce		json
+//	@Param			body_params body		models.PledgeClass	true	"Class to create"
+//	@Success		201		{object}	models.APIResponse{data=models.PledgeClass}
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		401		{object}	models.APIResponse
+//	@Failure		403		{object}	models.APIResponse
+//	@Failure		409		{object}	models.APIResponse
+//	@Failure		422		{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/classes [post]
+func (h *Handler) CreateClass(w http.ResponseWriter, r *http.Request) {
+    if !canWriteClasses(w, r) {
+        return
+    }
+
+    var class models.PledgeClass
+    if err := json.NewDecoder(r.Body).Decode(&class); err != nil {
+        respondWithDecodeError(w, r, err)
+        return
+    }
+    class.ClassID = 0
+    if !validClass(w, r, &class) {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    tx, err := h.db.BeginTx(ctx, nil)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while starting transaction")
+        return
+    }
+    defer tx.Rollback()
+
+    if err := lockClasses(r, tx); err != nil {
+        respondWithDBError(w, r, err, "Error while locking pledge classes")
+        return
+    }
+    if !h.classRangeFits(w, r, tx, class) {
+        return
+    }
+    created, err := store.InsertPledgeClass(ctx, tx, class)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while inserting pledge class")
+        return
+    }
+    if err := tx.Commit(); err != nil {
+        respondWithDBError(w, r, err, "Error while committing pledge class")
+        return
+    }
+
+    location := fmt.Sprintf("/api/classes/%d", created.ClassID)
+    models.RespondWithCreated(w, location, created)
+}
+
+// PATCH /api/classes/{classID}
+//	@Summary		Update a pledge class
+//	@Description	Change the name, semester, initiation date or roll call range of a pledge class. Renaming updates the className of its members.
+//	@Description	Set firstRollCall and lastRollCall to 0 to remove the range, and initiationDate to "" to clear it. Officers only
+//	@Tags			Classes
+//	@Accept			json
+//	@Produce		json
+//	@Param			body_params	body		handlers.UpdateClass.RequestBody	true	"Values to change"
+//	@Param			classID		path		int									true	"Class ID"
+//	@Success		200			{object}	models.APIResponse{data=models.PledgeClass}
+//	@Failure		400			{object}	models.APIResponse
+//	@Failure		401			{object}	models.APIResponse
+//	@Failure		403			{object}	models.APIResponse
+//	@Failure		404			{object}	models.APIResponse
+//	@Failure		409			{object}	models.APIResponse
+//	@Failure		422			{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/classes/{classID} [patch]
+func (h *Handler) UpdateClass(w http.ResponseWriter, r *http.Request) {
+    if !canWriteClasses(w, r) {
+        return
+    }
+    classID, ok := idParam(w, r, "classID", "class ID")
+    if !ok {
+        return
+    }
+
+    // Expected request body data. Omitted values are left unchanged
+    type RequestBody struct {
+        Name           *string `json:"name"`
+        Semester       *string `json:"semester"`
+        InitiationDate *string `json:"initiationDate"`
+        FirstRollCall  *int    `json:"firstRollCall" validate:"omitempty,min=0"`
+        LastRollCall   *int    `json:"lastRollCall" validate:"omitempty,min=0"`
+    }
+    var requestBody RequestBody
+    if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+        respondWithDecodeError(w, r, err)
+        return
+    }
+    if err := validate.Struct(requestBody); err != nil {
+        respondWithValidationError(w, r, err)
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    tx, err := h.db.BeginTx(ctx, nil)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while starting transaction")
+        return
+    }
+    defer tx.Rollback()
+
+    if err := lockClasses(r, tx); err != nil {
+        respondWithDBError(w, r, err, "Error while locking pledge classes")
+        return
+    }
+    class, err := store.GetPledgeClass(ctx, tx, classID)
+    if errors.Is(err, sql.ErrNoRows) {
+        respondWithClassNotFound(w, r, classID)
+        return
+    }
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while querying pledge class")
+        return
+    }
+
+    if requestBody.Name != nil {
+        class.Name = *requestBody.Name
+    }
+    if requestBody.Semester != nil {
+        class.Semester = *requestBody.Semester
+    }
+    if requestBody.InitiationDate != nil {
+        class.InitiationDate = *requestBody.InitiationDate
+    }
+    if requestBody.FirstRollCall != nil {
+        class.FirstRollCall = requestBody.FirstRollCall
+        if *requestBody.FirstRollCall == 0 {
+            class.FirstRollCall = nil
+        }
+    }
+    if requestBody.LastRollCall != nil {
+        class.LastRollCall = requestBody.LastRollCall
+        if *requestBody.LastRollCall == 0 {
+            class.LastRollCall = nil
+        }
+    }
+    if !validClass(w, r, &class) || !h.classRangeFits(w, r, tx, class) {
+        return
+    }
+
+    updated, err := store.UpdatePledgeClass(ctx, tx, class)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while updating pledge class")
+        return
+    }
+    if err := tx.Commit(); err != nil {
+        respondWithDBError(w, r, err, "Error while committing pledge class")
+        return
+    }
+
+    models.RespondWithSuccess(w, http.StatusOK, updated)
+}
+
+// DELETE /api/classes/{classID}
+//	@Summary		Delete a pledge class
+//	@Description	Delete a pledge class without members. Move or remove its members first. Officers only
+//	@Tags			Classes
+//	@Produce		json
+//	@Param			classID	path		int		true	"Class ID"
+//	@Success		200		{object}	models.APIResponse{data=models.PledgeClass}
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		401		{object}	models.APIResponse
+//	@Failure		403		{object}	models.APIResponse
+//	@Failure		404		{object}	models.APIResponse
+//	@Failure		409		{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/classes/{classID} [delete]
+func (h *Handler) DeleteClass(w http.ResponseWriter, r *http.Request) {
+    if !canWriteClasses(w, r) {
+        return
+    }
+    classID, ok := idParam(w, r, "classID", "class ID")
+    if !ok {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    members, err := store.CountClassMembers(ctx, h.db, classID)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while counting class members")
+        return
+    }
+    if members > 0 {
+        errMsg := fmt.Sprintf("Pledge class %d still has %d members", classID, members)
+        slog.InfoContext(r.Context(), errMsg)
+        models.RespondWithFailCode(w, http.StatusConflict, models.CodeConflict, errMsg)
+        return
+    }
+
+    deleted, err := store.DeletePledgeClass(ctx, h.db, classID)
+    if errors.Is(err, sql.ErrNoRows) {
+        respondWithClassNotFound(w, r, classID)
+        return
+    }
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while deleting pledge class")
+        return
+    }
+    slog.InfoContext(r.Context(), "Deleted pledge class", "class_id", deleted.ClassID, "name", deleted.Name)
+
+    models.RespondWithSuccess(w, http.StatusOK, deleted)
+}
+
+// GET /api/classes/{classID}/brothers
+//	@Summary		Get the members of a pledge class
+//	@Description	Get a pledge class with its members in roll call order, their number per status and their attendance rate
+//	@Tags			Classes
+//	@Produce		json
+//	@Param			classID	path		int		true	"Class ID"
+//	@Success		200		{object}	models.APIResponse{data=models.ClassRoster}
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		404		{object}	models.APIResponse
+//	@Router			/api/classes/{classID}/brothers [get]
+func (h *Handler) GetClassBrothers(w http.ResponseWriter, r *http.Request) {
+    classID, ok := idParam(w, r, "classID", "class ID")
+    if !ok {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    class, err := store.GetPledgeClass(ctx, h.db, classID)
+    if errors.Is(err, sql.ErrNoRows) {
+        respondWithClassNotFound(w, r, classID)
+        return
+    }
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while querying pledge class")
+        return
+    }
+    brothers, err := store.ClassBrothers(ctx, h.db, classID)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while querying class members")
+        return
+    }
+    stats := store.ClassStatsOf(brothers)
+    if stats.AttendanceRate, err = store.ClassAttendanceRate(ctx, h.db, classID); err != nil {
+        respondWithDBError(w, r, err, "Error while querying class attendance")
+        return
+    }
+
+    models.RespondWithSuccess(w, http.StatusOK, models.ClassRoster{Class: class, Stats: stats, Brothers: brothers})
+}
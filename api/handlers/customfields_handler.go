@@ -0,0 +1,312 @@
+// customfields_handler.go: Handle requests to define the custom fields admins track on brothers
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	apimiddleware "github.com/pacific-theta-tau/tt-db/api/middleware"
+	"github.com/pacific-theta-tau/tt-db/api/models"
+	"github.com/pacific-theta-tau/tt-db/store"
+)
+
+// Responds with 401 or 403 and returns false unless the signed in user is an admin. Only admins define custom fields
+func canDefineCustomFields(w http.ResponseWriter, r *http.Request) bool {
+    if apimiddleware.CanAdminister(r.Context()) {
+        return true
+    }
+    respondWithDenied(w, r, "Non-admin tried to change a custom field", "Only admins can change custom fields")
+    return false
+}
+
+// Parses the field ID from the URL. Responds with an error and returns false if it is invalid
+func customFieldParam(w http.ResponseWriter, r *http.Request) (int, bool) {
+    fieldID, err := strconv.Atoi(chi.URLParam(r, "fieldID"))
+    if err != nil {
+        respondWithInvalidParam(w, r, "custom field ID", err)
+        return 0, false
+    }
+    return fieldID, true
+}
+
+// Responds with 404 for a custom field that doesn't exist
+func respondWithCustomFieldNotFound(w http.ResponseWriter, r *http.Request, fieldID int) {
+    errMsg := fmt.Sprintf("Custom field %d not found", fieldID)
+    slog.InfoContext(r.Context(), errMsg)
+    models.RespondWithFail(w, http.StatusNotFound, errMsg)
+}
+
+// GET /api/custom-fields
+//	@Summary		Get custom fields
+//	@Description	Get the definitions of the custom fields on Brothers, in the order they were added
+//	@Tags			Custom Fields
+//	@Produce		json
+//	@Success		200		{object}	models.APIResponse{data=[]models.CustomField}
+//	@Failure		500		{object}	models.APIResponse
+//	@Router			/api/custom-fields [get]
+func (h *Handler) GetCustomFields(w http.ResponseWriter, r *http.Request) {
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    fields, err := store.CustomFields(ctx, h.db)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while querying custom fields")
+        return
+    }
+
+    models.RespondWithSuccess(w, http.StatusOK, fields)
+}
+
+// POST /api/custom-fields
+//	@Summary		Add a custom field
+//	@Description	Define a new custom field on Brothers. Admins only. A required field can only be added while no brothers exist, or every new brother would need it first; add it as optional, fill it in, then make it required
+//	@Tags			Custom Fields
+//	@Accept			json
+//	@Produce		json
+//	@Param			body_params body		models.CustomField	true	"Field to add"
+//	@Success		201		{object}	models.APIResponse{data=models.CustomField}
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		401		{object}	models.APIResponse
+//	@Failure		403		{object}	models.APIResponse
+//	@Failure		409		{object}	models.APIResponse
+//	@Failure		422		{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/custom-fields [post]
+func (h *Handler) CreateCustomField(w http.ResponseWriter, r *http.Request) {
+    if !canDefineCustomFields(w, r) {
+        return
+    }
+
+    var field models.CustomField
+    if err := json.NewDecoder(r.Body).Decode(&field); err != nil {
+        respondWithDecodeError(w, r, err)
+        return
+    }
+    if err := validate.Struct(field); err != nil {
+        respondWithValidationError(w, r, err)
+        return
+    }
+    if fieldErrors := store.ValidateCustomField(field); len(fieldErrors) > 0 {
+        models.RespondWithValidationErrors(w, fieldErrors)
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    tx, err := h.db.BeginTx(ctx, nil)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while starting transaction")
+        return
+    }
+    defer tx.Rollback()
+
+    if field.Required {
+        missing, err := store.CountMissingCustomField(ctx, tx, field.Name)
+        if err != nil {
+            respondWithDBError(w, r, err, "Error while counting brothers without the custom field")
+            return
+        }
+        if missing > 0 {
+            errMsg := fmt.Sprintf("%d brothers have no value for %s. Add it as optional and fill it in first", missing, field.Name)
+            slog.InfoContext(r.Context(), errMsg)
+            models.RespondWithFail(w, http.StatusConflict, errMsg)
+            return
+        }
+    }
+
+    created, err := store.InsertCustomField(ctx, tx, field)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while inserting custom field")
+        return
+    }
+    if err := tx.Commit(); err != nil {
+        respondWithDBError(w, r, err, "Error while committing custom field")
+        return
+    }
+    slog.InfoContext(r.Context(), "Added custom field", "field_id", created.FieldID, "name", created.Name, "type", created.Type)
+
+    location := fmt.Sprintf("/api/custom-fields/%d", created.FieldID)
+    models.RespondWithCreated(w, location, created)
+}
+
+// PATCH /api/custom-fields/{fieldID}
+//	@Summary		Update a custom field
+//	@Description	Change the label, options or required flag of a custom field. Admins only. The name and type can't change. Options still used by a brother can't be removed, and a field can't become required while a brother has no value for it
+//	@Tags			Custom Fields
+//	@Accept			json
+//	@Produce		json
+//	@Param			body_params body		handlers.UpdateCustomField.RequestBody	true	"Values to change"
+//	@Param			fieldID	path		int										true	"Custom field ID"
+//	@Success		200		{object}	models.APIResponse{data=models.CustomField}
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		401		{object}	models.APIResponse
+//	@Failure		403		{object}	models.APIResponse
+//	@Failure		404		{object}	models.APIResponse
+//	@Failure		409		{object}	models.APIResponse
+//	@Failure		422		{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/custom-fields/{fieldID} [patch]
+func (h *Handler) UpdateCustomField(w http.ResponseWriter, r *http.Request) {
+    if !canDefineCustomFields(w, r) {
+        return
+    }
+    fieldID, ok := customFieldParam(w, r)
+    if !ok {
+        return
+    }
+
+    // Expected request body data. Omitted values are left unchanged
+    type RequestBody struct {
+        Label    *string   `json:"label" validate:"omitempty,max=100"`
+        Options  *[]string `json:"options" validate:"omitempty,unique,dive,required,max=100"`
+        Required *bool     `json:"required"`
+    }
+    var requestBody RequestBody
+    if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+        respondWithDecodeError(w, r, err)
+        return
+    }
+    if err := validate.Struct(requestBody); err != nil {
+        respondWithValidationError(w, r, err)
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    tx, err := h.db.BeginTx(ctx, nil)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while starting transaction")
+        return
+    }
+    defer tx.Rollback()
+
+    field, err := store.GetCustomField(ctx, tx, fieldID)
+    if errors.Is(err, sql.ErrNoRows) {
+        respondWithCustomFieldNotFound(w, r, fieldID)
+        return
+    }
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while querying custom field")
+        return
+    }
+
+    previous := field
+    if requestBody.Label != nil {
+        field.Label = *requestBody.Label
+    }
+    if requestBody.Options != nil {
+        field.Options = *requestBody.Options
+    }
+    if requestBody.Required != nil {
+        field.Required = *requestBody.Required
+    }
+    if fieldErrors := store.ValidateCustomField(field); len(fieldErrors) > 0 {
+        models.RespondWithValidationErrors(w, fieldErrors)
+        return
+    }
+
+    // Brothers must keep valid values: removed options can't be in use, and a new requirement must already be met
+    var removed []string
+    for _, option := range previous.Options {
+        if !slices.Contains(field.Options, option) {
+            removed = append(removed, option)
+        }
+    }
+    if len(removed) > 0 {
+        uses, err := store.CountCustomFieldValues(ctx, tx, field.Name, removed)
+        if err != nil {
+            respondWithDBError(w, r, err, "Error while counting custom field values")
+            return
+        }
+        if uses > 0 {
+            errMsg := fmt.Sprintf("%d brothers still have a removed option of %s", uses, field.Name)
+            slog.InfoContext(r.Context(), errMsg, "removed", removed)
+            models.RespondWithFail(w, http.StatusConflict, errMsg)
+            return
+        }
+    }
+    if field.Required && !previous.Required {
+        missing, err := store.CountMissingCustomField(ctx, tx, field.Name)
+        if err != nil {
+            respondWithDBError(w, r, err, "Error while counting brothers without the custom field")
+            return
+        }
+        if missing > 0 {
+            errMsg := fmt.Sprintf("%d brothers have no value for %s", missing, field.Name)
+            slog.InfoContext(r.Context(), errMsg)
+            models.RespondWithFail(w, http.StatusConflict, errMsg)
+            return
+        }
+    }
+
+    updated, err := store.UpdateCustomField(ctx, tx, field)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while updating custom field")
+        return
+    }
+    if err := tx.Commit(); err != nil {
+        respondWithDBError(w, r, err, "Error while committing custom field")
+        return
+    }
+
+    models.RespondWithSuccess(w, http.StatusOK, updated)
+}
+
+// DELETE /api/custom-fields/{fieldID}
+//	@Summary		Delete a custom field
+//	@Description	Delete a custom field and its value on every Brother. Admins only
+//	@Tags			Custom Fields
+//	@Produce		json
+//	@Param			fieldID	path		int		true	"Custom field ID"
+//	@Success		200		{object}	models.APIResponse{data=models.CustomField}
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		401		{object}	models.APIResponse
+//	@Failure		403		{object}	models.APIResponse
+//	@Failure		404		{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/custom-fields/{fieldID} [delete]
+func (h *Handler) DeleteCustomField(w http.ResponseWriter, r *http.Request) {
+    if !canDefineCustomFields(w, r) {
+        return
+    }
+    fieldID, ok := customFieldParam(w, r)
+    if !ok {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    tx, err := h.db.BeginTx(ctx, nil)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while starting transaction")
+        return
+    }
+    defer tx.Rollback()
+
+    deleted, err := store.DeleteCustomField(ctx, tx, fieldID)
+    if errors.Is(err, sql.ErrNoRows) {
+        respondWithCustomFieldNotFound(w, r, fieldID)
+        return
+    }
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while deleting custom field")
+        return
+    }
+    if err := tx.Commit(); err != nil {
+        respondWithDBError(w, r, err, "Error while committing custom field deletion")
+        return
+    }
+    slog.InfoContext(r.Context(), "Deleted custom field", "field_id", deleted.FieldID, "name", deleted.Name)
+
+    models.RespondWithSuccess(w, http.StatusOK, deleted)
+}
@@ -0,0 +1,18 @@
+// database_handler.go: Handle requests about the database connection itself
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+)
+
+// GET /api/db/stats
+//	@Summary		Get database pool statistics
+//	@Description	Get open, in-use and idle connections and wait statistics of the database connection pool
+//	@Tags			Database
+//	@Success		200		object		models.APIResponse{data=models.PoolStats}
+//	@Router			/api/db/stats [get]
+func (h *Handler) GetDatabaseStats(w http.ResponseWriter, r *http.Request) {
+    models.RespondWithSuccess(w, http.StatusOK, models.NewPoolStats(h.db.Stats()))
+}
@@ -0,0 +1,337 @@
+// duplicates_handler.go: Handle requests for finding and merging duplicate Brother records
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+	"github.com/pacific-theta-tau/tt-db/db"
+	"github.com/pacific-theta-tau/tt-db/store"
+)
+
+// Maximum edit distance between two full names to consider them similar
+const maxNameDistance = 2
+
+// Phone numbers with fewer digits than this are ignored when matching (e.g. placeholder "0")
+const minPhoneDigits = 7
+
+// Ranks attendance statuses so the best one is kept when both brothers have a record for the same event
+const attendanceRankSQL = `(CASE %s WHEN 'Present' THEN 3 WHEN 'Excused' THEN 2 WHEN 'Absent' THEN 1 ELSE 0 END)`
+
+// Lowercases a name and collapses whitespace so "  John  Smith" matches "john smith"
+func normalizeName(firstName string, lastName string) string {
+	return strings.Join(strings.Fields(strings.ToLower(firstName+" "+lastName)), " ")
+}
+
+// Keeps only the digits of a phone number so "(209) 555-0100" matches "2095550100"
+func normalizePhone(phone string) string {
+	var b strings.Builder
+	for _, r := range phone {
+		if unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Levenshtein distance between two strings
+func levenshtein(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// Returns the reasons two brothers are likely the same person, or nil if they are not
+func duplicateReasons(a models.Brother, b models.Brother) []string {
+	var reasons []string
+
+	nameA := normalizeName(a.FirstName, a.LastName)
+	nameB := normalizeName(b.FirstName, b.LastName)
+	if nameA == nameB {
+		reasons = append(reasons, models.DuplicateSameName)
+	} else if len(nameA) > maxNameDistance*2 && levenshtein(nameA, nameB) <= maxNameDistance {
+		reasons = append(reasons, models.DuplicateSimilarName)
+	}
+
+	emailA := strings.ToLower(strings.TrimSpace(a.Email))
+	if emailA != "" && emailA == strings.ToLower(strings.TrimSpace(b.Email)) {
+		reasons = append(reasons, models.DuplicateSameEmail)
+	}
+
+	phoneA := normalizePhone(a.PhoneNumber)
+	if len(phoneA) >= minPhoneDigits && phoneA == normalizePhone(b.PhoneNumber) {
+		reasons = append(reasons, models.DuplicateSamePhone)
+	}
+
+	return reasons
+}
+
+// Compares every pair of brothers and returns the likely duplicates, most reasons first
+func findDuplicateCandidates(brothers []models.Brother) []models.DuplicateCandidate {
+	candidates := []models.DuplicateCandidate{}
+	for i := 0; i < len(brothers); i++ {
+		for j := i + 1; j < len(brothers); j++ {
+			reasons := duplicateReasons(brothers[i], brothers[j])
+			if len(reasons) == 0 {
+				continue
+			}
+			candidates = append(candidates, models.DuplicateCandidate{
+				Brothers: []models.Brother{brothers[i], brothers[j]},
+				Reasons:  reasons,
+			})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return len(candidates[i].Reasons) > len(candidates[j].Reasons)
+	})
+	return candidates
+}
+
+// GET /api/brothers/duplicates
+//	@Summary		Find duplicate Brothers
+//	@Description	Find pairs of Brother records that likely belong to the same person (same name, email, phone number or a similar name)
+//	@Tags			Brothers
+//	@Success		200		object		models.APIResponse{data=[]models.DuplicateCandidate}
+//	@Failure		500		{object}	models.APIResponse
+//	@Router			/api/brothers/duplicates [get]
+func (h *Handler) GetDuplicateBrothers(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := requestContext(r)
+	defer cancel()
+
+	query := "SELECT " + store.BrotherColumns + " FROM brothers ORDER BY brotherID"
+	rows, err := h.db.QueryContext(ctx, query)
+	if err != nil {
+		respondWithDBError(w, r, err, "Error while querying brothers for duplicates")
+		return
+	}
+	defer rows.Close()
+
+	var brothers []models.Brother
+	for rows.Next() {
+		brother, err := store.ScanBrother(rows)
+		if err != nil {
+			respondWithInternalError(w, r, err, "Error creating Brother object from row")
+			return
+		}
+		brothers = append(brothers, brother)
+	}
+	if err := rows.Err(); err != nil {
+		respondWithDBError(w, r, err, "Error while reading brothers for duplicates")
+		return
+	}
+
+	models.RespondWithSuccess(w, http.StatusOK, findDuplicateCandidates(brothers))
+}
+
+// POST /api/brothers/merge
+//	@Summary		Merge duplicate Brothers
+//	@Description	Move all attendance, status records, notes, attachments, tags, position terms and littles of the duplicate onto the survivor and delete the duplicate.
+//	@Description	Conflicting attendance keeps the best status (Present > Excused > Absent); conflicting semester statuses keep the survivor's.
+//	@Description	Empty email, phone number, class, custom fields and big of the survivor are filled in from the duplicate.
+//	@Tags			Brothers
+//	@Param			survivorID	body	int	true	"BrotherID to keep"
+//	@Param			duplicateID	body	int	true	"BrotherID to merge and delete"
+//	@Success		200		object		models.APIResponse{data=models.BrotherMerge}
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		404		{object}	models.APIResponse
+//	@Router			/api/brothers/merge [post]
+func (h *Handler) MergeBrothers(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := requestContext(r)
+	defer cancel()
+
+	var requestBody struct {
+		SurvivorID  int `json:"survivorID" validate:"required"`
+		DuplicateID int `json:"duplicateID" validate:"required"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil {
+		respondWithDecodeError(w, r, err)
+		return
+	}
+	if err := validate.Struct(requestBody); err != nil {
+		respondWithValidationError(w, r, err)
+		return
+	}
+	if requestBody.SurvivorID == requestBody.DuplicateID {
+		respondWithFieldError(w, r, "duplicateID", "nefield", "duplicateID must be different from survivorID")
+		return
+	}
+
+	merge, err := mergeBrothers(ctx, h.db, requestBody.SurvivorID, requestBody.DuplicateID)
+	if err == sql.ErrNoRows {
+		errMsg := fmt.Sprintf("Brothers %d and %d must both exist", requestBody.SurvivorID, requestBody.DuplicateID)
+		slog.InfoContext(r.Context(), errMsg)
+		models.RespondWithFail(w, http.StatusNotFound, errMsg)
+		return
+	}
+	if err != nil {
+		respondWithDBError(w, r, err, "Error while merging brothers")
+		return
+	}
+
+	models.RespondWithSuccess(w, http.StatusOK, merge)
+}
+
+// Merges duplicateID into survivorID in a single transaction and records the merge in brotherMerges.
+// Returns sql.ErrNoRows if either brother does not exist
+func mergeBrothers(ctx context.Context, conn *db.DB, survivorID int, duplicateID int) (models.BrotherMerge, error) {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return models.BrotherMerge{}, err
+	}
+	defer tx.Rollback()
+
+	// Lock both rows so concurrent edits can't slip in between the moves
+	var locked int
+	err = tx.QueryRowContext(ctx, `
+    SELECT count(*) FROM (
+        SELECT brotherID FROM brothers WHERE brotherID IN ($1, $2) FOR UPDATE
+    ) b`, survivorID, duplicateID).Scan(&locked)
+	if err != nil {
+		return models.BrotherMerge{}, err
+	}
+	if locked != 2 {
+		return models.BrotherMerge{}, sql.ErrNoRows
+	}
+
+	// Snapshot the duplicate before it is deleted
+	var snapshot []byte
+	err = tx.QueryRowContext(ctx, `SELECT row_to_json(b) FROM brothers b WHERE brotherID = $1`, duplicateID).Scan(&snapshot)
+	if err != nil {
+		return models.BrotherMerge{}, err
+	}
+
+	// Attendance for the same event: keep the best status on the survivor's row
+	upgradeAttendance := fmt.Sprintf(`
+    UPDATE attendance s SET attendanceStatus = d.attendanceStatus
+    FROM attendance d
+    WHERE s.brotherID = $1 AND d.brotherID = $2 AND s.eventID = d.eventID
+        AND %s > %s`,
+		fmt.Sprintf(attendanceRankSQL, "d.attendanceStatus"),
+		fmt.Sprintf(attendanceRankSQL, "s.attendanceStatus"),
+	)
+	if _, err := tx.ExecContext(ctx, upgradeAttendance, survivorID, duplicateID); err != nil {
+		return models.BrotherMerge{}, err
+	}
+
+	// Keep big/little links from changing while the cycle check below runs
+	if err := store.LockLineage(ctx, tx); err != nil {
+		return models.BrotherMerge{}, err
+	}
+
+	var attendanceConflicts, rushConflicts, statusConflicts, notesMoved, attachmentsMoved, sharedTags, tagsMoved, termsMoved, cyclicLinks, sharedBigs, littlesMoved, bigsMoved, candidatesMoved int
+	merge := models.BrotherMerge{SurvivorID: survivorID, MergedBrotherID: duplicateID}
+	steps := []struct {
+		query   string
+		counter *int
+	}{
+		// A rush record of the duplicate's candidate replaces the survivor's row, taking its resolved status
+		{`UPDATE attendance d SET attendanceStatus = s.attendanceStatus FROM attendance s
+          WHERE d.brotherID = $2 AND s.brotherID = $1 AND s.eventID = d.eventID
+              AND d.candidateID IS NOT NULL AND s.candidateID IS NULL`, &rushConflicts},
+		{`DELETE FROM attendance s USING attendance d
+          WHERE s.brotherID = $1 AND d.brotherID = $2 AND s.eventID = d.eventID
+              AND d.candidateID IS NOT NULL AND s.candidateID IS NULL`, &rushConflicts},
+		// Drop the duplicate's conflicting rows; the survivor's row already holds the resolved value
+		{`DELETE FROM attendance d USING attendance s
+          WHERE d.brotherID = $2 AND s.brotherID = $1 AND s.eventID = d.eventID`, &attendanceConflicts},
+		{`DELETE FROM brotherStatus d USING brotherStatus s
+          WHERE d.brotherID = $2 AND s.brotherID = $1 AND s.semesterID = d.semesterID`, &statusConflicts},
+		{`DELETE FROM brotherTags d USING brotherTags s
+          WHERE d.brotherID = $2 AND s.brotherID = $1 AND s.tagID = d.tagID`, &sharedTags},
+		// A big who is the other record or one of its descendants would form a cycle once the records are one
+		{`WITH RECURSIVE ds(brotherID) AS (
+              SELECT $1::int UNION SELECT l.littleID FROM lineage l JOIN ds ON l.bigID = ds.brotherID
+          ), dd(brotherID) AS (
+              SELECT $2::int UNION SELECT l.littleID FROM lineage l JOIN dd ON l.bigID = dd.brotherID
+          )
+          DELETE FROM lineage
+          WHERE (littleID = $1 AND bigID IN (SELECT brotherID FROM dd))
+              OR (littleID = $2 AND bigID IN (SELECT brotherID FROM ds))`, &cyclicLinks},
+		{`DELETE FROM lineage d USING lineage s WHERE d.littleID = $2 AND s.littleID = $1`, &sharedBigs},
+		// Move the remaining rows onto the survivor
+		{`UPDATE attendance SET brotherID = $1 WHERE brotherID = $2`, &merge.AttendanceMoved},
+		{`UPDATE brotherStatus SET brotherID = $1 WHERE brotherID = $2`, &merge.StatusesMoved},
+		{`UPDATE brotherNotes SET brotherID = $1 WHERE brotherID = $2`, &notesMoved},
+		{`UPDATE attachments SET brotherID = $1 WHERE brotherID = $2`, &attachmentsMoved},
+		{`UPDATE brotherTags SET brotherID = $1 WHERE brotherID = $2`, &tagsMoved},
+		{`UPDATE positionTerms SET brotherID = $1 WHERE brotherID = $2`, &termsMoved},
+		{`UPDATE lineage SET littleID = $1 WHERE littleID = $2`, &bigsMoved},
+		{`UPDATE lineage SET bigID = $1 WHERE bigID = $2`, &littlesMoved},
+		// A Brother comes from at most one candidate; the duplicate's candidate is unlinked when it is deleted
+		{`UPDATE candidates SET brotherID = $1
+          WHERE brotherID = $2 AND NOT EXISTS (SELECT 1 FROM candidates WHERE brotherID = $1)`, &candidatesMoved},
+	}
+	for _, step := range steps {
+		result, err := tx.ExecContext(ctx, step.query, survivorID, duplicateID)
+		if err != nil {
+			return models.BrotherMerge{}, err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return models.BrotherMerge{}, err
+		}
+		*step.counter = int(affected)
+	}
+	merge.ConflictsResolved = attendanceConflicts + rushConflicts + statusConflicts
+
+	// Fill in the survivor's empty contact fields and custom fields from the duplicate
+	_, err = tx.ExecContext(ctx, `
+    UPDATE brothers s SET
+        className = CASE WHEN s.classID IS NULL AND d.classID IS NOT NULL THEN d.className
+            ELSE COALESCE(NULLIF(s.className, ''), d.className) END,
+        classID = COALESCE(s.classID, d.classID),
+        email = COALESCE(NULLIF(s.email, ''), d.email),
+        phoneNumber = COALESCE(NULLIF(NULLIF(s.phoneNumber, ''), '0'), d.phoneNumber),
+        customFields = d.customFields || s.customFields
+    FROM brothers d
+    WHERE s.brotherID = $1 AND d.brotherID = $2`, survivorID, duplicateID)
+	if err != nil {
+		return models.BrotherMerge{}, err
+	}
+
+	// Records merged into the duplicate earlier now belong to the survivor
+	_, err = tx.ExecContext(ctx, `UPDATE brotherMerges SET survivorID = $1 WHERE survivorID = $2`, survivorID, duplicateID)
+	if err != nil {
+		return models.BrotherMerge{}, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM brothers WHERE brotherID = $1`, duplicateID); err != nil {
+		return models.BrotherMerge{}, err
+	}
+
+	err = tx.QueryRowContext(ctx, `
+    INSERT INTO brotherMerges (survivorID, mergedBrotherID, mergedBrother, attendanceMoved, statusesMoved, conflictsResolved)
+    VALUES ($1, $2, $3, $4, $5, $6)
+    RETURNING mergeID, mergedBrother, mergedAt`,
+		survivorID, duplicateID, snapshot, merge.AttendanceMoved, merge.StatusesMoved, merge.ConflictsResolved,
+	).Scan(&merge.MergeID, &merge.MergedBrother, &merge.MergedAt)
+	if err != nil {
+		return models.BrotherMerge{}, err
+	}
+
+	return merge, tx.Commit()
+}
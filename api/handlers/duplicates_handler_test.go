@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/pacific-theta-tau/tt-db/api/models"
+)
+
+// Merges duplicateID into survivorID through the merge endpoint
+func mergeTestBrothers(t *testing.T, survivorID int, duplicateID int) models.BrotherMerge {
+	t.Helper()
+	router := chi.NewRouter()
+	router.Post("/api/brothers/merge", handler.MergeBrothers)
+
+	req := newJSONRequest(t, "POST", "/api/brothers/merge", map[string]int{"survivorID": survivorID, "duplicateID": duplicateID})
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	checkResponseCode(t, 200, rr.Code)
+
+	var merge models.BrotherMerge
+	decodeData(t, rr, &merge)
+	return merge
+}
+
+func TestMergeBrothersResolvesConflicts(t *testing.T) {
+	ctx := context.Background()
+	survivor := insertTestBrother(t, "Survivor")
+	duplicate := insertTestBrother(t, "Duplicate")
+	shared, moved := insertTestEvent(t), insertTestEvent(t)
+	t.Cleanup(func() {
+		handler.db.ExecContext(context.Background(), `DELETE FROM brotherMerges WHERE mergedBrotherID = $1`, duplicate.BrotherID)
+	})
+
+	// Both were recorded at the shared event; only the duplicate at the other one
+	_, err := handler.db.ExecContext(ctx, `
+	INSERT INTO attendance (brotherID, eventID, attendanceStatus) VALUES ($1, $3, 'Absent'), ($2, $3, 'Present'), ($2, $4, 'Excused')`,
+		survivor.BrotherID, duplicate.BrotherID, shared, moved)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merge := mergeTestBrothers(t, survivor.BrotherID, duplicate.BrotherID)
+	if merge.AttendanceMoved != 1 || merge.ConflictsResolved != 1 {
+		t.Errorf("Expected 1 attendance record moved and 1 conflict resolved. Got %+v", merge)
+	}
+
+	var status string
+	err = handler.db.QueryRowContext(ctx, `SELECT attendanceStatus FROM attendance WHERE brotherID = $1 AND eventID = $2`,
+		survivor.BrotherID, shared).Scan(&status)
+	if err != nil || status != "Present" {
+		t.Errorf("Expected the conflict to keep Present. Got %q (%v)", status, err)
+	}
+	var records int
+	if err := handler.db.QueryRowContext(ctx, `SELECT count(*) FROM attendance WHERE brotherID = $1`, survivor.BrotherID).Scan(&records); err != nil || records != 2 {
+		t.Errorf("Expected the survivor to have 2 attendance records. Got %d (%v)", records, err)
+	}
+	var exists bool
+	if err := handler.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM brothers WHERE brotherID = $1)`, duplicate.BrotherID).Scan(&exists); err != nil || exists {
+		t.Errorf("Expected the duplicate to be deleted. Got exists %v (%v)", exists, err)
+	}
+}
+
+func TestMergeBrothersKeepsHistory(t *testing.T) {
+	ctx := context.Background()
+	first := insertTestBrother(t, "First")
+	second := insertTestBrother(t, "Second")
+	third := insertTestBrother(t, "Third")
+	t.Cleanup(func() {
+		handler.db.ExecContext(context.Background(), `DELETE FROM brotherMerges WHERE mergedBrotherID IN ($1, $2)`,
+			first.BrotherID, second.BrotherID)
+	})
+
+	earlier := mergeTestBrothers(t, second.BrotherID, first.BrotherID)
+	// The survivor of the first merge is merged in turn
+	mergeTestBrothers(t, third.BrotherID, second.BrotherID)
+
+	survivorOf := func(mergeID int) sql.NullInt64 {
+		t.Helper()
+		var survivorID sql.NullInt64
+		if err := handler.db.QueryRowContext(ctx, `SELECT survivorID FROM brotherMerges WHERE mergeID = $1`, mergeID).Scan(&survivorID); err != nil {
+			t.Fatalf("Expected merge %d to be kept: %v", mergeID, err)
+		}
+		return survivorID
+	}
+	if survivorID := survivorOf(earlier.MergeID); survivorID.Int64 != int64(third.BrotherID) {
+		t.Errorf("Expected the earlier merge to point to the new survivor %d. Got %v", third.BrotherID, survivorID)
+	}
+
+	// Deleting the survivor keeps the history without it
+	if _, err := handler.db.ExecContext(ctx, `DELETE FROM brothers WHERE brotherID = $1`, third.BrotherID); err != nil {
+		t.Fatal(err)
+	}
+	if survivorID := survivorOf(earlier.MergeID); survivorID.Valid {
+		t.Errorf("Expected no survivor once they are deleted. Got %v", survivorID)
+	}
+}
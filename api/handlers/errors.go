@@ -0,0 +1,250 @@
+// errors.go: Helpers to translate decoding, validation and database errors into API responses.
+// Internal error details are only logged and never sent to clients
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgconn"
+	apimiddleware "github.com/pacific-theta-tau/tt-db/api/middleware"
+	"github.com/pacific-theta-tau/tt-db/api/models"
+)
+
+// Postgres error codes. See: https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgUniqueViolation           = "23505"
+	pgForeignKeyViolation       = "23503"
+	pgCheckViolation            = "23514"
+	pgNotNullViolation          = "23502"
+	pgInvalidTextRepresentation = "22P02"
+)
+
+// Client-facing messages for known constraints, keyed by the constraint name Postgres reports
+var constraintMessages = map[string]string{
+	"attendance_brotherid_eventid_key":  "Attendance record already exists for this brother and event",
+	"brotherstatus_pkey":                "Status already exists for this brother and semester",
+	"attendance_attendancestatus_check": "attendanceStatus must be one of: Present, Absent, Excused",
+	"attendance_brotherid_fkey":         "Brother does not exist",
+	"attendance_eventid_fkey":           "Event does not exist",
+	"brotherstatus_brotherid_fkey":      "Brother does not exist",
+	"brotherstatus_semesterid_fkey":     "Semester does not exist",
+	"events_categoryid_fkey":            "Event category does not exist",
+	"brothernotes_brotherid_fkey":       "Brother does not exist",
+	"brothernotes_body_check":           "body must not be empty",
+	"brothers_rollcall_key":             "Roll call already belongs to another brother",
+	"semester_semesterlabel_key":        "Semester already exists",
+	"eventscategory_categoryname_key":   "Event category already exists",
+	"customfields_name_key":             "Custom field already exists",
+	"customfields_name_check":           "name must start with a letter and only contain letters, digits and underscores",
+	"tags_name_key":                     "Tag already exists",
+	"tags_name_check":                   "name must not be empty",
+	"brothertags_brotherid_fkey":        "Brother does not exist",
+	"brothertags_tagid_fkey":            "Tag does not exist",
+	"eventtags_eventid_fkey":            "Event does not exist",
+	"eventtags_tagid_fkey":              "Tag does not exist",
+	"positions_name_key":                "Position already exists",
+	"positions_name_check":              "name must not be empty",
+	"positionterms_brotherid_fkey":      "Brother does not exist",
+	"positionterms_positionid_fkey":     "Position does not exist",
+	"positionterms_range_check":         "endSemester must not be before startSemester",
+	"lineage_littleid_fkey":             "Brother does not exist",
+	"lineage_bigid_fkey":                "Big brother does not exist",
+	"lineage_self_check":                "A Brother can't be their own big",
+	"pledgeclasses_name_key":            "Pledge class already exists",
+	"pledgeclasses_rollcall_check":      "firstRollCall must be at least 1 and at most lastRollCall",
+	"brothers_classid_fkey":             "Pledge class does not exist",
+
+	// Rush candidates
+	"candidates_firstname_check":  "firstName must not be empty",
+	"candidates_lastname_check":   "lastName must not be empty",
+	"candidates_stage_check":      "stage must be one of: interested, attended, bid_extended, accepted, pledged",
+	"candidates_brotherid_key":    "Brother was already converted from another candidate",
+	"candidates_brotherid_fkey":   "Brother does not exist",
+	"attendance_candidateid_fkey": "Candidate does not exist",
+}
+
+// Shared validator for request bodies
+var validate = newValidator()
+
+// Create a validator that reports fields by their JSON name instead of the Go struct field name
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+	return v
+}
+
+// Log a database error and respond with the matching client error for constraint violations,
+// 504/503 when the request deadline passed or the request was cancelled, or a generic server error otherwise
+func respondWithDBError(w http.ResponseWriter, r *http.Request, err error, logMsg string) {
+	if errors.Is(err, context.Canceled) {
+		slog.WarnContext(r.Context(), logMsg, "error", err)
+		models.RespondWithError(w, http.StatusServiceUnavailable, "Request was cancelled")
+		return
+	}
+	if errors.Is(err, context.DeadlineExceeded) || pgconn.Timeout(err) {
+		slog.WarnContext(r.Context(), logMsg, "error", err)
+		models.RespondWithError(w, http.StatusGatewayTimeout, "Request timed out")
+		return
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		if status, code, message, ok := translatePgError(pgErr); ok {
+			slog.WarnContext(r.Context(), logMsg, "error", err, "pg_code", pgErr.Code, "constraint", pgErr.ConstraintName)
+			models.RespondWithFailCode(w, status, code, message)
+			return
+		}
+	}
+
+	slog.ErrorContext(r.Context(), logMsg, "error", err)
+	models.RespondWithError(w, http.StatusInternalServerError, "Internal server error")
+}
+
+// Map a Postgres error caused by the request's data to the client error to respond with.
+// ok is false for errors that aren't the client's fault
+func translatePgError(pgErr *pgconn.PgError) (status int, code string, message string, ok bool) {
+	message, known := constraintMessages[pgErr.ConstraintName]
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		if !known {
+			message = "A record with the same values already exists"
+		}
+		return http.StatusConflict, models.CodeConflict, message, true
+	case pgForeignKeyViolation:
+		if !known {
+			message = "Referenced record does not exist"
+		}
+		return http.StatusUnprocessableEntity, models.CodeInvalidReference, message, true
+	case pgCheckViolation, pgNotNullViolation:
+		if !known {
+			message = "Request violates a data constraint"
+		}
+		return http.StatusUnprocessableEntity, models.CodeConstraintViolation, message, true
+	case pgInvalidTextRepresentation:
+		return http.StatusUnprocessableEntity, models.CodeConstraintViolation, "Request contains an invalid value", true
+	}
+	return 0, "", "", false
+}
+
+// Log an internal error and respond with a generic server error
+func respondWithInternalError(w http.ResponseWriter, r *http.Request, err error, logMsg string) {
+	slog.ErrorContext(r.Context(), logMsg, "error", err)
+	models.RespondWithError(w, http.StatusInternalServerError, "Internal server error")
+}
+
+// Respond to a request body that could not be decoded. Type mismatches are reported per field
+func respondWithDecodeError(w http.ResponseWriter, r *http.Request, err error) {
+	slog.InfoContext(r.Context(), "Error decoding request body", "error", err)
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		fieldError := models.FieldError{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: fmt.Sprintf("%s must be a %s", typeErr.Field, jsonTypeName(typeErr.Type)),
+		}
+		models.RespondWithValidationErrors(w, []models.FieldError{fieldError})
+		return
+	}
+
+	models.RespondWithFailCode(w, http.StatusBadRequest, models.CodeInvalidBody, "Request body must be valid JSON")
+}
+
+// Respond to a request body that failed struct validation with one error per invalid field
+func respondWithValidationError(w http.ResponseWriter, r *http.Request, err error) {
+	slog.InfoContext(r.Context(), "Invalid request body", "error", err)
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		models.RespondWithFailCode(w, http.StatusBadRequest, models.CodeValidationFailed, "Invalid request parameters")
+		return
+	}
+
+	fieldErrors := make([]models.FieldError, 0, len(validationErrors))
+	for _, fe := range validationErrors {
+		fieldErrors = append(fieldErrors, models.FieldError{
+			Field:   fe.Field(),
+			Rule:    fe.Tag(),
+			Message: validationMessage(fe),
+		})
+	}
+	models.RespondWithValidationErrors(w, fieldErrors)
+}
+
+// Respond to a URL or query parameter that could not be parsed
+func respondWithInvalidParam(w http.ResponseWriter, r *http.Request, param string, err error) {
+	slog.InfoContext(r.Context(), "Invalid parameter", "param", param, "error", err)
+	models.RespondWithFailCode(w, http.StatusBadRequest, models.CodeInvalidParameter, fmt.Sprintf("Invalid %s", param))
+}
+
+// Human readable message for a single validation failure
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", fe.Field())
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", fe.Field(), strings.ReplaceAll(fe.Param(), " ", ", "))
+	case "email":
+		return fmt.Sprintf("%s must be a valid email address", fe.Field())
+	case "min", "gte":
+		return fmt.Sprintf("%s must be at least %s", fe.Field(), fe.Param())
+	case "max", "lte":
+		return fmt.Sprintf("%s must be at most %s", fe.Field(), fe.Param())
+	case "datetime":
+		return fmt.Sprintf("%s must be formatted as %s", fe.Field(), fe.Param())
+	}
+	return fmt.Sprintf("%s is invalid (%s)", fe.Field(), fe.Tag())
+}
+
+// JSON name of the type a request field is expected to have
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice, reflect.Array:
+		return "list"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+	return "string"
+}
+
+// Respond to a single invalid request field
+func respondWithFieldError(w http.ResponseWriter, r *http.Request, field string, rule string, message string) {
+	slog.InfoContext(r.Context(), "Invalid request field", "field", field, "message", message)
+	models.RespondWithValidationErrors(w, []models.FieldError{{Field: field, Rule: rule, Message: message}})
+}
+
+// Respond to a request the caller may not make: 401 asking anonymous callers to sign in, or 403 for signed in
+// users without the needed role. logMsg is logged with the user's ID
+func respondWithDenied(w http.ResponseWriter, r *http.Request, logMsg string, message string) {
+	user, ok := apimiddleware.UserFromContext(r.Context())
+	if !ok {
+		slog.InfoContext(r.Context(), "Anonymous request needs sign in", "method", r.Method, "path", r.URL.Path)
+		apimiddleware.RespondUnauthorized(w, "Sign in required. "+message)
+		return
+	}
+	slog.InfoContext(r.Context(), logMsg, "user_id", user.UserID)
+	models.RespondWithFail(w, http.StatusForbidden, message)
+}
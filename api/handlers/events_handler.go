@@ -6,21 +6,22 @@ import (
 	"database/sql"
 	"encoding/json"
     "io"
-	"log"
+	"log/slog"
 	"net/http"
     "strconv"
     "strings"
 	"time"
 
 	"github.com/pacific-theta-tau/tt-db/api/models"
+	"github.com/pacific-theta-tau/tt-db/logging"
+	"github.com/pacific-theta-tau/tt-db/store"
 	"github.com/go-chi/chi"
-    "github.com/go-playground/validator/v10"
 )
 
 const events_table = "events"
 
 // Helper function to scan SQL row and create new event instance
-func createEventFromRow(row *sql.Rows) (models.Event, error) {
+func createEventFromRow(row store.RowScanner) (models.Event, error) {
 	var events models.Event
 	err := row.Scan(
 		&events.EventID,
@@ -57,24 +58,33 @@ func createEventAttendanceFromRow(row *sql.Rows) (models.EventAttendance, error)
 //	@Summary		Get all event records
 //	@Description	Get data from all rows in events table
 //	@Tags			Events
+//	@Param			tag		query		[]string	false	"Only events with every one of these tags"	collectionFormat(multi)
 //	@Success		200		object		models.APIResponse{data=models.Event}
 //	@Failure		400		{object}	models.APIResponse
 //	@Router			/api/events [get]
 func (h *Handler) GetAllEvents(w http.ResponseWriter, r *http.Request) {
-	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	ctx, cancel := requestContext(r)
 	defer cancel()
 
 	query := `
-        SELECT e.eventid, e.eventName, ec.categoryName, e.eventLocation, e.eventDate
+        SELECT e.eventid, e.eventName, COALESCE(ec.categoryName, ''), e.eventLocation, e.eventDate
         FROM events e
-        JOIN eventsCategory ec ON e.categoryID = ec.categoryID
+        LEFT JOIN eventsCategory ec ON e.categoryID = ec.categoryID
     `
-	rows, err := h.db.QueryContext(ctx, query)
+    // Events must have every tag in ?tag=
+    var conditions []string
+    var args []interface{}
+    for _, tag := range r.URL.Query()["tag"] {
+        args = append(args, tag)
+        conditions = append(conditions, store.TagCondition(store.EventTags, "e.eventID", len(args)))
+    }
+    if len(conditions) > 0 {
+        query += " WHERE " + strings.Join(conditions, " AND ")
+    }
+	rows, err := h.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		// return error status code
-        errMsg := fmt.Sprintf("Error while querying events for events table: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDBError(w, r, err, "Error while querying events for events table")
 		return
 	}
 
@@ -83,9 +93,7 @@ func (h *Handler) GetAllEvents(w http.ResponseWriter, r *http.Request) {
 	for rows.Next() {
 		event, err := createEventFromRow(rows)
 		if err != nil {
-            errMsg := fmt.Sprintf("Error while parsing rows for events table: %s", err.Error())
-            log.Println(errMsg)
-            models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+            respondWithInternalError(w, r, err, "Error while parsing rows for events table")
 			return
 		}
 		events = append(events, &event)
@@ -103,38 +111,32 @@ func (h *Handler) GetAllEvents(w http.ResponseWriter, r *http.Request) {
 //	@Failure		400		{object}	models.APIResponse
 //	@Router			/api/events/{eventid} [get]
 func (h *Handler) GetEventByEventID(w http.ResponseWriter, r *http.Request) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	ctx, cancel := requestContext(r)
 	defer cancel()
 
 	requestEventID := chi.URLParam(r, "eventID")
 	if requestEventID == "" {
 		// If eventID is empty, return an error response
-        errMsg := fmt.Sprintf("Missing eventID parameter in query params")
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        respondWithInvalidParam(w, r, "event ID", fmt.Errorf("missing eventID"))
         return
 	}
 
     eventID, err := strconv.Atoi(requestEventID)
     if err != nil {
-        errMsg := fmt.Sprintf("Error while parsing eventID in query params: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusInternalServerError, errMsg)
+        respondWithInvalidParam(w, r, "event ID", err)
         return
     }
 
     event, err := queryEvent(h, ctx, eventID)
     if err != nil {
-        errMsg := fmt.Sprintf("Failed to query event with eventID %d: %s", eventID, err.Error())
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDBError(w, r, err, fmt.Sprintf("Failed to query event with eventID %d", eventID))
         return
     }
 
 	if event.EventID == 0 {
         errMsg := fmt.Sprintf("EventID %d not found", eventID)
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        slog.InfoContext(r.Context(), errMsg)
+        models.RespondWithFail(w, http.StatusNotFound, errMsg)
 		return
 	}
 
@@ -146,16 +148,16 @@ func (h *Handler) GetEventByEventID(w http.ResponseWriter, r *http.Request) {
 func queryEvent(h* Handler, ctx context.Context, eventID int) (models.Event, error) {
     // TODO: refactor so that we don't need to inject handler and context
     query := `
-        SELECT e.eventid, e.eventName, ec.categoryName, e.eventLocation, e.eventDate
+        SELECT e.eventid, e.eventName, COALESCE(ec.categoryName, ''), e.eventLocation, e.eventDate
         FROM events e
-        JOIN eventsCategory ec ON e.categoryID = ec.categoryID
+        LEFT JOIN eventsCategory ec ON e.categoryID = ec.categoryID
         WHERE eventID = $1
     `
 
 	row, err := h.db.QueryContext(ctx, query, eventID)
 	if err != nil {
         errMsg := fmt.Sprintf("\t[queryEvent()] Error while querying event data for eventID %d: %s", eventID, err.Error())
-		log.Println(errMsg)
+		slog.InfoContext(ctx, errMsg)
 		return models.Event{}, err
 	}
 	defer row.Close()
@@ -165,7 +167,7 @@ func queryEvent(h* Handler, ctx context.Context, eventID int) (models.Event, err
 	for row.Next() {
 		event, err = createEventFromRow(row)
 		if err != nil {
-            log.Printf("\t[queryEvent()] Error while parsing rows from database: %s", err.Error())
+            slog.ErrorContext(ctx, "Error while parsing event rows from database", "error", err)
 			return models.Event{}, err
 		}
 	}
@@ -173,56 +175,68 @@ func queryEvent(h* Handler, ctx context.Context, eventID int) (models.Event, err
     return event, nil
 }
 
+// Helper function to respond to a failed categoryID lookup. Unknown categories are a client error
+func respondWithCategoryError(w http.ResponseWriter, r *http.Request, err error, categoryName string) {
+    if err == sql.ErrNoRows {
+        errMsg := fmt.Sprintf("Event category '%s' does not exist", categoryName)
+        slog.InfoContext(r.Context(), errMsg)
+        models.RespondWithFailCode(w, http.StatusUnprocessableEntity, models.CodeInvalidReference, errMsg)
+        return
+    }
+    respondWithDBError(w, r, err, "Error while querying for categoryID")
+}
+
 // Add new event to events table
 //	@Summary		Create new event record
 //	@Description	Create new event record
 //	@Tags			Events
 //	@Param			body body models.Event true	"Values for new event record"
-//	@Success		200		{object}		models.APIResponse{data=models.Event}
+//	@Success		201		{object}		models.APIResponse{data=models.Event}
 //	@Failure		400		{object}	models.APIResponse
+//	@Failure		409		{object}	models.APIResponse
+//	@Failure		422		{object}	models.APIResponse
 //	@Router			/api/events [post]
 func (h* Handler) CreateEvent(w http.ResponseWriter, r *http.Request) {
-    ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+    ctx, cancel := requestContext(r)
     defer cancel()
 
     var event models.Event
     err := json.NewDecoder(r.Body).Decode(&event)
     if err != nil {
-        errMsg := fmt.Sprintf("Error decoding request body", err.Error())
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDecodeError(w, r, err)
         return
     }
 
     // Validate events struct
-    validate := validator.New() 
     if err := validate.Struct(event); err != nil {
-        errMsg := fmt.Sprintf("Error validating body params. Missing values: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        respondWithValidationError(w, r, err)
         return
 	}
 
     // 1. fetch categoryID from categoryName
-    log.Printf("\tQuerying for categoryID of %s", event.CategoryName)
+    slog.DebugContext(r.Context(), "Querying for categoryID", "category", event.CategoryName)
     var categoryID int
     query := "SELECT categoryID FROM eventsCategory WHERE categoryName = $1"
-    err = h.db.QueryRow(query, event.CategoryName).Scan(&categoryID)
+    err = h.db.QueryRowContext(ctx, query, event.CategoryName).Scan(&categoryID)
     if err != nil {
-        errMsg := fmt.Sprintf("Category not found. %v", err.Error())
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithCategoryError(w, r, err, event.CategoryName)
         return
     }
 
     // 2. Insert new event in `events` table
-    log.Println("\tInserting new event")
+    slog.DebugContext(r.Context(), "Inserting new event")
     query = `
-    INSERT INTO events (eventName, categoryID, eventLocation, eventDate)
-    VALUES ($1, $2, $3, $4) returning *
+    WITH inserted AS (
+        INSERT INTO events (eventName, categoryID, eventLocation, eventDate)
+        VALUES ($1, $2, $3, $4)
+        RETURNING eventID, eventName, categoryID, eventLocation, eventDate
+    )
+    SELECT i.eventID, i.eventName, COALESCE(ec.categoryName, ''), i.eventLocation, i.eventDate
+    FROM inserted i
+    LEFT JOIN eventsCategory ec ON ec.categoryID = i.categoryID
     `
-    log.Println(query)
-    _, err = h.db.ExecContext(
+    slog.DebugContext(r.Context(), "Query", "sql", query)
+    row := h.db.QueryRowContext(
         ctx,
         query,
         event.EventName,
@@ -230,10 +244,16 @@ func (h* Handler) CreateEvent(w http.ResponseWriter, r *http.Request) {
         event.EventLocation,
         event.EventDate,
     )
+    created, err := createEventFromRow(row)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while inserting new event")
+        return
+    }
 
     msg := "Created new event to `events` table successfully"
-    log.Println(msg)
-    models.RespondWithSuccess(w, http.StatusOK, "")
+    slog.InfoContext(r.Context(), msg)
+    location := fmt.Sprintf("/api/events/%d", created.EventID)
+    models.RespondWithCreated(w, location, created)
 }
 
 // Update fields of event row by event ID
@@ -241,47 +261,42 @@ func (h* Handler) CreateEvent(w http.ResponseWriter, r *http.Request) {
 //	@Description	Update event record by eventID
 //	@Tags			Events
 //	@Param			eventid		path int											true	"Event ID"
-//	@Success		200		object		models.APIResponse
+//	@Success		200		object		models.APIResponse{data=models.Event}
 //	@Failure		400		{object}	models.APIResponse
 //	@Router			/api/events/{eventid} [patch]
 func (h *Handler) UpdateEventByID(w http.ResponseWriter, r *http.Request) {
-    ctx, cancel :=  context.WithTimeout(context.Background(), dbTimeout)
+    ctx, cancel :=  requestContext(r)
     defer cancel()
 
     // Parse request body
     body, err := io.ReadAll(r.Body)
 	if err != nil {
-        errMsg := fmt.Sprintf("Error while reading request body: %s", err.Error())
-        log.Printf(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg) 
+        respondWithInternalError(w, r, err, "Error while reading request body")
         return
 	}
 
 	var requestBody map[string]interface{}
 	if err = json.Unmarshal(body, &requestBody); err != nil {
-        errMsg := "Error decoding JSON"
-        log.Printf(errMsg)
-        models.RespondWithFail(w, http.StatusInternalServerError, errMsg)
+        respondWithDecodeError(w, r, err)
 		return
 	}
 
     // Parse eventID from endpoint path
 	eventID, err := strconv.Atoi(chi.URLParam(r, "eventID"))
     if err != nil {
-        errMsg := fmt.Sprintf("Error while converting eventID string to int: %s", err.Error())
-        log.Printf(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithInvalidParam(w, r, "event ID", err)
         return
     }
     if eventID == 0 {
-        errMsg := fmt.Sprintf("Missing urlParam eventID: %s", eventID)
-        log.Printf(errMsg)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        errMsg := fmt.Sprintf("EventID %d not found", eventID)
+        slog.InfoContext(r.Context(), errMsg)
+        models.RespondWithFail(w, http.StatusNotFound, errMsg)
 		return
 	}
 
-    // Build query statement for each param in requestBody
-    updateQuery := fmt.Sprintf("UPDATE %s SET", events_table)
+    // Build query statement for each param in requestBody, binding every value as a parameter
+    var setClauses []string
+    var args []interface{}
     columns := []string{
         "eventName",
         "categoryName",
@@ -298,55 +313,45 @@ func (h *Handler) UpdateEventByID(w http.ResponseWriter, r *http.Request) {
             // TODO: refactor this into own function to avoid duplicate
             var categoryID int
             categoryIdQuery := "SELECT categoryID FROM eventsCategory WHERE categoryName = $1"
-            err = h.db.QueryRow(categoryIdQuery, newColumnValue).Scan(&categoryID)
+            err = h.db.QueryRowContext(ctx, categoryIdQuery, newColumnValue).Scan(&categoryID)
             if err != nil {
-                errMsg := fmt.Sprintf("\tError while querying for Category - not found: %s", err.Error())
-                log.Println(errMsg)
-                models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+                respondWithCategoryError(w, r, err, fmt.Sprint(newColumnValue))
                 return
             }
-            updateQuery += fmt.Sprintf(" %s = %d,", "categoryID", categoryID)
+            args = append(args, categoryID)
+            setClauses = append(setClauses, fmt.Sprintf("categoryID = $%d", len(args)))
             continue
         }
 
-        updateQuery += fmt.Sprintf(" %s = '%s',", column, newColumnValue)
+        args = append(args, newColumnValue)
+        setClauses = append(setClauses, fmt.Sprintf("%s = $%d", column, len(args)))
     }
-
-    // Remove trailling comma
-    updateQuery = strings.TrimRight(updateQuery, ",") 
-    // UPDATE events SET (values) FROM eventsCategory ...
-    updateQuery += `
-        FROM eventsCategory ec
-        WHERE eventID = $1 AND events.categoryID = ec.categoryID
-        RETURNING events.eventID, events.eventName, events.eventDate, events.eventLocation, ec.categoryName
-    `
-    log.Printf("UPDATE query: %s", updateQuery)
+    args = append(args, eventID)
+
+    // Join the updated row with eventsCategory so the new categoryName is returned
+    updateQuery := fmt.Sprintf(`
+    WITH updated AS (
+        UPDATE %s SET %s
+        WHERE eventID = $%d
+        RETURNING eventID, eventName, categoryID, eventLocation, eventDate
+    )
+    SELECT u.eventID, u.eventName, COALESCE(ec.categoryName, ''), u.eventLocation, u.eventDate
+    FROM updated u
+    LEFT JOIN eventsCategory ec ON ec.categoryID = u.categoryID
+    `, events_table, strings.Join(setClauses, ", "), len(args))
+    slog.DebugContext(r.Context(), "Query", "sql", updateQuery)
 
     // Query Database
-    var queryResult models.Event
-    err = h.db.QueryRowContext(ctx, updateQuery, eventID).Scan(
-        &queryResult.EventID,
-        &queryResult.EventName,
-        &queryResult.EventDate,
-        &queryResult.EventLocation,
-        &queryResult.CategoryName,
-    )
-    if err != nil {
-        errMsg := fmt.Sprintf("Error while querying update: %s", err.Error())
-        log.Printf(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
-		return
+    event, err := createEventFromRow(h.db.QueryRowContext(ctx, updateQuery, args...))
+    if err == sql.ErrNoRows {
+        errMsg := fmt.Sprintf("EventID %d not found", eventID)
+        slog.InfoContext(r.Context(), errMsg)
+        models.RespondWithFail(w, http.StatusNotFound, errMsg)
+        return
     }
-    log.Printf("query result: %+v", queryResult)
-
-    // Get modified row
-    // TODO: use RETURNING row clause instead of querying for the modified row
-    event, err := queryEvent(h, ctx, eventID)
     if err != nil {
-        errMsg := fmt.Sprintf("Failed to query event with eventID %d after update query: %s", eventID, err)
-        log.Printf(errMsg)
-        models.RespondWithFail(w, http.StatusInternalServerError, errMsg)
-        return
+        respondWithDBError(w, r, err, "Error while querying update")
+		return
     }
 
     models.RespondWithSuccess(w, http.StatusOK, event)
@@ -360,7 +365,7 @@ func (h *Handler) UpdateEventByID(w http.ResponseWriter, r *http.Request) {
 //	@Failure		400		{object}	models.APIResponse
 //	@Router			/api/events [delete]
 func (h *Handler) DeleteEventByEventID(w http.ResponseWriter, r *http.Request) {
-    ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+    ctx, cancel := requestContext(r)
 	defer cancel()
 
     var requestBody struct {
@@ -368,33 +373,24 @@ func (h *Handler) DeleteEventByEventID(w http.ResponseWriter, r *http.Request) {
     }
     err := json.NewDecoder(r.Body).Decode(&requestBody)
 	if err != nil {
-        errMsg := fmt.Sprint("Error decoding request body: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusInternalServerError, errMsg)
+        respondWithDecodeError(w, r, err)
         return
 	}
 
-    validate := validator.New()
     if err := validate.Struct(requestBody); err != nil {
-        errMsg := fmt.Sprintf("Invalid Input: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        respondWithValidationError(w, r, err)
         return
     }
 
 	if requestBody.EventID == 0 { 
-        errMsg := fmt.Sprint("Invalid eventID 0")
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        respondWithFieldError(w, r, "eventID", "required", "eventID is required")
 		return
 	}
 
 	query := fmt.Sprintf("DELETE from %s WHERE eventID = $1", events_table)
 	_, err = h.db.ExecContext(ctx, query, requestBody.EventID)
 	if err != nil {
-        errMsg := fmt.Sprint("Error during query: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusInternalServerError, errMsg)
+        respondWithDBError(w, r, err, "Error while deleting event")
 		return
 	}
 
@@ -414,21 +410,23 @@ func (h *Handler) GetEventAttendance(w http.ResponseWriter, r *http.Request) {
     eventIDStr := chi.URLParam(r, "eventID")
     eventID, err := strconv.Atoi(eventIDStr)
     if err != nil {
-        errMsg := "Invalid event ID"
-        log.Printf(errMsg, err)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        respondWithInvalidParam(w, r, "event ID", err)
         return
     }
 
-    ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+    ctx, cancel := requestContext(r)
     defer cancel()
 
     // Query event data
     eventData, err := queryEvent(h, ctx, eventID)
     if err != nil {
-        errMsg := fmt.Sprintf("Error while fetching event data for eventID %d: %s", eventID, err.Error())
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDBError(w, r, err, fmt.Sprintf("Error while fetching event data for eventID %d", eventID))
+        return
+    }
+    if eventData.EventID == 0 {
+        errMsg := fmt.Sprintf("EventID %d not found", eventID)
+        slog.InfoContext(r.Context(), errMsg)
+        models.RespondWithFail(w, http.StatusNotFound, errMsg)
         return
     }
 
@@ -439,12 +437,10 @@ func (h *Handler) GetEventAttendance(w http.ResponseWriter, r *http.Request) {
     JOIN brothers b ON b.brotherID = a.brotherID
     WHERE a.eventID = $1
     `
-    log.Printf("\tEventID: %d", eventID)
+    slog.DebugContext(r.Context(), "Parsed event ID", "eventID", eventID)
     rows, err := h.db.QueryContext(ctx, query, eventID)
 	if err != nil {
-        errMsg := fmt.Sprintf("Error while querying for attendance for eventID %d: %s", eventID, err.Error())
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDBError(w, r, err, fmt.Sprintf("Error while querying for attendance for eventID %d", eventID))
 		return
 	}
 
@@ -452,9 +448,7 @@ func (h *Handler) GetEventAttendance(w http.ResponseWriter, r *http.Request) {
 	for rows.Next() {
         record, err := createEventAttendanceFromRow(rows)
 		if err != nil {
-            errMsg := fmt.Sprintf("Error while parsing attendance query for eventID %d: '%s'", eventID, err.Error())
-            log.Println(errMsg)
-            models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+            respondWithInternalError(w, r, err, fmt.Sprintf("Error while parsing attendance query for eventID %d", eventID))
 			return
 		}
         attendanceList = append(attendanceList, &record)
@@ -487,12 +481,14 @@ func (h *Handler) GetEventAttendance(w http.ResponseWriter, r *http.Request) {
 //	@Description	Create new event record
 //	@Tags			Events
 //	@Param			eventid path int true	"eventID"
-//	@Success		200		{object}		models.APIResponse{data=models.Event}
+//	@Success		201		{object}		models.APIResponse{data=models.Attendance}
 //	@Failure		400		{object}	models.APIResponse
+//	@Failure		409		{object}	models.APIResponse
+//	@Failure		422		{object}	models.APIResponse
 //	@Router			/api/events/{eventid}/attendance [post]
 func (h* Handler) CreateAttendanceRecordForEvent(w http.ResponseWriter, r *http.Request) {
     // TODO: fix swagger docs
-    ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+    ctx, cancel := requestContext(r)
     defer cancel()
 
     // Get event from URL Params
@@ -500,9 +496,7 @@ func (h* Handler) CreateAttendanceRecordForEvent(w http.ResponseWriter, r *http.
     eventIDStr := chi.URLParam(r, "eventID")
     eventID, err := strconv.Atoi(eventIDStr)
     if err != nil {
-        errMsg := "Invalid event ID"
-        log.Printf(errMsg, err)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        respondWithInvalidParam(w, r, "event ID", err)
         return
     }
 
@@ -513,47 +507,50 @@ func (h* Handler) CreateAttendanceRecordForEvent(w http.ResponseWriter, r *http.
     }
     err = json.NewDecoder(r.Body).Decode(&requestBody)
     if err != nil {
-        errMsg := fmt.Sprintf("Error decoding request body", err.Error())
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDecodeError(w, r, err)
         return
     }
-    fmt.Printf("Event %d; Request Body: %v\n", eventID, requestBody)
+    slog.DebugContext(r.Context(), "Request body", "eventID", eventID, "body", logging.Body(requestBody))
 
     // Validate request body params 
-    validate := validator.New() 
     if err := validate.Struct(requestBody); err != nil {
-        errMsg := fmt.Sprintf("Error validating body params. Missing values: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        respondWithValidationError(w, r, err)
         return
 	}
 
     // Insert new attendance record for eventID
-    log.Println("\tInserting new attendance record\n")
+    slog.DebugContext(r.Context(), "Inserting new attendance record")
     query := `
-    INSERT INTO attendance (eventID, brotherID, attendanceStatus)
-    SELECT $1, b.brotherID, $2
-    FROM brothers b
-    WHERE b.rollCall = $3
-    `
-    log.Printf("query: %s\n", query)
-    _, err = h.db.ExecContext(
+    WITH inserted AS (
+        INSERT INTO attendance (eventID, brotherID, attendanceStatus)
+        SELECT $1, b.brotherID, $2
+        FROM brothers b
+        WHERE b.rollCall = $3
+        RETURNING brotherID, eventID, attendanceStatus
+    )
+    ` + fmt.Sprintf(store.AttendanceRecordQuery, "inserted")
+    slog.DebugContext(r.Context(), "Query", "sql", query)
+    row := h.db.QueryRowContext(
         ctx,
         query,
         eventID,
         requestBody.AttendanceStatus,
         requestBody.RollCall,
     )
+    record, err := store.ScanAttendance(row)
+    if err == sql.ErrNoRows {
+        errMsg := fmt.Sprintf("Brother with roll call %d not found", requestBody.RollCall)
+        slog.InfoContext(r.Context(), errMsg)
+        models.RespondWithFail(w, http.StatusNotFound, errMsg)
+        return
+    }
     if err != nil {
-        errMsg := fmt.Sprintf("Error while querying: %s", err.Error())
-        log.Printf(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDBError(w, r, err, "Error while inserting attendance record")
 		return
     }
 
     msg := "Created new attendance record to `attendance` table successfully"
-    log.Println(msg)
-    models.RespondWithSuccess(w, http.StatusCreated, "")
+    slog.InfoContext(r.Context(), msg)
+    location := fmt.Sprintf("/api/events/%d/attendance", record.EventID)
+    models.RespondWithCreated(w, location, record)
 }
-
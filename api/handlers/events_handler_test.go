@@ -122,3 +122,30 @@ func TestUpdateEventByID(t *testing.T) {
         t.Errorf("Failed to update event. \nExpected:\n%+v \n\nActual:\n%+v", event, *response)
     }
 }
+
+func TestUpdateEventWithoutCategory(t *testing.T) {
+	eventID := insertTestEvent(t)
+	router := chi.NewRouter()
+	router.Get("/api/events/{eventID}", handler.GetEventByEventID)
+	router.Patch("/api/events/{eventID}", handler.UpdateEventByID)
+	url := fmt.Sprintf("/api/events/%d", eventID)
+
+	// The event has no category and its new name has a quote
+	eventName := "Founders' Day"
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, newJSONRequest(t, "PATCH", url, map[string]string{"eventName": eventName}))
+	checkResponseCode(t, http.StatusOK, rr.Code)
+	var updated models.Event
+	decodeData(t, rr, &updated)
+	if updated.EventID != eventID || updated.EventName != eventName || updated.CategoryName != "" {
+		t.Errorf("Expected the renamed event without a category. Got %+v", updated)
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	checkResponseCode(t, http.StatusOK, rr.Code)
+}
@@ -1,19 +1,85 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
 	"time"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+	"github.com/pacific-theta-tau/tt-db/blob"
+	"github.com/pacific-theta-tau/tt-db/db"
+	"github.com/pacific-theta-tau/tt-db/metrics"
+	"github.com/pacific-theta-tau/tt-db/store"
 )
 
-// Threshold for waiting database response
+// Threshold for waiting database response when the route doesn't set its own deadline
 const dbTimeout = time.Second * 5
 
 // Handler contains methods to handle all API requests
 type Handler struct {
-	db *sql.DB
+	db *db.DB
+	// Attachment contents. Nil until UseAttachments is called, and attachment routes respond 503
+	blobs       blob.Store
+	attachments blob.AttachmentConfig
+}
+
+// Create a new Handler instance with the app's database connection.
+// Query durations are reported to the metrics package, labelled with the calling handler or helper
+func NewHandler(conn *sql.DB) *Handler {
+	return &Handler{db: db.Instrument(conn, metrics.ObserveQuery)}
+}
+
+// Stores attachment contents in blobs and checks uploads against config
+func (h *Handler) UseAttachments(blobs blob.Store, config blob.AttachmentConfig) {
+	h.blobs = blobs
+	h.attachments = config
+}
+
+// Returns the user with an email and their password hash, for apimiddleware.Authenticate
+func (h *Handler) LookupUser(ctx context.Context, email string) (models.User, string, error) {
+	// Sign in runs before the route's timeout middleware
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+	return store.UserByEmail(ctx, h.db, email)
+}
+
+// Responds with 404 and returns false if the brother doesn't exist
+func (h *Handler) brotherExists(w http.ResponseWriter, r *http.Request, brotherID int) bool {
+	return h.rowExists(w, r, "Brother", brotherID, store.BrotherExists)
+}
+
+// Responds with 404 and returns false if the event doesn't exist
+func (h *Handler) eventExists(w http.ResponseWriter, r *http.Request, eventID int) bool {
+	return h.rowExists(w, r, "Event", eventID, store.EventExists)
+}
+
+// Checks a row with exists, naming it in the 404 and log messages
+func (h *Handler) rowExists(w http.ResponseWriter, r *http.Request, name string, id int, exists func(context.Context, store.Querier, int) (bool, error)) bool {
+	ctx, cancel := requestContext(r)
+	defer cancel()
+
+	found, err := exists(ctx, h.db, id)
+	if err != nil {
+		respondWithDBError(w, r, err, fmt.Sprintf("Error while checking %s", strings.ToLower(name)))
+		return false
+	}
+	if !found {
+		errMsg := fmt.Sprintf("%s ID %d not found", name, id)
+		slog.InfoContext(r.Context(), errMsg)
+		models.RespondWithFail(w, http.StatusNotFound, errMsg)
+	}
+	return found
 }
 
-// Create a new Handler instance with the app's database connection
-func NewHandler(db *sql.DB) *Handler {
-	return &Handler{db: db}
+// Returns a context for database calls that is cancelled when the client disconnects.
+// Routes wrapped in a timeout middleware already carry a deadline; other requests are bounded by dbTimeout
+func requestContext(r *http.Request) (context.Context, context.CancelFunc) {
+	if _, ok := r.Context().Deadline(); ok {
+		return context.WithCancel(r.Context())
+	}
+	return context.WithTimeout(r.Context(), dbTimeout)
 }
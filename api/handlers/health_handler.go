@@ -0,0 +1,102 @@
+// health_handler.go: Handle liveness, readiness and build-info probes
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+	"github.com/pacific-theta-tau/tt-db/db"
+	"github.com/pacific-theta-tau/tt-db/version"
+)
+
+// Probes must answer quickly, even when the database hangs
+const probeTimeout = time.Second * 2
+
+// GET /healthz
+//	@Summary		Liveness probe
+//	@Description	Reports that the process is alive. Does not check the database
+//	@Tags			Health
+//	@Success		200		object		models.APIResponse{data=string}
+//	@Router			/healthz [get]
+func (h *Handler) Healthz(w http.ResponseWriter, r *http.Request) {
+    models.RespondWithSuccess(w, http.StatusOK, "ok")
+}
+
+// GET /readyz
+//	@Summary		Readiness probe
+//	@Description	Reports whether the API can serve traffic: the database answers a ping, all migrations are applied and the connection pool is not exhausted
+//	@Tags			Health
+//	@Success		200		object		models.APIResponse{data=models.Readiness}
+//	@Failure		503		object		models.APIResponse{data=models.Readiness}
+//	@Router			/readyz [get]
+func (h *Handler) Readyz(w http.ResponseWriter, r *http.Request) {
+    ctx, cancel := context.WithTimeout(r.Context(), probeTimeout)
+    defer cancel()
+
+    readiness := models.Readiness{Ready: true}
+    // The error is only logged: the probe is public and errors can reveal hosts or queries
+    addCheck := func(name string, message string, err error) {
+        check := models.HealthCheck{Name: name, OK: err == nil}
+        if err != nil {
+            slog.WarnContext(r.Context(), "Readiness check failed", "check", name, "error", err)
+            check.Message = message
+            readiness.Ready = false
+        }
+        readiness.Checks = append(readiness.Checks, check)
+    }
+
+    addCheck("database", "database unreachable", h.db.PingContext(ctx))
+
+    schema, err := db.GetSchemaStatus(ctx, h.db.DB)
+    message := "schema version unavailable"
+    if err == nil && len(schema.Pending) > 0 {
+        err = fmt.Errorf("%d pending migrations (schema version %d, latest %d)", len(schema.Pending), schema.Version, schema.Latest)
+        message = "migrations pending"
+    }
+    addCheck("migrations", message, err)
+
+    stats := h.db.Stats()
+    err = nil
+    if stats.MaxOpenConnections > 0 && stats.InUse >= stats.MaxOpenConnections {
+        err = fmt.Errorf("all %d connections in use", stats.MaxOpenConnections)
+    }
+    addCheck("pool", "connection pool exhausted", err)
+
+    if !readiness.Ready {
+        models.RespondWithErrorData(w, http.StatusServiceUnavailable, "Not ready", readiness)
+        return
+    }
+    models.RespondWithSuccess(w, http.StatusOK, readiness)
+}
+
+// GET /version
+//	@Summary		Build information
+//	@Description	Get the git commit, build time and Go version of the running API, and the applied database schema version
+//	@Tags			Health
+//	@Success		200		object		models.APIResponse{data=models.VersionInfo}
+//	@Router			/version [get]
+func (h *Handler) Version(w http.ResponseWriter, r *http.Request) {
+    ctx, cancel := context.WithTimeout(r.Context(), probeTimeout)
+    defer cancel()
+
+    commit, buildTime, goVersion := version.Get()
+    info := models.VersionInfo{
+        Commit:     commit,
+        BuildTime:  buildTime,
+        GoVersion:  goVersion,
+    }
+
+    // Build info is still useful when the database is down, so schema errors are only logged
+    schema, err := db.GetSchemaStatus(ctx, h.db.DB)
+    if err != nil {
+        slog.WarnContext(r.Context(), "Error while reading schema version", "error", err)
+    }
+    info.SchemaVersion = schema.Version
+    info.LatestSchema = schema.Latest
+
+    models.RespondWithSuccess(w, http.StatusOK, info)
+}
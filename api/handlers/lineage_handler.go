@@ -0,0 +1,277 @@
+// lineage_handler.go: Handle requests for big/little relationships and family trees
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	apimiddleware "github.com/pacific-theta-tau/tt-db/api/middleware"
+	"github.com/pacific-theta-tau/tt-db/api/models"
+	"github.com/pacific-theta-tau/tt-db/store"
+)
+
+// Responds with 401 or 403 and returns false unless the signed in user is an officer or admin. Only officers change the lineage
+func canWriteLineage(w http.ResponseWriter, r *http.Request) bool {
+    if apimiddleware.CanSeeOfficerData(r.Context()) {
+        return true
+    }
+    respondWithDenied(w, r, "Member tried to change the lineage", "Only officers can change the lineage")
+    return false
+}
+
+// Reads the optional ?depth= limit on generations, 0 when unset. Responds with an error and returns false if it is invalid
+func depthParam(w http.ResponseWriter, r *http.Request) (int, bool) {
+    value := r.URL.Query().Get("depth")
+    if value == "" {
+        return 0, true
+    }
+    depth, err := strconv.Atoi(value)
+    if err == nil && depth < 1 {
+        err = errors.New("depth must be at least 1")
+    }
+    if err != nil {
+        respondWithInvalidParam(w, r, "depth", err)
+        return 0, false
+    }
+    return depth, true
+}
+
+// PUT /api/brothers/{id}/big
+//	@Summary		Set the big of a Brother
+//	@Description	Make another Brother the big of this one, replacing their previous big. A Brother can't be their own big or the big of one of their ancestors. Officers only
+//	@Tags			Lineage
+//	@Accept			json
+//	@Produce		json
+//	@Param			body_params	body		models.BigLink	true	"Big of the Brother. littleID is ignored"
+//	@Param			id			path		int				true	"Brother ID of the little"
+//	@Success		200			{object}	models.APIResponse{data=models.BigLink}
+//	@Failure		400			{object}	models.APIResponse
+//	@Failure		401			{object}	models.APIResponse
+//	@Failure		403			{object}	models.APIResponse
+//	@Failure		404			{object}	models.APIResponse
+//	@Failure		422			{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/brothers/{id}/big [put]
+func (h *Handler) SetBrotherBig(w http.ResponseWriter, r *http.Request) {
+    if !canWriteLineage(w, r) {
+        return
+    }
+    littleID, ok := idParam(w, r, "id", "brother ID")
+    if !ok {
+        return
+    }
+
+    var link models.BigLink
+    if err := json.NewDecoder(r.Body).Decode(&link); err != nil {
+        respondWithDecodeError(w, r, err)
+        return
+    }
+    if err := validate.Struct(link); err != nil {
+        respondWithValidationError(w, r, err)
+        return
+    }
+    link.LittleID = littleID
+    if link.BigBrotherID == littleID {
+        respondWithFieldError(w, r, "bigBrotherID", "self", "A Brother can't be their own big")
+        return
+    }
+    if !h.brotherExists(w, r, littleID) {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    tx, err := h.db.BeginTx(ctx, nil)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while starting transaction")
+        return
+    }
+    defer tx.Rollback()
+
+    if err := store.LockLineage(ctx, tx); err != nil {
+        respondWithDBError(w, r, err, "Error while locking lineage")
+        return
+    }
+    cycle, err := store.IsAncestorOrSelf(ctx, tx, littleID, link.BigBrotherID)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while checking lineage for cycles")
+        return
+    }
+    if cycle {
+        msg := fmt.Sprintf("Brother ID %d is a descendant of Brother ID %d and can't be their big", link.BigBrotherID, littleID)
+        respondWithFieldError(w, r, "bigBrotherID", "cycle", msg)
+        return
+    }
+    if err := store.SetBig(ctx, tx, littleID, link.BigBrotherID); err != nil {
+        respondWithDBError(w, r, err, "Error while setting big")
+        return
+    }
+    if err := tx.Commit(); err != nil {
+        respondWithDBError(w, r, err, "Error while committing big")
+        return
+    }
+
+    models.RespondWithSuccess(w, http.StatusOK, link)
+}
+
+// DELETE /api/brothers/{id}/big
+//	@Summary		Remove the big of a Brother
+//	@Description	Remove the link to the Brother's big. Their littles stay linked to them. Officers only
+//	@Tags			Lineage
+//	@Param			id		path		int		true	"Brother ID of the little"
+//	@Success		204
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		401		{object}	models.APIResponse
+//	@Failure		403		{object}	models.APIResponse
+//	@Failure		404		{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/brothers/{id}/big [delete]
+func (h *Handler) RemoveBrotherBig(w http.ResponseWriter, r *http.Request) {
+    if !canWriteLineage(w, r) {
+        return
+    }
+    littleID, ok := idParam(w, r, "id", "brother ID")
+    if !ok {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    removed, err := store.RemoveBig(ctx, h.db, littleID)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while removing big")
+        return
+    }
+    if !removed {
+        errMsg := fmt.Sprintf("Brother ID %d has no big", littleID)
+        slog.InfoContext(r.Context(), errMsg)
+        models.RespondWithFail(w, http.StatusNotFound, errMsg)
+        return
+    }
+
+    w.WriteHeader(http.StatusNoContent)
+}
+
+// Responds with the ancestors or descendants of the Brother in the URL
+func (h *Handler) listRelatives(w http.ResponseWriter, r *http.Request, query func(ctx context.Context, q store.Querier, brotherID int, maxDepth int) ([]models.LineageBrother, error)) {
+    brotherID, ok := idParam(w, r, "id", "brother ID")
+    if !ok {
+        return
+    }
+    depth, ok := depthParam(w, r)
+    if !ok || !h.brotherExists(w, r, brotherID) {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    relatives, err := query(ctx, h.db, brotherID, depth)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while querying lineage")
+        return
+    }
+
+    models.RespondWithSuccess(w, http.StatusOK, relatives)
+}
+
+// GET /api/brothers/{id}/ancestors
+//	@Summary		Get the ancestors of a Brother
+//	@Description	Get the Brother's big, their big and so on, closest first
+//	@Tags			Lineage
+//	@Produce		json
+//	@Param			id		path		int		true	"Brother ID"
+//	@Param			depth	query		int		false	"Number of generations to return (default: all)"
+//	@Success		200		{object}	models.APIResponse{data=[]models.LineageBrother}
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		404		{object}	models.APIResponse
+//	@Router			/api/brothers/{id}/ancestors [get]
+func (h *Handler) GetBrotherAncestors(w http.ResponseWriter, r *http.Request) {
+    h.listRelatives(w, r, store.Ancestors)
+}
+
+// GET /api/brothers/{id}/descendants
+//	@Summary		Get the descendants of a Brother
+//	@Description	Get the Brother's littles, their littles and so on, closest first
+//	@Tags			Lineage
+//	@Produce		json
+//	@Param			id		path		int		true	"Brother ID"
+//	@Param			depth	query		int		false	"Number of generations to return (default: all)"
+//	@Success		200		{object}	models.APIResponse{data=[]models.LineageBrother}
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		404		{object}	models.APIResponse
+//	@Router			/api/brothers/{id}/descendants [get]
+func (h *Handler) GetBrotherDescendants(w http.ResponseWriter, r *http.Request) {
+    h.listRelatives(w, r, store.Descendants)
+}
+
+// GET /api/lineage
+//	@Summary		Export the family trees
+//	@Description	Get every family tree, starting from founders (Brothers without a big). Brothers with neither a big nor littles are left out.
+//	@Description	With format=dot the trees are returned as a Graphviz digraph instead of JSON, e.g. for `dot -Tsvg lineage.dot -o lineage.svg`
+//	@Tags			Lineage
+//	@Produce		json
+//	@Produce		text/vnd.graphviz
+//	@Param			format	query		string	false	"Response format (default: json)"	Enums(json, dot)
+//	@Success		200		{object}	models.APIResponse{data=[]models.FamilyTreeNode}
+//	@Failure		400		{object}	models.APIResponse
+//	@Router			/api/lineage [get]
+func (h *Handler) GetLineage(w http.ResponseWriter, r *http.Request) {
+    format := r.URL.Query().Get("format")
+    if format != "" && format != "json" && format != "dot" {
+        respondWithInvalidParam(w, r, "format", fmt.Errorf("unknown format %q", format))
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    brothers, err := store.Lineage(ctx, h.db)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while querying lineage")
+        return
+    }
+    trees := store.FamilyTree(brothers)
+
+    if format != "dot" {
+        models.RespondWithSuccess(w, http.StatusOK, trees)
+        return
+    }
+    var graph bytes.Buffer
+    if err := store.WriteLineageDOT(&graph, trees); err != nil {
+        respondWithInternalError(w, r, err, "Error while writing lineage graph")
+        return
+    }
+    w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+    w.Header().Set("Content-Disposition", `attachment; filename="lineage.dot"`)
+    w.WriteHeader(http.StatusOK)
+    graph.WriteTo(w)
+}
+
+// GET /api/lineage/families
+//	@Summary		Get family stats
+//	@Description	Get the size, number of Active Brothers and number of generations of each family, largest first. A family is a founder and all their descendants
+//	@Tags			Lineage
+//	@Produce		json
+//	@Success		200		{object}	models.APIResponse{data=[]models.Family}
+//	@Router			/api/lineage/families [get]
+func (h *Handler) GetFamilies(w http.ResponseWriter, r *http.Request) {
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    brothers, err := store.Lineage(ctx, h.db)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while querying lineage")
+        return
+    }
+
+    models.RespondWithSuccess(w, http.StatusOK, store.FamilyStats(store.FamilyTree(brothers)))
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/pacific-theta-tau/tt-db/api/models"
+)
+
+// Sets the big of littleID as an officer and returns the response code
+func setTestBig(t *testing.T, littleID int, bigID int) int {
+	router := chi.NewRouter()
+	router.Put("/api/brothers/{id}/big", handler.SetBrotherBig)
+
+	req := newJSONRequest(t, "PUT", fmt.Sprintf("/api/brothers/%d/big", littleID), models.BigLink{BigBrotherID: bigID})
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, withRole(req, models.RoleOfficer))
+	return rr.Code
+}
+
+func TestSetBrotherBigRejectsCycles(t *testing.T) {
+	grandBig := insertTestBrother(t, "GrandBig")
+	big := insertTestBrother(t, "Big")
+	little := insertTestBrother(t, "Little")
+
+	checkResponseCode(t, http.StatusOK, setTestBig(t, big.BrotherID, grandBig.BrotherID))
+	checkResponseCode(t, http.StatusOK, setTestBig(t, little.BrotherID, big.BrotherID))
+	// The grand big's big can't be one of their descendants
+	checkResponseCode(t, http.StatusBadRequest, setTestBig(t, grandBig.BrotherID, little.BrotherID))
+	checkResponseCode(t, http.StatusBadRequest, setTestBig(t, big.BrotherID, big.BrotherID))
+}
+
+func TestSetBrotherBigRejectsConcurrentCycles(t *testing.T) {
+	a := insertTestBrother(t, "Concurrent")
+	b := insertTestBrother(t, "Concurrent")
+
+	// Each link is fine alone, but not both: only one of the requests may win
+	codes := make([]int, 2)
+	var wg sync.WaitGroup
+	for i, link := range [][2]int{{a.BrotherID, b.BrotherID}, {b.BrotherID, a.BrotherID}} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes[i] = setTestBig(t, link[0], link[1])
+		}()
+	}
+	wg.Wait()
+
+	if !(codes[0] == http.StatusOK && codes[1] == http.StatusBadRequest) && !(codes[0] == http.StatusBadRequest && codes[1] == http.StatusOK) {
+		t.Errorf("Expected one link to be set and the other rejected. Got %v", codes)
+	}
+	var links int
+	err := handler.db.QueryRowContext(context.Background(), `SELECT count(*) FROM lineage WHERE littleID IN ($1, $2)`,
+		a.BrotherID, b.BrotherID).Scan(&links)
+	if err != nil || links != 1 {
+		t.Errorf("Expected 1 link between the brothers. Got %d (%v)", links, err)
+	}
+}
+
+func TestSetBrotherBigNeedsOfficer(t *testing.T) {
+	big := insertTestBrother(t, "Big")
+	little := insertTestBrother(t, "Little")
+	router := chi.NewRouter()
+	router.Put("/api/brothers/{id}/big", handler.SetBrotherBig)
+
+	for _, tt := range []struct {
+		role   string
+		status int
+	}{{"", http.StatusUnauthorized}, {models.RoleMember, http.StatusForbidden}} {
+		req := newJSONRequest(t, "PUT", fmt.Sprintf("/api/brothers/%d/big", little.BrotherID), models.BigLink{BigBrotherID: big.BrotherID})
+		if tt.role != "" {
+			req = withRole(req, tt.role)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		checkResponseCode(t, tt.status, rr.Code)
+	}
+}
@@ -0,0 +1,312 @@
+// notes_handler.go: Handle requests for notes officers keep on brother records
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	apimiddleware "github.com/pacific-theta-tau/tt-db/api/middleware"
+	"github.com/pacific-theta-tau/tt-db/api/models"
+	"github.com/pacific-theta-tau/tt-db/store"
+)
+
+// Parses the brother ID and, when present, the note ID from the URL. Responds with an error and returns false if one is invalid
+func noteParams(w http.ResponseWriter, r *http.Request) (brotherID int, noteID int, ok bool) {
+    brotherID, err := strconv.Atoi(chi.URLParam(r, "id"))
+    if err != nil {
+        respondWithInvalidParam(w, r, "brother ID", err)
+        return 0, 0, false
+    }
+    if param := chi.URLParam(r, "noteID"); param != "" {
+        noteID, err = strconv.Atoi(param)
+        if err != nil {
+            respondWithInvalidParam(w, r, "note ID", err)
+            return 0, 0, false
+        }
+    }
+    return brotherID, noteID, true
+}
+
+// Responds with 401 or 403 and returns false unless the signed in user is an officer or admin. Only officers write notes
+func canWriteNotes(w http.ResponseWriter, r *http.Request) bool {
+    if apimiddleware.CanSeeOfficerData(r.Context()) {
+        return true
+    }
+    respondWithDenied(w, r, "Member tried to change a note", "Only officers can change notes")
+    return false
+}
+
+// Responds with 404 for a note that doesn't exist on the brother
+func respondWithNoteNotFound(w http.ResponseWriter, r *http.Request, brotherID int, noteID int) {
+    errMsg := fmt.Sprintf("Note %d not found for brother ID %d", noteID, brotherID)
+    slog.InfoContext(r.Context(), errMsg)
+    models.RespondWithFail(w, http.StatusNotFound, errMsg)
+}
+
+// GET /api/brothers/{id}/notes
+//	@Summary		Get notes on a Brother
+//	@Description	Get the notes on a Brother, newest first. Officers-only notes are left out for members and callers who are not signed in
+//	@Tags			Notes
+//	@Produce		json
+//	@Param			id		path		int		true	"Brother ID"
+//	@Success		200		{object}	models.APIResponse{data=[]models.Note}
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		404		{object}	models.APIResponse
+//	@Router			/api/brothers/{id}/notes [get]
+func (h *Handler) GetBrotherNotes(w http.ResponseWriter, r *http.Request) {
+    brotherID, _, ok := noteParams(w, r)
+    if !ok || !h.brotherExists(w, r, brotherID) {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    notes, err := store.BrotherNotes(ctx, h.db, brotherID, apimiddleware.CanSeeOfficerData(r.Context()))
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while querying brother notes")
+        return
+    }
+
+    models.RespondWithSuccess(w, http.StatusOK, notes)
+}
+
+// POST /api/brothers/{id}/notes
+//	@Summary		Add a note to a Brother
+//	@Description	Add a note to a Brother. Notes are visible to officers only unless visibility is "all". The author is the signed in user. Officers only
+//	@Tags			Notes
+//	@Accept			json
+//	@Produce		json
+//	@Param			body_params body		handlers.CreateBrotherNote.RequestBody	true	"Note to add"
+//	@Param			id		path		int										true	"Brother ID"
+//	@Success		201		{object}	models.APIResponse{data=models.Note}
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		401		{object}	models.APIResponse
+//	@Failure		403		{object}	models.APIResponse
+//	@Failure		422		{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/brothers/{id}/notes [post]
+func (h *Handler) CreateBrotherNote(w http.ResponseWriter, r *http.Request) {
+    brotherID, _, ok := noteParams(w, r)
+    if !ok {
+        return
+    }
+
+    // Expected request body data
+    type RequestBody struct {
+        Body       string `json:"body" validate:"required,max=10000"`
+        Visibility string `json:"visibility" validate:"omitempty,oneof=officers all"`
+    }
+    var requestBody RequestBody
+    if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+        respondWithDecodeError(w, r, err)
+        return
+    }
+    if err := validate.Struct(requestBody); err != nil {
+        respondWithValidationError(w, r, err)
+        return
+    }
+    if !canWriteNotes(w, r) {
+        return
+    }
+    // canWriteNotes only lets signed in officers through, so there is always a user
+    author, _ := apimiddleware.UserFromContext(r.Context())
+    if requestBody.Visibility == "" {
+        requestBody.Visibility = models.NoteVisibilityOfficers
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    note, err := store.InsertNote(ctx, h.db, brotherID, requestBody.Body, requestBody.Visibility, author.Email)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while inserting brother note")
+        return
+    }
+
+    location := fmt.Sprintf("/api/brothers/%d/notes/%d", brotherID, note.NoteID)
+    models.RespondWithCreated(w, location, note)
+}
+
+// PATCH /api/brothers/{id}/notes/{noteID}
+//	@Summary		Edit a note on a Brother
+//	@Description	Change the body and/or visibility of a note. The previous version is kept in the note's history. The editor is the signed in user
+//	@Tags			Notes
+//	@Accept			json
+//	@Produce		json
+//	@Param			body_params body		handlers.UpdateBrotherNote.RequestBody	true	"Values to change"
+//	@Param			id		path		int										true	"Brother ID"
+//	@Param			noteID	path		int										true	"Note ID"
+//	@Success		200		{object}	models.APIResponse{data=models.Note}
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		401		{object}	models.APIResponse
+//	@Failure		403		{object}	models.APIResponse
+//	@Failure		404		{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/brothers/{id}/notes/{noteID} [patch]
+func (h *Handler) UpdateBrotherNote(w http.ResponseWriter, r *http.Request) {
+    brotherID, noteID, ok := noteParams(w, r)
+    if !ok {
+        return
+    }
+
+    // Expected request body data. Fields left out keep their value
+    type RequestBody struct {
+        Body       *string `json:"body" validate:"omitempty,min=1,max=10000"`
+        Visibility *string `json:"visibility" validate:"omitempty,oneof=officers all"`
+    }
+    var requestBody RequestBody
+    if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+        respondWithDecodeError(w, r, err)
+        return
+    }
+    if err := validate.Struct(requestBody); err != nil {
+        respondWithValidationError(w, r, err)
+        return
+    }
+    if requestBody.Body == nil && requestBody.Visibility == nil {
+        respondWithFieldError(w, r, "body", "required", "body or visibility is required")
+        return
+    }
+    if !canWriteNotes(w, r) {
+        return
+    }
+    editor, _ := apimiddleware.UserFromContext(r.Context())
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    tx, err := h.db.BeginTx(ctx, nil)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while starting transaction")
+        return
+    }
+    defer tx.Rollback()
+
+    note, err := store.GetNote(ctx, tx, brotherID, noteID)
+    if errors.Is(err, sql.ErrNoRows) {
+        respondWithNoteNotFound(w, r, brotherID, noteID)
+        return
+    }
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while querying brother note")
+        return
+    }
+    if requestBody.Body != nil {
+        note.Body = *requestBody.Body
+    }
+    if requestBody.Visibility != nil {
+        note.Visibility = *requestBody.Visibility
+    }
+
+    note, err = store.UpdateNote(ctx, tx, brotherID, noteID, note.Body, note.Visibility, editor.Email)
+    if errors.Is(err, sql.ErrNoRows) {
+        respondWithNoteNotFound(w, r, brotherID, noteID)
+        return
+    }
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while updating brother note")
+        return
+    }
+    if err := tx.Commit(); err != nil {
+        respondWithDBError(w, r, err, "Error while committing brother note")
+        return
+    }
+
+    models.RespondWithSuccess(w, http.StatusOK, note)
+}
+
+// DELETE /api/brothers/{id}/notes/{noteID}
+//	@Summary		Delete a note on a Brother
+//	@Description	Delete a note and its history
+//	@Tags			Notes
+//	@Param			id		path		int		true	"Brother ID"
+//	@Param			noteID	path		int		true	"Note ID"
+//	@Success		204
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		401		{object}	models.APIResponse
+//	@Failure		403		{object}	models.APIResponse
+//	@Failure		404		{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/brothers/{id}/notes/{noteID} [delete]
+func (h *Handler) DeleteBrotherNote(w http.ResponseWriter, r *http.Request) {
+    brotherID, noteID, ok := noteParams(w, r)
+    if !ok {
+        return
+    }
+    if !canWriteNotes(w, r) {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    err := store.DeleteNote(ctx, h.db, brotherID, noteID)
+    if errors.Is(err, sql.ErrNoRows) {
+        respondWithNoteNotFound(w, r, brotherID, noteID)
+        return
+    }
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while deleting brother note")
+        return
+    }
+
+    slog.InfoContext(r.Context(), "Deleted brother note", "brother_id", brotherID, "note_id", noteID)
+    w.WriteHeader(http.StatusNoContent)
+}
+
+// GET /api/brothers/{id}/notes/{noteID}/history
+//	@Summary		Get the edit history of a note
+//	@Description	Get every version of a note with who wrote it and when, oldest first. The last one is the current version. Members and callers who are not signed in only see versions visible to all
+//	@Tags			Notes
+//	@Produce		json
+//	@Param			id		path		int		true	"Brother ID"
+//	@Param			noteID	path		int		true	"Note ID"
+//	@Success		200		{object}	models.APIResponse{data=[]models.NoteRevision}
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		404		{object}	models.APIResponse
+//	@Router			/api/brothers/{id}/notes/{noteID}/history [get]
+func (h *Handler) GetBrotherNoteHistory(w http.ResponseWriter, r *http.Request) {
+    brotherID, noteID, ok := noteParams(w, r)
+    if !ok {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    note, err := store.GetNote(ctx, h.db, brotherID, noteID)
+    // Members can't tell officers-only notes from missing ones
+    if errors.Is(err, sql.ErrNoRows) || (err == nil && note.Visibility != models.NoteVisibilityAll && !apimiddleware.CanSeeOfficerData(r.Context())) {
+        respondWithNoteNotFound(w, r, brotherID, noteID)
+        return
+    }
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while querying brother note")
+        return
+    }
+
+    history, err := store.NoteHistory(ctx, h.db, note)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while querying note history")
+        return
+    }
+    // Versions written before the note was shared with everyone stay hidden from members
+    if !apimiddleware.CanSeeOfficerData(r.Context()) {
+        visible := []models.NoteRevision{}
+        for _, revision := range history {
+            if revision.Visibility == models.NoteVisibilityAll {
+                visible = append(visible, revision)
+            }
+        }
+        history = visible
+    }
+
+    models.RespondWithSuccess(w, http.StatusOK, history)
+}
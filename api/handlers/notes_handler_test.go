@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/pacific-theta-tau/tt-db/api/models"
+	"github.com/pacific-theta-tau/tt-db/store"
+)
+
+func TestGetBrotherNotesHidesOfficersOnly(t *testing.T) {
+	ctx := context.Background()
+	brother := insertTestBrother(t, "Noted")
+	officersOnly, err := store.InsertNote(ctx, handler.db, brother.BrotherID, "Officers only", models.NoteVisibilityOfficers, "officer@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.InsertNote(ctx, handler.db, brother.BrotherID, "For everyone", models.NoteVisibilityAll, "officer@example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	router := chi.NewRouter()
+	router.Get("/api/brothers/{id}/notes", handler.GetBrotherNotes)
+	router.Get("/api/brothers/{id}/notes/{noteID}/history", handler.GetBrotherNoteHistory)
+	notesURL := fmt.Sprintf("/api/brothers/%d/notes", brother.BrotherID)
+	historyURL := fmt.Sprintf("%s/%d/history", notesURL, officersOnly.NoteID)
+
+	tests := []struct {
+		role          string
+		notes         int
+		historyStatus int
+	}{
+		{"", 1, http.StatusNotFound},
+		{models.RoleMember, 1, http.StatusNotFound},
+		{models.RoleOfficer, 2, http.StatusOK},
+		{models.RoleAdmin, 2, http.StatusOK},
+	}
+	for _, tt := range tests {
+		send := func(url string) *httptest.ResponseRecorder {
+			req, err := http.NewRequest("GET", url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.role != "" {
+				req = withRole(req, tt.role)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+			return rr
+		}
+
+		rr := send(notesURL)
+		checkResponseCode(t, http.StatusOK, rr.Code)
+		var notes []models.Note
+		decodeData(t, rr, &notes)
+		if len(notes) != tt.notes {
+			t.Errorf("Role %q: expected %d notes. Got %d", tt.role, tt.notes, len(notes))
+		}
+		for _, note := range notes {
+			if note.Visibility != models.NoteVisibilityAll && tt.notes == 1 {
+				t.Errorf("Role %q: expected only notes visible to all. Got %+v", tt.role, note)
+			}
+		}
+		// Officers-only notes look missing to everyone else
+		checkResponseCode(t, tt.historyStatus, send(historyURL).Code)
+	}
+}
+
+func TestCreateBrotherNoteTakesAuthorFromUser(t *testing.T) {
+	brother := insertTestBrother(t, "Noted")
+	router := chi.NewRouter()
+	router.Post("/api/brothers/{id}/notes", handler.CreateBrotherNote)
+	url := fmt.Sprintf("/api/brothers/%d/notes", brother.BrotherID)
+	// An author in the body is ignored
+	body := map[string]string{"body": "Paid dues", "author": "someone@example.com"}
+
+	for _, tt := range []struct {
+		role   string
+		status int
+	}{{"", http.StatusUnauthorized}, {models.RoleMember, http.StatusForbidden}, {models.RoleOfficer, http.StatusCreated}} {
+		req := newJSONRequest(t, "POST", url, body)
+		if tt.role != "" {
+			req = withRole(req, tt.role)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		checkResponseCode(t, tt.status, rr.Code)
+		if rr.Code != http.StatusCreated {
+			continue
+		}
+
+		var note models.Note
+		decodeData(t, rr, &note)
+		if note.Author != "officer@example.com" || note.Visibility != models.NoteVisibilityOfficers {
+			t.Errorf("Expected an officers-only note by the signed in officer. Got %+v", note)
+		}
+	}
+}
@@ -0,0 +1,610 @@
+// positions_handler.go: Handle requests for officer positions, committees and the terms brothers serve in them
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi"
+	apimiddleware "github.com/pacific-theta-tau/tt-db/api/middleware"
+	"github.com/pacific-theta-tau/tt-db/api/models"
+	"github.com/pacific-theta-tau/tt-db/store"
+)
+
+// Responds with 401 or 403 and returns false unless the signed in user is an admin. Positions can grant roles, so only admins change them
+func canManagePositions(w http.ResponseWriter, r *http.Request) bool {
+    if apimiddleware.CanAdminister(r.Context()) {
+        return true
+    }
+    respondWithDenied(w, r, "Non-admin tried to change a position", "Only admins can change positions")
+    return false
+}
+
+// Reads the optional ?kind= filter. Responds with an error and returns false if it is invalid
+func positionKindParam(w http.ResponseWriter, r *http.Request) (string, bool) {
+    kind := r.URL.Query().Get("kind")
+    if kind != "" && kind != models.PositionOffice && kind != models.PositionCommittee {
+        respondWithInvalidParam(w, r, "kind", fmt.Errorf("unknown position kind %q", kind))
+        return "", false
+    }
+    return kind, true
+}
+
+// Responds with 404 for a position that doesn't exist
+func respondWithPositionNotFound(w http.ResponseWriter, r *http.Request, positionID int) {
+    errMsg := fmt.Sprintf("Position %d not found", positionID)
+    slog.InfoContext(r.Context(), errMsg)
+    models.RespondWithFail(w, http.StatusNotFound, errMsg)
+}
+
+// Responds with 404 for a term that doesn't exist on the position
+func respondWithTermNotFound(w http.ResponseWriter, r *http.Request, positionID int, termID int) {
+    errMsg := fmt.Sprintf("Term %d not found for position %d", termID, positionID)
+    slog.InfoContext(r.Context(), errMsg)
+    models.RespondWithFail(w, http.StatusNotFound, errMsg)
+}
+
+// Checks the semesters of a term. Responds with an error and returns false if they aren't a valid range
+func validTermSemesters(w http.ResponseWriter, r *http.Request, term models.PositionTerm) bool {
+    if _, _, err := store.SemesterDates(term.StartSemester); err != nil {
+        respondWithFieldError(w, r, "startSemester", "semester", err.Error())
+        return false
+    }
+    if term.EndSemester == "" {
+        return true
+    }
+    if _, _, err := store.SemesterDates(term.EndSemester); err != nil {
+        respondWithFieldError(w, r, "endSemester", "semester", err.Error())
+        return false
+    }
+    if _, _, err := store.TermDates(term.StartSemester, term.EndSemester); err != nil {
+        respondWithFieldError(w, r, "endSemester", "range", err.Error())
+        return false
+    }
+    return true
+}
+
+// Responds with 409 and returns false if the brother already serves the position during the term
+func (h *Handler) termIsFree(w http.ResponseWriter, r *http.Request, q store.Querier, term models.PositionTerm) bool {
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    overlaps, err := store.TermOverlaps(ctx, q, term)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while checking overlapping terms")
+        return false
+    }
+    if overlaps {
+        errMsg := fmt.Sprintf("Brother ID %d already holds position %d during some of these semesters", term.BrotherID, term.PositionID)
+        slog.InfoContext(r.Context(), errMsg)
+        models.RespondWithFail(w, http.StatusConflict, errMsg)
+        return false
+    }
+    return true
+}
+
+// GET /api/positions
+//	@Summary		Get positions
+//	@Description	Get the chapter's offices and committees, offices first
+//	@Tags			Positions
+//	@Produce		json
+//	@Param			kind	query		string	false	"Only offices or only committees"	Enums(office, committee)
+//	@Success		200		{object}	models.APIResponse{data=[]models.Position}
+//	@Failure		400		{object}	models.APIResponse
+//	@Router			/api/positions [get]
+func (h *Handler) GetPositions(w http.ResponseWriter, r *http.Request) {
+    kind, ok := positionKindParam(w, r)
+    if !ok {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    positions, err := store.Positions(ctx, h.db, kind)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while querying positions")
+        return
+    }
+
+    models.RespondWithSuccess(w, http.StatusOK, positions)
+}
+
+// POST /api/positions
+//	@Summary		Create a position
+//	@Description	Create an office or committee. Admins only. Holders get the position's role, if any, from `ttdb sync-roles`
+//	@Tags			Positions
+//	@Accept			json
+//	@Produce		json
+//	@Param			body_params body		models.Position	true	"Position to create"
+//	@Success		201		{object}	models.APIResponse{data=models.Position}
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		401		{object}	models.APIResponse
+//	@Failure		403		{object}	models.APIResponse
+//	@Failure		409		{object}	models.APIResponse
+//	@Failure		422		{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/positions [post]
+func (h *Handler) CreatePosition(w http.ResponseWriter, r *http.Request) {
+    if !canManagePositions(w, r) {
+        return
+    }
+
+    var position models.Position
+    if err := json.NewDecoder(r.Body).Decode(&position); err != nil {
+        respondWithDecodeError(w, r, err)
+        return
+    }
+    position.Name = strings.TrimSpace(position.Name)
+    if err := validate.Struct(position); err != nil {
+        respondWithValidationError(w, r, err)
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    created, err := store.InsertPosition(ctx, h.db, position)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while inserting position")
+        return
+    }
+
+    location := fmt.Sprintf("/api/positions/%d", created.PositionID)
+    models.RespondWithCreated(w, location, created)
+}
+
+// PATCH /api/positions/{positionID}
+//	@Summary		Update a position
+//	@Description	Change the name, kind or role of a position. Admins only
+//	@Tags			Positions
+//	@Accept			json
+//	@Produce		json
+//	@Param			body_params	body		handlers.UpdatePosition.RequestBody	true	"Values to change"
+//	@Param			positionID	path		int									true	"Position ID"
+//	@Success		200			{object}	models.APIResponse{data=models.Position}
+//	@Failure		400			{object}	models.APIResponse
+//	@Failure		401			{object}	models.APIResponse
+//	@Failure		403			{object}	models.APIResponse
+//	@Failure		404			{object}	models.APIResponse
+//	@Failure		409			{object}	models.APIResponse
+//	@Failure		422			{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/positions/{positionID} [patch]
+func (h *Handler) UpdatePosition(w http.ResponseWriter, r *http.Request) {
+    if !canManagePositions(w, r) {
+        return
+    }
+    positionID, ok := idParam(w, r, "positionID", "position ID")
+    if !ok {
+        return
+    }
+
+    // Expected request body data. Omitted values are left unchanged; an empty role grants none
+    type RequestBody struct {
+        Name *string `json:"name" validate:"omitempty,max=100"`
+        Kind *string `json:"kind" validate:"omitempty,oneof=office committee"`
+        Role *string `json:"role" validate:"omitempty,oneof=admin officer"`
+    }
+    var requestBody RequestBody
+    if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+        respondWithDecodeError(w, r, err)
+        return
+    }
+    if err := validate.Struct(requestBody); err != nil {
+        respondWithValidationError(w, r, err)
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    position, err := store.GetPosition(ctx, h.db, positionID)
+    if errors.Is(err, sql.ErrNoRows) {
+        respondWithPositionNotFound(w, r, positionID)
+        return
+    }
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while querying position")
+        return
+    }
+    if requestBody.Name != nil {
+        position.Name = strings.TrimSpace(*requestBody.Name)
+    }
+    if requestBody.Kind != nil {
+        position.Kind = *requestBody.Kind
+    }
+    if requestBody.Role != nil {
+        position.Role = *requestBody.Role
+    }
+    if err := validate.Struct(position); err != nil {
+        respondWithValidationError(w, r, err)
+        return
+    }
+
+    updated, err := store.UpdatePosition(ctx, h.db, position)
+    if errors.Is(err, sql.ErrNoRows) {
+        respondWithPositionNotFound(w, r, positionID)
+        return
+    }
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while updating position")
+        return
+    }
+
+    models.RespondWithSuccess(w, http.StatusOK, updated)
+}
+
+// DELETE /api/positions/{positionID}
+//	@Summary		Delete a position
+//	@Description	Delete a position and every term served in it. Admins only. End the current term instead to keep the history
+//	@Tags			Positions
+//	@Produce		json
+//	@Param			positionID	path		int		true	"Position ID"
+//	@Success		200			{object}	models.APIResponse{data=models.Position}
+//	@Failure		400			{object}	models.APIResponse
+//	@Failure		401			{object}	models.APIResponse
+//	@Failure		403			{object}	models.APIResponse
+//	@Failure		404			{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/positions/{positionID} [delete]
+func (h *Handler) DeletePosition(w http.ResponseWriter, r *http.Request) {
+    if !canManagePositions(w, r) {
+        return
+    }
+    positionID, ok := idParam(w, r, "positionID", "position ID")
+    if !ok {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    deleted, err := store.DeletePosition(ctx, h.db, positionID)
+    if errors.Is(err, sql.ErrNoRows) {
+        respondWithPositionNotFound(w, r, positionID)
+        return
+    }
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while deleting position")
+        return
+    }
+    slog.InfoContext(r.Context(), "Deleted position", "position_id", deleted.PositionID, "name", deleted.Name)
+
+    models.RespondWithSuccess(w, http.StatusOK, deleted)
+}
+
+// GET /api/positions/{positionID}/terms
+//	@Summary		Get the holders of a position
+//	@Description	Get every term served in a position, latest first
+//	@Tags			Positions
+//	@Produce		json
+//	@Param			positionID	path		int		true	"Position ID"
+//	@Success		200			{object}	models.APIResponse{data=[]models.PositionTerm}
+//	@Failure		400			{object}	models.APIResponse
+//	@Failure		404			{object}	models.APIResponse
+//	@Router			/api/positions/{positionID}/terms [get]
+func (h *Handler) GetPositionTerms(w http.ResponseWriter, r *http.Request) {
+    positionID, ok := idParam(w, r, "positionID", "position ID")
+    if !ok {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    if _, err := store.GetPosition(ctx, h.db, positionID); errors.Is(err, sql.ErrNoRows) {
+        respondWithPositionNotFound(w, r, positionID)
+        return
+    } else if err != nil {
+        respondWithDBError(w, r, err, "Error while querying position")
+        return
+    }
+
+    terms, err := store.PositionTerms(ctx, h.db, positionID)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while querying position terms")
+        return
+    }
+
+    models.RespondWithSuccess(w, http.StatusOK, terms)
+}
+
+// POST /api/positions/{positionID}/terms
+//	@Summary		Assign a Brother to a position
+//	@Description	Add a term in which a Brother holds the position from startSemester to endSemester, both included. Leave endSemester empty for a term that hasn't ended. Admins only
+//	@Tags			Positions
+//	@Accept			json
+//	@Produce		json
+//	@Param			body_params	body		handlers.CreatePositionTerm.RequestBody	true	"Term to add"
+//	@Param			positionID	path		int										true	"Position ID"
+//	@Success		201			{object}	models.APIResponse{data=models.PositionTerm}
+//	@Failure		400			{object}	models.APIResponse
+//	@Failure		401			{object}	models.APIResponse
+//	@Failure		403			{object}	models.APIResponse
+//	@Failure		404			{object}	models.APIResponse
+//	@Failure		409			{object}	models.APIResponse
+//	@Failure		422			{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/positions/{positionID}/terms [post]
+func (h *Handler) CreatePositionTerm(w http.ResponseWriter, r *http.Request) {
+    if !canManagePositions(w, r) {
+        return
+    }
+    positionID, ok := idParam(w, r, "positionID", "position ID")
+    if !ok {
+        return
+    }
+
+    // Expected request body data
+    type RequestBody struct {
+        BrotherID     int    `json:"brotherID" validate:"required"`
+        Title         string `json:"title" validate:"max=50"`
+        StartSemester string `json:"startSemester" validate:"required"`
+        EndSemester   string `json:"endSemester"`
+    }
+    var requestBody RequestBody
+    if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+        respondWithDecodeError(w, r, err)
+        return
+    }
+    if err := validate.Struct(requestBody); err != nil {
+        respondWithValidationError(w, r, err)
+        return
+    }
+    term := models.PositionTerm{
+        PositionID:    positionID,
+        BrotherID:     requestBody.BrotherID,
+        Title:         strings.TrimSpace(requestBody.Title),
+        StartSemester: strings.TrimSpace(requestBody.StartSemester),
+        EndSemester:   strings.TrimSpace(requestBody.EndSemester),
+    }
+    if !validTermSemesters(w, r, term) {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    tx, err := h.db.BeginTx(ctx, nil)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while starting transaction")
+        return
+    }
+    defer tx.Rollback()
+
+    // Lock the position so concurrent assignments can't both pass the overlap check
+    var locked int
+    err = tx.QueryRowContext(ctx, `SELECT positionID FROM positions WHERE positionID = $1 FOR UPDATE`, positionID).Scan(&locked)
+    if errors.Is(err, sql.ErrNoRows) {
+        respondWithPositionNotFound(w, r, positionID)
+        return
+    }
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while locking position")
+        return
+    }
+    if !h.termIsFree(w, r, tx, term) {
+        return
+    }
+
+    created, err := store.InsertTerm(ctx, tx, term)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while inserting position term")
+        return
+    }
+    if err := tx.Commit(); err != nil {
+        respondWithDBError(w, r, err, "Error while committing position term")
+        return
+    }
+
+    location := fmt.Sprintf("/api/positions/%d/terms/%d", positionID, created.TermID)
+    models.RespondWithCreated(w, location, created)
+}
+
+// PATCH /api/positions/{positionID}/terms/{termID}
+//	@Summary		Update a term
+//	@Description	Change the title or semesters of a term, e.g. set endSemester when a Brother steps down. An empty endSemester reopens the term. Admins only
+//	@Tags			Positions
+//	@Accept			json
+//	@Produce		json
+//	@Param			body_params	body		handlers.UpdatePositionTerm.RequestBody	true	"Values to change"
+//	@Param			positionID	path		int										true	"Position ID"
+//	@Param			termID		path		int										true	"Term ID"
+//	@Success		200			{object}	models.APIResponse{data=models.PositionTerm}
+//	@Failure		400			{object}	models.APIResponse
+//	@Failure		401			{object}	models.APIResponse
+//	@Failure		403			{object}	models.APIResponse
+//	@Failure		404			{object}	models.APIResponse
+//	@Failure		409			{object}	models.APIResponse
+//	@Failure		422			{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/positions/{positionID}/terms/{termID} [patch]
+func (h *Handler) UpdatePositionTerm(w http.ResponseWriter, r *http.Request) {
+    if !canManagePositions(w, r) {
+        return
+    }
+    positionID, ok := idParam(w, r, "positionID", "position ID")
+    if !ok {
+        return
+    }
+    termID, ok := idParam(w, r, "termID", "term ID")
+    if !ok {
+        return
+    }
+
+    // Expected request body data. Omitted values are left unchanged
+    type RequestBody struct {
+        Title         *string `json:"title" validate:"omitempty,max=50"`
+        StartSemester *string `json:"startSemester"`
+        EndSemester   *string `json:"endSemester"`
+    }
+    var requestBody RequestBody
+    if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+        respondWithDecodeError(w, r, err)
+        return
+    }
+    if err := validate.Struct(requestBody); err != nil {
+        respondWithValidationError(w, r, err)
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    tx, err := h.db.BeginTx(ctx, nil)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while starting transaction")
+        return
+    }
+    defer tx.Rollback()
+
+    var locked int
+    err = tx.QueryRowContext(ctx, `SELECT positionID FROM positions WHERE positionID = $1 FOR UPDATE`, positionID).Scan(&locked)
+    if err != nil && !errors.Is(err, sql.ErrNoRows) {
+        respondWithDBError(w, r, err, "Error while locking position")
+        return
+    }
+    term, err := store.GetTerm(ctx, tx, positionID, termID)
+    if errors.Is(err, sql.ErrNoRows) {
+        respondWithTermNotFound(w, r, positionID, termID)
+        return
+    }
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while querying position term")
+        return
+    }
+
+    if requestBody.Title != nil {
+        term.Title = strings.TrimSpace(*requestBody.Title)
+    }
+    if requestBody.StartSemester != nil {
+        term.StartSemester = strings.TrimSpace(*requestBody.StartSemester)
+    }
+    if requestBody.EndSemester != nil {
+        term.EndSemester = strings.TrimSpace(*requestBody.EndSemester)
+    }
+    if !validTermSemesters(w, r, term) || !h.termIsFree(w, r, tx, term) {
+        return
+    }
+
+    updated, err := store.UpdateTerm(ctx, tx, term)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while updating position term")
+        return
+    }
+    if err := tx.Commit(); err != nil {
+        respondWithDBError(w, r, err, "Error while committing position term")
+        return
+    }
+
+    models.RespondWithSuccess(w, http.StatusOK, updated)
+}
+
+// DELETE /api/positions/{positionID}/terms/{termID}
+//	@Summary		Delete a term
+//	@Description	Delete a term entered by mistake. Set endSemester instead when a Brother steps down, to keep the history. Admins only
+//	@Tags			Positions
+//	@Param			positionID	path		int		true	"Position ID"
+//	@Param			termID		path		int		true	"Term ID"
+//	@Success		204
+//	@Failure		400			{object}	models.APIResponse
+//	@Failure		401			{object}	models.APIResponse
+//	@Failure		403			{object}	models.APIResponse
+//	@Failure		404			{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/positions/{positionID}/terms/{termID} [delete]
+func (h *Handler) DeletePositionTerm(w http.ResponseWriter, r *http.Request) {
+    if !canManagePositions(w, r) {
+        return
+    }
+    positionID, ok := idParam(w, r, "positionID", "position ID")
+    if !ok {
+        return
+    }
+    termID, ok := idParam(w, r, "termID", "term ID")
+    if !ok {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    err := store.DeleteTerm(ctx, h.db, positionID, termID)
+    if errors.Is(err, sql.ErrNoRows) {
+        respondWithTermNotFound(w, r, positionID, termID)
+        return
+    }
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while deleting position term")
+        return
+    }
+
+    w.WriteHeader(http.StatusNoContent)
+}
+
+// GET /api/semesters/{semester}/officers
+//	@Summary		Get the officers of a semester
+//	@Description	Get who held each office and committee during a semester, offices first
+//	@Tags			Positions
+//	@Produce		json
+//	@Param			semester	path		string	true	"Semester label, e.g. Fall 2024"
+//	@Param			kind		query		string	false	"Only offices or only committees"	Enums(office, committee)
+//	@Success		200			{object}	models.APIResponse{data=[]models.PositionTerm}
+//	@Failure		400			{object}	models.APIResponse
+//	@Router			/api/semesters/{semester}/officers [get]
+func (h *Handler) GetSemesterOfficers(w http.ResponseWriter, r *http.Request) {
+    semester := chi.URLParam(r, "semester")
+    if _, _, err := store.SemesterDates(semester); err != nil {
+        respondWithInvalidParam(w, r, "semester", err)
+        return
+    }
+    kind, ok := positionKindParam(w, r)
+    if !ok {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    terms, err := store.SemesterTerms(ctx, h.db, semester, kind)
+    if err != nil {
+        respondWithDBError(w, r, err, fmt.Sprintf("Error while querying officers of %s", semester))
+        return
+    }
+
+    models.RespondWithSuccess(w, http.StatusOK, terms)
+}
+
+// GET /api/brothers/{id}/positions
+//	@Summary		Get the positions of a Brother
+//	@Description	Get every office and committee a Brother has served in, latest first
+//	@Tags			Positions
+//	@Produce		json
+//	@Param			id		path		int		true	"Brother ID"
+//	@Success		200		{object}	models.APIResponse{data=[]models.PositionTerm}
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		404		{object}	models.APIResponse
+//	@Router			/api/brothers/{id}/positions [get]
+func (h *Handler) GetBrotherPositions(w http.ResponseWriter, r *http.Request) {
+    brotherID, ok := idParam(w, r, "id", "brother ID")
+    if !ok || !h.brotherExists(w, r, brotherID) {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    terms, err := store.BrotherTerms(ctx, h.db, brotherID)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while querying brother positions")
+        return
+    }
+
+    models.RespondWithSuccess(w, http.StatusOK, terms)
+}
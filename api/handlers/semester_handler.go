@@ -1,16 +1,17 @@
 package handlers
 
 import (
-	"context"
-	//"database/sql"
+	"database/sql"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"net/url"
 
 	"github.com/go-chi/chi"
-	"github.com/go-playground/validator/v10"
 	"github.com/pacific-theta-tau/tt-db/api/models"
+	"github.com/pacific-theta-tau/tt-db/logging"
+	"github.com/pacific-theta-tau/tt-db/store"
 )
 
 // Get all semester labels. E.g.: "Spring 2024"
@@ -22,16 +23,14 @@ import (
 //	@Failure		400		{object}	models.APIResponse
 //	@Router			/api/semesters [get]
 func (h *Handler) GetAllSemesterLabels(w http.ResponseWriter, r *http.Request) {
-    ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+    ctx, cancel := requestContext(r)
 	defer cancel()
 
     query := `SELECT semesterLabel FROM semester`
     rows, err := h.db.QueryContext(ctx, query)
-    log.Printf("Querying for semester labels:\n%s", query)
+    slog.DebugContext(r.Context(), "Query", "sql", query)
     if err != nil {
-        errMsg := fmt.Sprintf("Error while querying for semester data: `%s`\n", err.Error())
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDBError(w, r, err, "Error while querying for semester data")
         return
     }
 
@@ -41,9 +40,7 @@ func (h *Handler) GetAllSemesterLabels(w http.ResponseWriter, r *http.Request) {
         var label string
         err := rows.Scan(&label)
         if err != nil {
-            errMsg := fmt.Sprintf("Error creating Semester label slice from row: '%s'\n", err.Error())
-			log.Println(errMsg)
-            models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+            respondWithInternalError(w, r, err, "Error creating Semester label slice from row")
 			return
 		}
 
@@ -55,25 +52,38 @@ func (h *Handler) GetAllSemesterLabels(w http.ResponseWriter, r *http.Request) {
 }
 
 
-// Helper function to get semesterID related to a semesterLabel
-func (h *Handler) GetSemesterIdBySemesterLabel(semesterLabel string) (int, error) {
-    ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+// Get a single semester by its label. E.g.: "Spring 2024"
+/* GET /api/semesters/{semester} */
+//	@Summary		Get semester
+//	@Description	Get semester ID and label for a semester label (e.g. "Spring 2024")
+//	@Tags		    Semesters
+//	@Param			semester path	string  true	"Semester Label (e.g. `Fall 2023`)"
+//	@Success		200		object		models.APIResponse{data=models.Semester}
+//	@Failure		404		{object}	models.APIResponse
+//	@Router			/api/semesters/{semester} [get]
+func (h *Handler) GetSemesterByLabel(w http.ResponseWriter, r *http.Request) {
+    ctx, cancel := requestContext(r)
     defer cancel()
 
-    query := `
-    SELECT semesterID
-    FROM semester
-    WHERE semesterLabel = $1
-    `
-    var semesterID int
-    err := h.db.QueryRowContext(ctx, query, semesterLabel).Scan(&semesterID)
-    log.Printf("Querying for semester labels:\n%s", query)
+    semesterLabel := chi.URLParam(r, "semester")
+    query := `SELECT semesterID, semesterLabel FROM semester WHERE semesterLabel = $1`
+    var semester models.Semester
+    err := h.db.QueryRowContext(ctx, query, semesterLabel).Scan(
+        &semester.SemesterID,
+        &semester.SemesterLabel,
+    )
+    if err == sql.ErrNoRows {
+        errMsg := fmt.Sprintf("Semester %s not found", semesterLabel)
+        slog.InfoContext(r.Context(), errMsg)
+        models.RespondWithFail(w, http.StatusNotFound, errMsg)
+        return
+    }
     if err != nil {
-        errMsg := fmt.Sprintf("Error while querying for semesterID: `%s`\n", err.Error())
-        log.Println(errMsg)
-        return -1, err
+        respondWithDBError(w, r, err, fmt.Sprintf("Error while querying for semester %s", semesterLabel))
+        return
     }
-    return semesterID, nil
+
+    models.RespondWithSuccess(w, http.StatusOK, semester)
 }
 
 
@@ -83,36 +93,38 @@ func (h *Handler) GetSemesterIdBySemesterLabel(semesterLabel string) (int, error
 //	@Description	Create semester label (e.g. Spring 2024)
 //	@Tags		    Semesters 
 //	@Param			semester body	string  true	"Semester Label (e.g. `Fall 2023`)"
-//	@Success		200		object		models.APIResponse
+//	@Success		201		object		models.APIResponse{data=models.Semester}
 //	@Failure		400		{object}	models.APIResponse
+//	@Failure		409		{object}	models.APIResponse
+//	@Failure		422		{object}	models.APIResponse
 //	@Router			/api/semesters [post]
 func (h *Handler) CreateSemesterLabel(w http.ResponseWriter, r *http.Request) {
-    ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+    ctx, cancel := requestContext(r)
     defer cancel()
 
     var requestBody struct {
-        Semester    string `json:"semester"`
+        Semester    string `json:"semester" validate:"required"`
     }
     err := json.NewDecoder(r.Body).Decode(&requestBody)
     if err != nil {
-        errMsg := fmt.Sprintf("Error while parsing request body:\n%s\n", err.Error())
-        log.Println(err)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDecodeError(w, r, err)
+        return
     }
-    log.Printf("request body data: `%v%`", requestBody)
+    if err := validate.Struct(requestBody); err != nil {
+        respondWithValidationError(w, r, err)
+        return
+    }
+    slog.DebugContext(r.Context(), "Request body", "body", logging.Body(requestBody))
 
-    query := `INSERT INTO semester (semesterLabel) VALUES ($1)`
-    log.Printf("Query insert:\n%s\n", query)
-    _, err = h.db.QueryContext(ctx, query, requestBody.Semester)
+    semester, err := store.CreateSemester(ctx, h.db, requestBody.Semester)
     if err != nil {
-        errMsg := fmt.Sprintf("Query error:\n\t'%s'\n", err.Error())
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDBError(w, r, err, "Error while inserting semester")
 		return
 	}
 
-    models.RespondWithSuccess(w, http.StatusCreated, "")
-} 
+    location := fmt.Sprintf("/api/semesters/%s", url.PathEscape(semester.SemesterLabel))
+    models.RespondWithCreated(w, location, semester)
+}
 
 
 // TODO: move status-related endpoint to status-handler.go
@@ -123,16 +135,16 @@ func (h *Handler) CreateSemesterLabel(w http.ResponseWriter, r *http.Request) {
 //	@Failure		400		{object}	models.APIResponse
 //	@Router			/api/semesters/{semesterLabel}/statuses [get]
 func (h *Handler) GetAllBrotherStatusesForSemester(w http.ResponseWriter, r *http.Request) {
-    ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+    ctx, cancel := requestContext(r)
     defer cancel()
 
     // urlParams: if none provided, get for all semesters
     semester := chi.URLParam(r, "semester")
-    log.Printf("Semester url param: %s", semester)
+    slog.DebugContext(r.Context(), "Semester url param", "semester", semester)
     if semester == "" {
         errMsg := "Missing semester in query params"
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        slog.InfoContext(r.Context(), errMsg)
+        models.RespondWithFailCode(w, http.StatusBadRequest, models.CodeInvalidParameter, errMsg)
         return
     }
 
@@ -143,12 +155,10 @@ func (h *Handler) GetAllBrotherStatusesForSemester(w http.ResponseWriter, r *htt
     JOIN semester s ON s.semesterID = bs.semesterID
     WHERE semesterLabel = $1
     `
-    log.Printf("Query:\n%s\n", query)
+    slog.DebugContext(r.Context(), "Query", "sql", query)
     rows, err := h.db.QueryContext(ctx, query, semester)
     if err != nil {
-        errMsg := fmt.Sprintf("Error while querying brother statuses for semester %s: %s", semester, err.Error())
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDBError(w, r, err, fmt.Sprintf("Error while querying brother statuses for semester %s", semester))
         return
     }
 
@@ -156,9 +166,7 @@ func (h *Handler) GetAllBrotherStatusesForSemester(w http.ResponseWriter, r *htt
     for rows.Next() {
         brotherStatus, err := models.CreateBrotherStatusFromSemesterFromRow(rows)
         if err != nil {
-            errMsg := fmt.Sprintf("Error while parsing query: %s", err.Error())
-            log.Println(errMsg)
-            models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+            respondWithInternalError(w, r, err, "Error while parsing query")
             return
         }
         brotherStatuses = append(brotherStatuses, &brotherStatus)
@@ -173,29 +181,35 @@ func (h *Handler) GetAllBrotherStatusesForSemester(w http.ResponseWriter, r *htt
 //	@Param			semesterLabel path string true	"semesterLabel"
 //	@Param			brotherID body int											true	"BrotherID"
 //	@Param			status body string true	"Status"
-//	@Success		200		object		models.APIResponse{data=models.Attendance}
+//	@Success		201		object		models.APIResponse{data=models.StatusRecord}
 //	@Failure		400		{object}	models.APIResponse
+//	@Failure		409		{object}	models.APIResponse
+//	@Failure		422		{object}	models.APIResponse
 //	@Router			/api/semesters/{semesterLabel}/statuses [post]
 func (h *Handler) CreateBrotherStatusForSemester(w http.ResponseWriter, r *http.Request) {
-    ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+    ctx, cancel := requestContext(r)
     defer cancel()
 
     // urlParams: if none provided, get for all semesters
     semesterLabel := chi.URLParam(r, "semester")
-    log.Printf("Semester url param: %s", semesterLabel)
+    slog.DebugContext(r.Context(), "Semester url param", "semester", semesterLabel)
     if semesterLabel == "" {
         errMsg := "Missing semester in query params"
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        slog.InfoContext(r.Context(), errMsg)
+        models.RespondWithFailCode(w, http.StatusBadRequest, models.CodeInvalidParameter, errMsg)
         return
     }
 
     // Get SemesterID related to semesterLabel
-    semesterID, err := h.GetSemesterIdBySemesterLabel(semesterLabel)
+    semesterID, err := store.SemesterIDByLabel(ctx, h.db, semesterLabel)
+    if err == sql.ErrNoRows {
+        errMsg := fmt.Sprintf("Semester %s not found", semesterLabel)
+        slog.InfoContext(r.Context(), errMsg)
+        models.RespondWithFail(w, http.StatusNotFound, errMsg)
+        return
+    }
     if err != nil {
-        errMsg := fmt.Sprintf("Error while querying for semesterID", err.Error())
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        respondWithDBError(w, r, err, "Error while querying for semesterID")
         return
     }
 
@@ -207,41 +221,24 @@ func (h *Handler) CreateBrotherStatusForSemester(w http.ResponseWriter, r *http.
     var bodyParams RequestBody
     err = json.NewDecoder(r.Body).Decode(&bodyParams)
     if err != nil {
-        errMsg := fmt.Sprintf("Error while decoding body params: %s", err)
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDecodeError(w, r, err)
+        return
     }
 
     // Validate data provided in request body
-    validate := validator.New()
 	if err := validate.Struct(bodyParams); err != nil {
-        errMsg := fmt.Sprintf("Missing or Invalid request body params: %s", err)
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithValidationError(w, r, err)
 		return
 	}
 
     // Query INSERT
-    query := `
-    INSERT INTO brotherStatus (brotherID, semesterID, status)
-    VALUES
-        ($1, $2, $3)
-    RETURNING *
-    `
-    _, err = h.db.QueryContext(
-        ctx,
-        query,
-        bodyParams.BrotherID,
-        semesterID,
-        bodyParams.Status,
-    )
+    statusRecord, err := store.InsertBrotherStatus(ctx, h.db, bodyParams.BrotherID, semesterID, bodyParams.Status)
     if err != nil {
-        errMsg := fmt.Sprintf("Error while querying brother statuses for semester %s: %s", semesterLabel, err.Error())
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDBError(w, r, err, fmt.Sprintf("Error while inserting brother status for semester %s", semesterLabel))
         return
     }
-    
-    models.RespondWithSuccess(w, http.StatusCreated, "")
+
+    location := fmt.Sprintf("/api/brothers/%d/statuses", statusRecord.BrotherID)
+    models.RespondWithCreated(w, location, statusRecord)
 }
 
@@ -2,17 +2,16 @@
 package handlers
 
 import (
-	"context"
 	"strconv"
-	//"database/sql"
+	"database/sql"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 
 	"github.com/go-chi/chi"
-	"github.com/go-playground/validator/v10"
 	"github.com/pacific-theta-tau/tt-db/api/models"
+	"github.com/pacific-theta-tau/tt-db/store"
 )
 
 // GET /api/statuses
@@ -33,7 +32,7 @@ func (h *Handler) GetAllStatusLabels(w http.ResponseWriter, r *http.Request) {
 //      make POST /api/brohters/statuses body receive a `brotherID`, while POST /api/brothers/{id}/statuses uses urlParams
 /* POST /statuses */
 func (h *Handler) CreateStatusForBrother(w http.ResponseWriter, r *http.Request) {
-    ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+    ctx, cancel := requestContext(r)
     defer cancel()
 
     var requestBody struct {
@@ -44,41 +43,24 @@ func (h *Handler) CreateStatusForBrother(w http.ResponseWriter, r *http.Request)
     // Parse body
     err := json.NewDecoder(r.Body).Decode(&requestBody)
     if err != nil {
-        errMsg := fmt.Sprintf("Error while parsing request body data: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDecodeError(w, r, err)
         return
     }
 
     // Validate received data
-    validate := validator.New()
     if err := validate.Struct(requestBody); err != nil {
-        errMsg := fmt.Sprintf("Invalid Input: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        respondWithValidationError(w, r, err)
         return
     }
 
-    // Validate received data
-    query := `
-    INSERT INTO brotherStatus (brotherID, semesterID, status)
-    VALUES ($1, $2, $3)
-    `
-    _, err = h.db.QueryContext(
-        ctx,
-        query,
-        requestBody.BrotherID,
-        requestBody.SemesterID,
-        requestBody.Status,
-    )
+    statusRecord, err := store.InsertBrotherStatus(ctx, h.db, requestBody.BrotherID, requestBody.SemesterID, requestBody.Status)
     if err != nil {
-        errMsg := fmt.Sprintf("Query error:\n\t'%s'\n", err.Error())
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDBError(w, r, err, "Error while inserting brother status")
 		return
 	}
 
-    models.RespondWithSuccess(w, http.StatusCreated, "")
+    location := fmt.Sprintf("/api/brothers/%d/statuses", statusRecord.BrotherID)
+    models.RespondWithCreated(w, location, statusRecord)
 }
 
 
@@ -92,7 +74,7 @@ func (h *Handler) CreateStatusForBrother(w http.ResponseWriter, r *http.Request)
 //	@Failure		400		{object}	models.APIResponse
 //	@Router			/api/brothers/{brotherID}/statuses/{semesterID} [delete]
 func (h* Handler) DeleteStatusByMemberAndSemesterHandler(w http.ResponseWriter, r *http.Request) {
-    ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+    ctx, cancel := requestContext(r)
     defer cancel()
 
     brotherID := chi.URLParam(r, "brotherID")
@@ -100,17 +82,15 @@ func (h* Handler) DeleteStatusByMemberAndSemesterHandler(w http.ResponseWriter,
 
     if brotherID == "" || semesterID == "" {
         errMsg := "brotherID and semesterID are required"
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusBadRequest, errMsg)
+        slog.InfoContext(r.Context(), errMsg)
+        models.RespondWithFailCode(w, http.StatusBadRequest, models.CodeInvalidParameter, errMsg)
         return
     }
 
     query := "DELETE FROM brotherStatus WHERE brotherID = $1 AND semesterID = $2"
     _, err := h.db.ExecContext(ctx, query, brotherID, semesterID)
     if err != nil {
-        errMsg := fmt.Sprintf("Error while querying:\n\t'%s'\n", err.Error())
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDBError(w, r, err, "Error while deleting brother status")
 		return
 	}
 
@@ -125,26 +105,22 @@ func (h* Handler) DeleteStatusByMemberAndSemesterHandler(w http.ResponseWriter,
 //  @Param  brotherID path   string true "brotherID"
 //  @Param  semesterID body int true "semesterID"
 //  @Param  status body string true "body"
-//	@Success		200		object		models.APIResponse{data=[]string}
+//	@Success		200		object		models.APIResponse{data=models.StatusRecord}
 //	@Failure		400		{object}	models.APIResponse
 //	@Router			/api/brothers/{brotherID}/statuses [patch]
 func (h* Handler) UpdateBrotherStatusByBrotherID(w http.ResponseWriter, r *http.Request) {
-    ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+    ctx, cancel := requestContext(r)
     defer cancel()
 
     // parse url params
     brotherID := chi.URLParam(r, "id")
     if brotherID == "" {
-        errMsg := "Missing brotherID in url params"
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        respondWithInvalidParam(w, r, "brother ID", fmt.Errorf("missing brotherID"))
         return
     }
     brotherIDInt, err  := strconv.Atoi(brotherID)
     if err != nil {
-        errMsg := fmt.Sprintf("Error converting brotherID to int. Error: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithInvalidParam(w, r, "brother ID", err)
         return
     }
 
@@ -156,18 +132,13 @@ func (h* Handler) UpdateBrotherStatusByBrotherID(w http.ResponseWriter, r *http.
     }
     err = json.NewDecoder(r.Body).Decode(&requestBody)
     if err != nil {
-        errMsg := fmt.Sprintf("Error while parsing request body. Error: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDecodeError(w, r, err)
         return
     }
     
     // validate request body
-    validate := validator.New()
     if err := validate.Struct(requestBody); err != nil {
-        errMsg := fmt.Sprintf("Invalid Input: %s", err.Error())
-        log.Println(errMsg)
-        models.RespondWithFail(w, http.StatusBadRequest, errMsg)
+        respondWithValidationError(w, r, err)
         return
     }
 
@@ -181,14 +152,17 @@ func (h* Handler) UpdateBrotherStatusByBrotherID(w http.ResponseWriter, r *http.
     //}
 
     // Query Database
-    query := "UPDATE brotherStatus SET status = $1 WHERE brotherID = $2 AND semesterID = $3"
-    _, err = h.db.ExecContext(ctx, query, requestBody.Status, brotherIDInt, requestBody.SemesterID)
+    statusRecord, err := store.UpdateBrotherStatus(ctx, h.db, brotherIDInt, requestBody.SemesterID, requestBody.Status)
+    if err == sql.ErrNoRows {
+        errMsg := fmt.Sprintf("No status found for brotherID %d and semesterID %d", brotherIDInt, requestBody.SemesterID)
+        slog.InfoContext(r.Context(), errMsg)
+        models.RespondWithFail(w, http.StatusNotFound, errMsg)
+        return
+    }
     if err != nil {
-        errMsg := fmt.Sprintf("Error while querying:\n\t'%s'\n", err.Error())
-        log.Println(errMsg)
-        models.RespondWithError(w, http.StatusInternalServerError, errMsg)
+        respondWithDBError(w, r, err, "Error while updating brother status")
 		return
 	}
 
-    models.RespondWithSuccess(w, http.StatusOK, "")
+    models.RespondWithSuccess(w, http.StatusOK, statusRecord)
 }
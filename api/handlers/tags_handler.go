@@ -0,0 +1,416 @@
+// tags_handler.go: Handle requests for tags, the ad-hoc groupings of brothers and events
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi"
+	apimiddleware "github.com/pacific-theta-tau/tt-db/api/middleware"
+	"github.com/pacific-theta-tau/tt-db/api/models"
+	"github.com/pacific-theta-tau/tt-db/store"
+)
+
+// What tags are put on: brothers or events
+type tagTarget struct {
+	target store.TagTarget
+	// URL parameter holding the brother or event ID
+	param string
+	// Used in messages
+	name string
+}
+
+var (
+	brotherTags = tagTarget{store.BrotherTags, "id", "Brother"}
+	eventTags   = tagTarget{store.EventTags, "eventID", "Event"}
+)
+
+// Request body of tag creation and renaming
+type tagRequest struct {
+	Name string `json:"name" validate:"required,max=50"`
+}
+
+// Responds with 401 or 403 and returns false unless the signed in user is an officer or admin. Only officers change tags
+func canWriteTags(w http.ResponseWriter, r *http.Request) bool {
+    if apimiddleware.CanSeeOfficerData(r.Context()) {
+        return true
+    }
+    respondWithDenied(w, r, "Member tried to change a tag", "Only officers can change tags")
+    return false
+}
+
+// Parses the URL parameter holding an ID. Responds with an error and returns false if it is invalid
+func idParam(w http.ResponseWriter, r *http.Request, param string, name string) (int, bool) {
+    id, err := strconv.Atoi(chi.URLParam(r, param))
+    if err != nil {
+        respondWithInvalidParam(w, r, name, err)
+        return 0, false
+    }
+    return id, true
+}
+
+// Decodes and validates a tag name from the request body. Responds with an error and returns false if it is invalid
+func decodeTagName(w http.ResponseWriter, r *http.Request) (string, bool) {
+    var requestBody tagRequest
+    if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+        respondWithDecodeError(w, r, err)
+        return "", false
+    }
+    requestBody.Name = strings.TrimSpace(requestBody.Name)
+    if err := validate.Struct(requestBody); err != nil {
+        respondWithValidationError(w, r, err)
+        return "", false
+    }
+    return requestBody.Name, true
+}
+
+// Responds with 404 for a tag that doesn't exist
+func respondWithTagNotFound(w http.ResponseWriter, r *http.Request, tagID int) {
+    errMsg := fmt.Sprintf("Tag %d not found", tagID)
+    slog.InfoContext(r.Context(), errMsg)
+    models.RespondWithFail(w, http.StatusNotFound, errMsg)
+}
+
+// GET /api/tags
+//	@Summary		Get tags
+//	@Description	Get every tag, sorted by name
+//	@Tags			Tags
+//	@Produce		json
+//	@Success		200		{object}	models.APIResponse{data=[]models.Tag}
+//	@Failure		500		{object}	models.APIResponse
+//	@Router			/api/tags [get]
+func (h *Handler) GetTags(w http.ResponseWriter, r *http.Request) {
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    tags, err := store.Tags(ctx, h.db)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while querying tags")
+        return
+    }
+
+    models.RespondWithSuccess(w, http.StatusOK, tags)
+}
+
+// GET /api/tags/count
+//	@Summary		Get tag counts
+//	@Description	Get how many Brothers and Events have each tag, sorted by tag name
+//	@Tags			Tags
+//	@Produce		json
+//	@Success		200		{object}	models.APIResponse{data=[]models.TagCount}
+//	@Failure		500		{object}	models.APIResponse
+//	@Router			/api/tags/count [get]
+func (h *Handler) GetTagCounts(w http.ResponseWriter, r *http.Request) {
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    counts, err := store.TagCounts(ctx, h.db)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while counting tags")
+        return
+    }
+
+    models.RespondWithSuccess(w, http.StatusOK, counts)
+}
+
+// POST /api/tags
+//	@Summary		Create a tag
+//	@Description	Create a tag that Brothers and Events can be tagged with. Names are unique regardless of case
+//	@Tags			Tags
+//	@Accept			json
+//	@Produce		json
+//	@Param			body_params body		handlers.tagRequest	true	"Tag to create"
+//	@Success		201		{object}	models.APIResponse{data=models.Tag}
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		401		{object}	models.APIResponse
+//	@Failure		403		{object}	models.APIResponse
+//	@Failure		409		{object}	models.APIResponse
+//	@Failure		422		{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/tags [post]
+func (h *Handler) CreateTag(w http.ResponseWriter, r *http.Request) {
+    if !canWriteTags(w, r) {
+        return
+    }
+    name, ok := decodeTagName(w, r)
+    if !ok {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    tag, err := store.InsertTag(ctx, h.db, name)
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while inserting tag")
+        return
+    }
+
+    location := fmt.Sprintf("/api/tags/%d", tag.TagID)
+    models.RespondWithCreated(w, location, tag)
+}
+
+// PATCH /api/tags/{tagID}
+//	@Summary		Rename a tag
+//	@Tags			Tags
+//	@Accept			json
+//	@Produce		json
+//	@Param			body_params body		handlers.tagRequest	true	"New name"
+//	@Param			tagID	path		int					true	"Tag ID"
+//	@Success		200		{object}	models.APIResponse{data=models.Tag}
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		401		{object}	models.APIResponse
+//	@Failure		403		{object}	models.APIResponse
+//	@Failure		404		{object}	models.APIResponse
+//	@Failure		409		{object}	models.APIResponse
+//	@Failure		422		{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/tags/{tagID} [patch]
+func (h *Handler) RenameTag(w http.ResponseWriter, r *http.Request) {
+    if !canWriteTags(w, r) {
+        return
+    }
+    tagID, ok := idParam(w, r, "tagID", "tag ID")
+    if !ok {
+        return
+    }
+    name, ok := decodeTagName(w, r)
+    if !ok {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    tag, err := store.RenameTag(ctx, h.db, tagID, name)
+    if errors.Is(err, sql.ErrNoRows) {
+        respondWithTagNotFound(w, r, tagID)
+        return
+    }
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while renaming tag")
+        return
+    }
+
+    models.RespondWithSuccess(w, http.StatusOK, tag)
+}
+
+// DELETE /api/tags/{tagID}
+//	@Summary		Delete a tag
+//	@Description	Delete a tag and take it off every Brother and Event
+//	@Tags			Tags
+//	@Produce		json
+//	@Param			tagID	path		int		true	"Tag ID"
+//	@Success		200		{object}	models.APIResponse{data=models.Tag}
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		401		{object}	models.APIResponse
+//	@Failure		403		{object}	models.APIResponse
+//	@Failure		404		{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/tags/{tagID} [delete]
+func (h *Handler) DeleteTag(w http.ResponseWriter, r *http.Request) {
+    if !canWriteTags(w, r) {
+        return
+    }
+    tagID, ok := idParam(w, r, "tagID", "tag ID")
+    if !ok {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    tag, err := store.DeleteTag(ctx, h.db, tagID)
+    if errors.Is(err, sql.ErrNoRows) {
+        respondWithTagNotFound(w, r, tagID)
+        return
+    }
+    if err != nil {
+        respondWithDBError(w, r, err, "Error while deleting tag")
+        return
+    }
+    slog.InfoContext(r.Context(), "Deleted tag", "tag_id", tag.TagID, "name", tag.Name)
+
+    models.RespondWithSuccess(w, http.StatusOK, tag)
+}
+
+// Responds with 404 and returns false if the brother or event doesn't exist
+func (h *Handler) tagTargetExists(w http.ResponseWriter, r *http.Request, target tagTarget, id int) bool {
+    if target.target == store.EventTags {
+        return h.eventExists(w, r, id)
+    }
+    return h.brotherExists(w, r, id)
+}
+
+func (h *Handler) listTagsOf(w http.ResponseWriter, r *http.Request, target tagTarget) {
+    id, ok := idParam(w, r, target.param, strings.ToLower(target.name)+" ID")
+    if !ok || !h.tagTargetExists(w, r, target, id) {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    tags, err := store.TagsOf(ctx, h.db, target.target, id)
+    if err != nil {
+        respondWithDBError(w, r, err, fmt.Sprintf("Error while querying %s tags", strings.ToLower(target.name)))
+        return
+    }
+
+    models.RespondWithSuccess(w, http.StatusOK, tags)
+}
+
+// Puts the tag on the brother or event, or takes it off, and responds with its tags
+func (h *Handler) changeTagsOf(w http.ResponseWriter, r *http.Request, target tagTarget, add bool) {
+    if !canWriteTags(w, r) {
+        return
+    }
+    id, ok := idParam(w, r, target.param, strings.ToLower(target.name)+" ID")
+    if !ok {
+        return
+    }
+    tagID, ok := idParam(w, r, "tagID", "tag ID")
+    if !ok || !h.tagTargetExists(w, r, target, id) {
+        return
+    }
+
+    ctx, cancel := requestContext(r)
+    defer cancel()
+
+    if add {
+        if _, err := store.GetTag(ctx, h.db, tagID); errors.Is(err, sql.ErrNoRows) {
+            respondWithTagNotFound(w, r, tagID)
+            return
+        } else if err != nil {
+            respondWithDBError(w, r, err, "Error while querying tag")
+            return
+        }
+        if err := store.AddTag(ctx, h.db, target.target, id, tagID); err != nil {
+            respondWithDBError(w, r, err, fmt.Sprintf("Error while tagging %s", strings.ToLower(target.name)))
+            return
+        }
+    } else {
+        removed, err := store.RemoveTag(ctx, h.db, target.target, id, tagID)
+        if err != nil {
+            respondWithDBError(w, r, err, fmt.Sprintf("Error while untagging %s", strings.ToLower(target.name)))
+            return
+        }
+        if !removed {
+            errMsg := fmt.Sprintf("%s ID %d has no tag %d", target.name, id, tagID)
+            slog.InfoContext(r.Context(), errMsg)
+            models.RespondWithFail(w, http.StatusNotFound, errMsg)
+            return
+        }
+    }
+
+    tags, err := store.TagsOf(ctx, h.db, target.target, id)
+    if err != nil {
+        respondWithDBError(w, r, err, fmt.Sprintf("Error while querying %s tags", strings.ToLower(target.name)))
+        return
+    }
+    models.RespondWithSuccess(w, http.StatusOK, tags)
+}
+
+// GET /api/brothers/{id}/tags
+//	@Summary		Get tags of a Brother
+//	@Tags			Tags
+//	@Produce		json
+//	@Param			id		path		int		true	"Brother ID"
+//	@Success		200		{object}	models.APIResponse{data=[]models.Tag}
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		404		{object}	models.APIResponse
+//	@Router			/api/brothers/{id}/tags [get]
+func (h *Handler) GetBrotherTags(w http.ResponseWriter, r *http.Request) {
+    h.listTagsOf(w, r, brotherTags)
+}
+
+// PUT /api/brothers/{id}/tags/{tagID}
+//	@Summary		Tag a Brother
+//	@Description	Put a tag on a Brother and get the Brother's tags. Tagging twice is not an error
+//	@Tags			Tags
+//	@Produce		json
+//	@Param			id		path		int		true	"Brother ID"
+//	@Param			tagID	path		int		true	"Tag ID"
+//	@Success		200		{object}	models.APIResponse{data=[]models.Tag}
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		401		{object}	models.APIResponse
+//	@Failure		403		{object}	models.APIResponse
+//	@Failure		404		{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/brothers/{id}/tags/{tagID} [put]
+func (h *Handler) TagBrother(w http.ResponseWriter, r *http.Request) {
+    h.changeTagsOf(w, r, brotherTags, true)
+}
+
+// DELETE /api/brothers/{id}/tags/{tagID}
+//	@Summary		Untag a Brother
+//	@Description	Take a tag off a Brother and get the Brother's remaining tags
+//	@Tags			Tags
+//	@Produce		json
+//	@Param			id		path		int		true	"Brother ID"
+//	@Param			tagID	path		int		true	"Tag ID"
+//	@Success		200		{object}	models.APIResponse{data=[]models.Tag}
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		401		{object}	models.APIResponse
+//	@Failure		403		{object}	models.APIResponse
+//	@Failure		404		{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/brothers/{id}/tags/{tagID} [delete]
+func (h *Handler) UntagBrother(w http.ResponseWriter, r *http.Request) {
+    h.changeTagsOf(w, r, brotherTags, false)
+}
+
+// GET /api/events/{eventID}/tags
+//	@Summary		Get tags of an Event
+//	@Tags			Tags
+//	@Produce		json
+//	@Param			eventID	path		int		true	"Event ID"
+//	@Success		200		{object}	models.APIResponse{data=[]models.Tag}
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		404		{object}	models.APIResponse
+//	@Router			/api/events/{eventID}/tags [get]
+func (h *Handler) GetEventTags(w http.ResponseWriter, r *http.Request) {
+    h.listTagsOf(w, r, eventTags)
+}
+
+// PUT /api/events/{eventID}/tags/{tagID}
+//	@Summary		Tag an Event
+//	@Description	Put a tag on an Event and get the Event's tags. Tagging twice is not an error
+//	@Tags			Tags
+//	@Produce		json
+//	@Param			eventID	path		int		true	"Event ID"
+//	@Param			tagID	path		int		true	"Tag ID"
+//	@Success		200		{object}	models.APIResponse{data=[]models.Tag}
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		401		{object}	models.APIResponse
+//	@Failure		403		{object}	models.APIResponse
+//	@Failure		404		{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/events/{eventID}/tags/{tagID} [put]
+func (h *Handler) TagEvent(w http.ResponseWriter, r *http.Request) {
+    h.changeTagsOf(w, r, eventTags, true)
+}
+
+// DELETE /api/events/{eventID}/tags/{tagID}
+//	@Summary		Untag an Event
+//	@Description	Take a tag off an Event and get the Event's remaining tags
+//	@Tags			Tags
+//	@Produce		json
+//	@Param			eventID	path		int		true	"Event ID"
+//	@Param			tagID	path		int		true	"Tag ID"
+//	@Success		200		{object}	models.APIResponse{data=[]models.Tag}
+//	@Failure		400		{object}	models.APIResponse
+//	@Failure		401		{object}	models.APIResponse
+//	@Failure		403		{object}	models.APIResponse
+//	@Failure		404		{object}	models.APIResponse
+//	@Security		BasicAuth
+//	@Router			/api/events/{eventID}/tags/{tagID} [delete]
+func (h *Handler) UntagEvent(w http.ResponseWriter, r *http.Request) {
+    h.changeTagsOf(w, r, eventTags, false)
+}
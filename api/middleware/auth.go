@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Realm sent in WWW-Authenticate challenges
+const authRealm = `Basic realm="tt-db", charset="UTF-8"`
+
+// Returns the user with an email and their bcrypt password hash, or sql.ErrNoRows if there is none
+type UserLookup func(ctx context.Context, email string) (models.User, string, error)
+
+// Hash compared against when the email is unknown, so unknown and known emails take as long to reject
+var dummyHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("not a password"), bcrypt.DefaultCost)
+	return hash
+})
+
+// Authenticate signs in requests that send HTTP Basic credentials (a user's email and password) and stores
+// the user in the request context with WithUser. Requests without credentials go on anonymously, so they only
+// get what is open to everyone. Wrong credentials get a 401
+func Authenticate(lookup UserLookup) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			email, password, ok := r.BasicAuth()
+			if !ok {
+				RespondUnauthorized(w, "Authorization must use the Basic scheme with an email and password")
+				return
+			}
+
+			user, hash, err := lookup(r.Context(), email)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				slog.ErrorContext(r.Context(), "Error while looking up user", "error", err)
+				models.RespondWithError(w, http.StatusInternalServerError, "Internal server error")
+				return
+			}
+			if err != nil {
+				bcrypt.CompareHashAndPassword(dummyHash(), []byte(password))
+			}
+			if err != nil || bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil {
+				slog.InfoContext(r.Context(), "Failed sign in", "email", email)
+				RespondUnauthorized(w, "Invalid email or password")
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(WithUser(r.Context(), user)))
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// Sends a 401 JSend fail response asking the client to sign in with Basic credentials
+func RespondUnauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("WWW-Authenticate", authRealm)
+	models.RespondWithFail(w, http.StatusUnauthorized, message)
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestAuthenticate(t *testing.T) {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	officer := models.User{UserID: 2, Email: "officer@example.com", Role: models.RoleOfficer}
+	lookup := func(ctx context.Context, email string) (models.User, string, error) {
+		switch email {
+		case officer.Email:
+			return officer, string(hash), nil
+		case "broken@example.com":
+			return models.User{}, "", errors.New("connection refused")
+		}
+		return models.User{}, "", sql.ErrNoRows
+	}
+	var signedIn *models.User
+	handler := Authenticate(lookup)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signedIn = nil
+		if user, ok := UserFromContext(r.Context()); ok {
+			signedIn = &user
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name     string
+		setAuth  func(r *http.Request)
+		status   int
+		signedIn bool
+	}{
+		{"no credentials", func(r *http.Request) {}, http.StatusOK, false},
+		{"valid credentials", func(r *http.Request) { r.SetBasicAuth("officer@example.com", "correct horse") }, http.StatusOK, true},
+		{"wrong password", func(r *http.Request) { r.SetBasicAuth("officer@example.com", "wrong") }, http.StatusUnauthorized, false},
+		{"unknown email", func(r *http.Request) { r.SetBasicAuth("nobody@example.com", "correct horse") }, http.StatusUnauthorized, false},
+		{"other scheme", func(r *http.Request) { r.Header.Set("Authorization", "Bearer abc") }, http.StatusUnauthorized, false},
+		{"lookup error", func(r *http.Request) { r.SetBasicAuth("broken@example.com", "x") }, http.StatusInternalServerError, false},
+	}
+	for _, tt := range tests {
+		signedIn = nil
+		r := httptest.NewRequest("GET", "/", nil)
+		tt.setAuth(r)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, r)
+
+		if rr.Code != tt.status {
+			t.Errorf("%s: expected %d. Got %d", tt.name, tt.status, rr.Code)
+		}
+		if (signedIn != nil) != tt.signedIn || (signedIn != nil && signedIn.UserID != officer.UserID) {
+			t.Errorf("%s: expected signed in %v. Got %+v", tt.name, tt.signedIn, signedIn)
+		}
+		if rr.Code == http.StatusUnauthorized && rr.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("%s: expected a WWW-Authenticate challenge", tt.name)
+		}
+	}
+}
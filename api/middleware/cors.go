@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-chi/cors"
+)
+
+// Cross-origin settings, set per environment
+type CORSConfig struct {
+	// Origins allowed to call the API, e.g. https://tt-db.example.com.
+	// A single * in the host allows subdomains (https://*.example.com). Empty denies all cross-origin requests
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+	// Response headers readable by browser code
+	ExposedHeaders []string
+	// Allow cookies and Authorization headers on cross-origin requests. Requires explicit origins
+	AllowCredentials bool
+	// How long browsers may cache a preflight response
+	MaxAge time.Duration
+}
+
+// Returns the CORS settings used when none are configured. No origins are allowed
+func DefaultCORSConfig() CORSConfig {
+	return CORSConfig{
+		AllowedMethods:   []string{"GET", "PATCH", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Request-ID", "traceparent", "tracestate"},
+		ExposedHeaders:   []string{"Link", "X-Request-ID"},
+		AllowCredentials: true,
+		MaxAge:           5 * time.Minute, // Maximum value not ignored by any of major browsers
+	}
+}
+
+// Checks that the origins are well formed. Browsers reject a wildcard origin on credentialed requests
+func (c CORSConfig) Validate() error {
+	var errs []error
+	for _, origin := range c.AllowedOrigins {
+		if origin == "*" {
+			if c.AllowCredentials {
+				errs = append(errs, errors.New("CORS_ALLOWED_ORIGINS cannot be * when CORS_ALLOW_CREDENTIALS is true. List the origins instead"))
+			}
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+			errs = append(errs, fmt.Errorf("CORS_ALLOWED_ORIGINS: %q must be a scheme and host such as https://example.com", origin))
+		}
+		if strings.Count(origin, "*") > 1 {
+			errs = append(errs, fmt.Errorf("CORS_ALLOWED_ORIGINS: %q may contain at most one *", origin))
+		}
+	}
+	if c.MaxAge < 0 {
+		errs = append(errs, errors.New("CORS_MAX_AGE must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// CORS answers preflight requests and sets the CORS response headers allowed by config
+func CORS(config CORSConfig) func(http.Handler) http.Handler {
+	origins := make([]string, len(config.AllowedOrigins))
+	for i, origin := range config.AllowedOrigins {
+		// Browsers send the Origin header without a trailing slash
+		origins[i] = strings.TrimSuffix(origin, "/")
+	}
+	options := cors.Options{
+		AllowedOrigins:   origins,
+		AllowedMethods:   config.AllowedMethods,
+		AllowedHeaders:   config.AllowedHeaders,
+		ExposedHeaders:   config.ExposedHeaders,
+		AllowCredentials: config.AllowCredentials,
+		MaxAge:           int(config.MaxAge.Seconds()),
+	}
+	if len(origins) == 0 {
+		// The cors package allows every origin when none are listed
+		options.AllowOriginFunc = func(*http.Request, string) bool { return false }
+	}
+	return cors.New(options).Handler
+}
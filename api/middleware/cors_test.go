@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Sends a preflight request from origin and returns the allowed origin header
+func preflight(config CORSConfig, origin string) string {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	req := httptest.NewRequest(http.MethodOptions, "/api/brothers", nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	rr := httptest.NewRecorder()
+	CORS(config)(ok).ServeHTTP(rr, req)
+	return rr.Header().Get("Access-Control-Allow-Origin")
+}
+
+func TestCORSAllowsConfiguredOrigins(t *testing.T) {
+	config := DefaultCORSConfig()
+	config.AllowedOrigins = []string{"http://localhost:5173/"}
+
+	if got := preflight(config, "http://localhost:5173"); got != "http://localhost:5173" {
+		t.Errorf("Expected configured origin to be allowed. Got %q", got)
+	}
+	if got := preflight(config, "https://evil.example.com"); got != "" {
+		t.Errorf("Expected other origins to be denied. Got %q", got)
+	}
+}
+
+func TestCORSWithoutOriginsDeniesAll(t *testing.T) {
+	if got := preflight(DefaultCORSConfig(), "http://localhost:5173"); got != "" {
+		t.Errorf("Expected all origins to be denied. Got %q", got)
+	}
+}
+
+func TestCORSConfigValidate(t *testing.T) {
+	tests := []struct {
+		origins     []string
+		credentials bool
+		valid       bool
+	}{
+		{[]string{"https://tt-db.example.com", "https://*.example.com"}, true, true},
+		{[]string{"*"}, false, true},
+		{[]string{"*"}, true, false},
+		{[]string{"localhost:5173"}, true, false},
+		{[]string{"https://example.com/app"}, true, false},
+	}
+	for _, test := range tests {
+		config := DefaultCORSConfig()
+		config.AllowedOrigins = test.origins
+		config.AllowCredentials = test.credentials
+		if err := config.Validate(); (err == nil) != test.valid {
+			t.Errorf("Validate(%v, credentials=%t): expected valid=%t. Got %v", test.origins, test.credentials, test.valid, err)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/middleware"
+	"github.com/pacific-theta-tau/tt-db/api/models"
+)
+
+// Settings for locking out clients after repeated failed logins
+type LockoutConfig struct {
+	// Failed attempts allowed within Window. 0 disables the lockout
+	MaxFailures int
+	// Period failed attempts are counted over
+	Window time.Duration
+	// How long a client is locked out
+	Duration time.Duration
+}
+
+// Returns the recommended lockout settings
+func DefaultLockoutConfig() LockoutConfig {
+	return LockoutConfig{
+		MaxFailures: 5,
+		Window:      15 * time.Minute,
+		Duration:    15 * time.Minute,
+	}
+}
+
+// Tracks failed logins per client
+type LoginGuard struct {
+	config     LockoutConfig
+	trustProxy bool
+
+	mu        sync.Mutex
+	clients   map[string]*loginAttempts
+	lastSweep time.Time
+}
+
+type loginAttempts struct {
+	failures    int
+	windowStart time.Time
+	lockedUntil time.Time
+}
+
+// Checks the settings can be used
+func (c LockoutConfig) Validate() error {
+	if c.MaxFailures < 0 || (c.MaxFailures > 0 && (c.Window <= 0 || c.Duration <= 0)) {
+		return errors.New("LOGIN_MAX_FAILURES must not be negative, and LOGIN_FAILURE_WINDOW and LOGIN_LOCKOUT_DURATION must be positive when it is set")
+	}
+	return nil
+}
+
+// Returns a guard for sign ins. Clients are identified like in RateLimit
+func NewLoginGuard(config LockoutConfig, trustProxy bool) *LoginGuard {
+	return &LoginGuard{config: config, trustProxy: trustProxy, clients: map[string]*loginAttempts{}}
+}
+
+// Returns how long key is still locked out, or 0
+func (g *LoginGuard) lockedFor(key string, now time.Time) time.Duration {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if attempts, ok := g.clients[key]; ok && now.Before(attempts.lockedUntil) {
+		return attempts.lockedUntil.Sub(now)
+	}
+	return 0
+}
+
+// Counts a failed login for key and locks it out once it reaches the limit. Returns true if it was locked out
+func (g *LoginGuard) fail(key string, now time.Time) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// Drop clients whose window and lockout have passed so the map stays small
+	if now.Sub(g.lastSweep) >= sweepInterval {
+		for k, attempts := range g.clients {
+			if now.Sub(attempts.windowStart) > g.config.Window && !now.Before(attempts.lockedUntil) {
+				delete(g.clients, k)
+			}
+		}
+		g.lastSweep = now
+	}
+
+	attempts, ok := g.clients[key]
+	if !ok || now.Sub(attempts.windowStart) > g.config.Window {
+		attempts = &loginAttempts{windowStart: now}
+		g.clients[key] = attempts
+	}
+	attempts.failures++
+	if attempts.failures < g.config.MaxFailures {
+		return false
+	}
+	attempts.failures = 0
+	attempts.windowStart = now
+	attempts.lockedUntil = now.Add(g.config.Duration)
+	return true
+}
+
+// Clears the failed logins of key after a successful login
+func (g *LoginGuard) succeed(key string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.clients, key)
+}
+
+// Lockout protects sign in from brute force. Mount it before Authenticate. A 401 response to a request
+// with credentials (an Authorization header) counts as a failed login, and a successful response to one
+// resets the count; requests without credentials are never counted. Once a client reaches the limit
+// within the window, its requests are rejected with a 429 and a Retry-After header until the lockout ends
+func Lockout(guard *LoginGuard) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if guard == nil || guard.config.MaxFailures == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := clientKey(r, guard.trustProxy)
+			if wait := guard.lockedFor(key, time.Now()); wait > 0 {
+				respondTooManyRequests(w, models.CodeLockedOut, "Too many failed login attempts. Try again later", wait)
+				return
+			}
+
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			if r.Header.Get("Authorization") == "" {
+				return
+			}
+			switch status := ww.Status(); {
+			case status == http.StatusUnauthorized:
+				if guard.fail(key, time.Now()) {
+					slog.WarnContext(r.Context(), "Client locked out after failed logins", "client", key, "duration", guard.config.Duration.String())
+				}
+			case status >= 200 && status < 300:
+				guard.succeed(key)
+			}
+		}
+		return http.HandlerFunc(fn)
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	chimiddleware "github.com/go-chi/chi/middleware"
+	"github.com/pacific-theta-tau/tt-db/metrics"
+)
+
+// Route label for requests that didn't match any route, so unknown paths can't create new series
+const unmatchedRoute = "unmatched"
+
+// Metrics records the count, latency and JSend status of every request, labelled with
+// the chi route pattern (e.g. /api/brothers/{id}) instead of the raw path
+func Metrics(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		route := unmatchedRoute
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		metrics.ObserveRequest(route, r.Method, status, time.Since(start))
+	}
+	return http.HandlerFunc(fn)
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/pacific-theta-tau/tt-db/api/models"
+	"github.com/pacific-theta-tau/tt-db/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// Requests are labelled with the route pattern, not the raw path
+func TestMetricsUsesRoutePattern(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(Metrics)
+	r.Get("/api/brothers/{id}", func(w http.ResponseWriter, r *http.Request) {
+		models.RespondWithFail(w, http.StatusNotFound, "Brother not found")
+	})
+
+	for _, path := range []string{"/api/brothers/1", "/api/brothers/2"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+
+	expected := `
+# HELP ttdb_jsend_responses_total API responses by route pattern and JSend status (success, fail or error).
+# TYPE ttdb_jsend_responses_total counter
+ttdb_jsend_responses_total{route="/api/brothers/{id}",status="fail"} 2
+`
+	err := testutil.GatherAndCompare(metrics.Registry, strings.NewReader(expected), "ttdb_jsend_responses_total")
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,199 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+	"golang.org/x/time/rate"
+)
+
+type clientIDKey struct{}
+
+// Returns a copy of ctx carrying the ID of the authenticated client (API key or user).
+// Authentication middleware sets it so rate limits follow the client instead of its IP
+func WithClientID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, clientIDKey{}, id)
+}
+
+// Returns the authenticated client ID stored in ctx, or "" for anonymous requests
+func ClientIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(clientIDKey{}).(string)
+	return id
+}
+
+// Returns the key requests are counted under: the authenticated client, or else the client IP.
+// Forwarding headers are only trusted behind a reverse proxy, since clients can set them freely
+func clientKey(r *http.Request, trustProxy bool) string {
+	if id := ClientIDFromContext(r.Context()); id != "" {
+		return "client:" + id
+	}
+	if trustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			ip, _, _ := strings.Cut(forwarded, ",")
+			return "ip:" + strings.TrimSpace(ip)
+		}
+		if ip := r.Header.Get("X-Real-IP"); ip != "" {
+			return "ip:" + ip
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// Token bucket size and refill rate for one route group
+type Limit struct {
+	// Tokens added per minute. 0 disables the limit
+	PerMinute int
+	// Requests allowed in a burst
+	Burst int
+}
+
+// Rate limit settings for each route group
+type RateLimitConfig struct {
+	Enabled bool
+	// Use X-Forwarded-For / X-Real-IP as the client IP. Only enable behind a reverse proxy that sets them
+	TrustProxy bool
+	// GET, HEAD and OPTIONS requests
+	Read Limit
+	// Requests that change data
+	Write Limit
+	// Long-running endpoints (e.g. duplicate detection and merges)
+	Long Limit
+}
+
+// Returns the rate limits used when none are configured
+func DefaultRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		Enabled: true,
+		Read:    Limit{PerMinute: 600, Burst: 60},
+		Write:   Limit{PerMinute: 120, Burst: 20},
+		Long:    Limit{PerMinute: 6, Burst: 2},
+	}
+}
+
+// Checks that every limit is usable
+func (c RateLimitConfig) Validate() error {
+	groups := []struct {
+		name  string
+		limit Limit
+	}{{"READ", c.Read}, {"WRITE", c.Write}, {"LONG", c.Long}}
+	for _, group := range groups {
+		limit := group.limit
+		if limit.PerMinute < 0 || limit.Burst < 0 || (limit.PerMinute > 0 && limit.Burst == 0) {
+			return fmt.Errorf("RATE_LIMIT_%s_PER_MINUTE and RATE_LIMIT_%s_BURST must not be negative, and the burst must be at least 1 when the limit is set", group.name, group.name)
+		}
+	}
+	return nil
+}
+
+// How often idle buckets are dropped
+const sweepInterval = time.Minute
+
+// Token buckets for one route group, one per client
+type RateLimiter struct {
+	name       string
+	limit      rate.Limit
+	burst      int
+	trustProxy bool
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	// Time a bucket takes to refill completely. Idle buckets older than this are equivalent to new ones
+	idleTTL time.Duration
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// Returns a limiter for the named route group, or nil when the limit is disabled
+func NewRateLimiter(name string, limit Limit, trustProxy bool) *RateLimiter {
+	if limit.PerMinute == 0 {
+		return nil
+	}
+	perSecond := float64(limit.PerMinute) / 60
+	return &RateLimiter{
+		name:       name,
+		limit:      rate.Limit(perSecond),
+		burst:      limit.Burst,
+		trustProxy: trustProxy,
+		buckets:    map[string]*bucket{},
+		idleTTL:    time.Duration(float64(limit.Burst) / perSecond * float64(time.Second)),
+	}
+}
+
+// Takes a token from the bucket of key. If none is left, returns false and the wait until the next one
+func (l *RateLimiter) allow(key string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) >= sweepInterval {
+		for k, b := range l.buckets {
+			if now.Sub(b.lastSeen) > l.idleTTL {
+				delete(l.buckets, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.buckets[key] = b
+	}
+	b.lastSeen = now
+
+	reservation := b.limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+// RateLimit rejects requests over the limit of their route group with a 429 and a Retry-After header.
+// Reads and writes are counted in separate buckets. A nil limiter lets every request through
+func RateLimit(reads, writes *RateLimiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			limiter := writes
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				limiter = reads
+			}
+			if limiter == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := clientKey(r, limiter.trustProxy)
+			if ok, wait := limiter.allow(key, time.Now()); !ok {
+				slog.WarnContext(r.Context(), "Rate limit exceeded", "group", limiter.name, "client", key)
+				respondTooManyRequests(w, models.CodeRateLimited, "Too many requests. Try again later", wait)
+				return
+			}
+			next.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// Sends a 429 JSend fail response telling the client how many seconds to wait
+func respondTooManyRequests(w http.ResponseWriter, code string, message string, wait time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	models.RespondWithFailCode(w, http.StatusTooManyRequests, code, message)
+}
@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+)
+
+// Sends a request from remoteAddr through handler and returns the recorded response
+func serve(handler http.Handler, method string, remoteAddr string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/api/brothers", nil)
+	req.RemoteAddr = remoteAddr
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	models.RespondWithSuccess(w, http.StatusOK, "ok")
+})
+
+func TestRateLimitRejectsAfterBurst(t *testing.T) {
+	limiter := NewRateLimiter("write", Limit{PerMinute: 1, Burst: 2}, false)
+	handler := RateLimit(nil, limiter)(okHandler)
+
+	for i := 0; i < 2; i++ {
+		if rr := serve(handler, http.MethodPost, "10.0.0.1:1234"); rr.Code != http.StatusOK {
+			t.Fatalf("Expected request %d within burst to pass. Got %d", i+1, rr.Code)
+		}
+	}
+
+	rr := serve(handler, http.MethodPost, "10.0.0.1:1234")
+	if rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected response code %d. Got %d", http.StatusTooManyRequests, rr.Code)
+	}
+	if retryAfter := rr.Header().Get("Retry-After"); retryAfter == "" || retryAfter == "0" {
+		t.Errorf("Expected a positive Retry-After header. Got %q", retryAfter)
+	}
+	var response models.APIResponse
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("Error decoding response: %v", err)
+	}
+	if response.Status != "fail" || response.Code != models.CodeRateLimited {
+		t.Errorf("Expected fail response with code %s. Got %+v", models.CodeRateLimited, response)
+	}
+
+	// Other clients and reads have their own buckets
+	if rr := serve(handler, http.MethodPost, "10.0.0.2:1234"); rr.Code != http.StatusOK {
+		t.Errorf("Expected another client to pass. Got %d", rr.Code)
+	}
+	if rr := serve(handler, http.MethodGet, "10.0.0.1:1234"); rr.Code != http.StatusOK {
+		t.Errorf("Expected reads to be unlimited. Got %d", rr.Code)
+	}
+}
+
+func TestClientKey(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.1")
+
+	if key := clientKey(req, false); key != "ip:10.0.0.1" {
+		t.Errorf("Expected forwarding headers to be ignored. Got %s", key)
+	}
+	if key := clientKey(req, true); key != "ip:203.0.113.7" {
+		t.Errorf("Expected first forwarded IP behind a proxy. Got %s", key)
+	}
+	req = req.WithContext(WithClientID(req.Context(), "api-key-1"))
+	if key := clientKey(req, true); key != "client:api-key-1" {
+		t.Errorf("Expected authenticated client ID. Got %s", key)
+	}
+}
+
+func TestLockoutAfterFailedLogins(t *testing.T) {
+	status := http.StatusUnauthorized
+	login := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		models.RespondWithFail(w, status, "Invalid credentials")
+	})
+	guard := NewLoginGuard(LockoutConfig{MaxFailures: 3, Window: time.Minute, Duration: time.Minute}, false)
+	handler := Lockout(guard)(login)
+	signIn := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/login", nil)
+		req.RemoteAddr = remoteAddr
+		req.SetBasicAuth("officer@example.com", "wrong")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// Requests without credentials aren't failed logins
+	for i := 0; i < 5; i++ {
+		serve(handler, http.MethodGet, "10.0.0.1:1234")
+	}
+	for i := 0; i < 3; i++ {
+		if rr := signIn("10.0.0.1:1234"); rr.Code != http.StatusUnauthorized {
+			t.Fatalf("Expected attempt %d to reach the handler. Got %d", i+1, rr.Code)
+		}
+	}
+
+	status = http.StatusOK
+	rr := signIn("10.0.0.1:1234")
+	if rr.Code != http.StatusTooManyRequests || rr.Header().Get("Retry-After") != "60" {
+		t.Fatalf("Expected locked out client to get 429 with Retry-After 60. Got %d %q", rr.Code, rr.Header().Get("Retry-After"))
+	}
+	if rr := serve(handler, http.MethodPost, "10.0.0.2:1234"); rr.Code != http.StatusOK {
+		t.Errorf("Expected other clients to log in. Got %d", rr.Code)
+	}
+}
+
+func TestLockoutResetsOnSuccess(t *testing.T) {
+	guard := NewLoginGuard(LockoutConfig{MaxFailures: 2, Window: time.Minute, Duration: time.Minute}, false)
+	now := time.Now()
+
+	guard.fail("ip:10.0.0.1", now)
+	guard.succeed("ip:10.0.0.1")
+	if guard.fail("ip:10.0.0.1", now) {
+		t.Error("Expected a successful login to reset the failure count")
+	}
+	if !guard.fail("ip:10.0.0.1", now) {
+		t.Error("Expected the client to be locked out after 2 failures")
+	}
+	if wait := guard.lockedFor("ip:10.0.0.1", now.Add(2*time.Minute)); wait != 0 {
+		t.Errorf("Expected the lockout to end. Got %s left", wait)
+	}
+}
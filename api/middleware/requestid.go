@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+
+	"github.com/pacific-theta-tau/tt-db/logging"
+)
+
+// Header used to receive and return request IDs
+const RequestIDHeader = "X-Request-ID"
+
+// Request IDs accepted from clients or proxies. Anything else is replaced with a generated ID
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._\-]{1,64}$`)
+
+// RequestID stores an ID for each request in its context, so log lines written with
+// the request context carry it, and returns it in the X-Request-ID header.
+// An X-Request-ID sent by the client or a proxy is reused when it is valid
+func RequestID(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), id)))
+	}
+	return http.HandlerFunc(fn)
+}
+
+// Generates a random 32 character hex ID
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
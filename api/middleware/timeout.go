@@ -0,0 +1,40 @@
+// Package middleware contains the API's HTTP middleware
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/middleware"
+	"github.com/pacific-theta-tau/tt-db/api/models"
+)
+
+// Timeout sets a deadline of d on the request context. Handlers that derive their
+// database calls from the request context are cancelled once it passes.
+// If the handler returns without writing a response because the deadline passed
+// (or the request was cancelled), a 504 (or 503) JSend error is sent instead.
+func Timeout(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			if ww.Status() != 0 || ctx.Err() == nil {
+				return
+			}
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				slog.WarnContext(r.Context(), "Request exceeded its deadline", "method", r.Method, "path", r.URL.Path, "timeout", d.String())
+				models.RespondWithError(w, http.StatusGatewayTimeout, "Request timed out")
+				return
+			}
+			models.RespondWithError(w, http.StatusServiceUnavailable, "Request was cancelled")
+		}
+		return http.HandlerFunc(fn)
+	}
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+)
+
+// A handler that waits on the request context and returns without writing is answered with a 504
+func TestTimeoutRespondsWithGatewayTimeout(t *testing.T) {
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	rr := httptest.NewRecorder()
+	Timeout(10*time.Millisecond)(slow).ServeHTTP(rr, httptest.NewRequest("GET", "/api/brothers", nil))
+
+	if rr.Code != http.StatusGatewayTimeout {
+		t.Fatalf("Expected response code %d. Got %d\n", http.StatusGatewayTimeout, rr.Code)
+	}
+	var response models.APIResponse
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("Error decoding response: %v", err)
+	}
+	if response.Status != "error" || response.Code != models.CodeTimeout {
+		t.Errorf("Expected error response with code %s. Got %+v\n", models.CodeTimeout, response)
+	}
+}
+
+// Responses written before the deadline are left untouched
+func TestTimeoutKeepsHandlerResponse(t *testing.T) {
+	fast := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		models.RespondWithSuccess(w, http.StatusOK, "ok")
+	})
+	rr := httptest.NewRecorder()
+	Timeout(time.Second)(fast).ServeHTTP(rr, httptest.NewRequest("GET", "/api/brothers", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected response code %d. Got %d\n", http.StatusOK, rr.Code)
+	}
+}
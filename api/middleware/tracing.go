@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing starts a server span for every request, continuing the trace from an incoming
+// W3C traceparent header. Once routed, the span is named after the chi route pattern
+// (e.g. "GET /api/events/{eventID}/attendance") so spans of the same endpoint group together.
+// The span context is stored in the request context, so database spans become its children
+func Tracing(next http.Handler) http.Handler {
+	nameSpan := func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+
+		rctx := chi.RouteContext(r.Context())
+		if rctx == nil || rctx.RoutePattern() == "" {
+			return
+		}
+		span := trace.SpanFromContext(r.Context())
+		span.SetName(r.Method + " " + rctx.RoutePattern())
+		span.SetAttributes(semconv.HTTPRoute(rctx.RoutePattern()))
+	}
+	return otelhttp.NewHandler(http.HandlerFunc(nameSpan), "http.request",
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return r.Method + " " + unmatchedRoute
+		}),
+	)
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+)
+
+type userKey struct{}
+
+// Returns a copy of ctx carrying the signed in user. Authenticate sets it; handlers use it
+// to attribute changes and to hide officers-only data from members. Rate limits then follow the user
+func WithUser(ctx context.Context, user models.User) context.Context {
+	ctx = WithClientID(ctx, "user:"+strconv.Itoa(user.UserID))
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// Returns the signed in user stored in ctx. ok is false for requests without one
+func UserFromContext(ctx context.Context) (user models.User, ok bool) {
+	user, ok = ctx.Value(userKey{}).(models.User)
+	return user, ok
+}
+
+// Reports whether the request may read officers-only data. Requests without a signed in user may not
+func CanSeeOfficerData(ctx context.Context) bool {
+	user, ok := UserFromContext(ctx)
+	return ok && (user.Role == models.RoleAdmin || user.Role == models.RoleOfficer)
+}
+
+// Reports whether the request may change settings that shape the database, like custom field definitions.
+// Requests without a signed in user may not
+func CanAdminister(ctx context.Context) bool {
+	user, ok := UserFromContext(ctx)
+	return ok && user.Role == models.RoleAdmin
+}
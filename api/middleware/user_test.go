@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+)
+
+func TestCanSeeOfficerData(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want bool
+	}{
+		{"no user", context.Background(), false},
+		{"admin", WithUser(context.Background(), models.User{UserID: 1, Role: models.RoleAdmin}), true},
+		{"officer", WithUser(context.Background(), models.User{UserID: 2, Role: models.RoleOfficer}), true},
+		{"member", WithUser(context.Background(), models.User{UserID: 3, Role: models.RoleMember}), false},
+	}
+	for _, tt := range tests {
+		if got := CanSeeOfficerData(tt.ctx); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestWithUserSetsClientKey(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(WithUser(r.Context(), models.User{UserID: 7, Email: "officer@example.com", Role: models.RoleOfficer}))
+
+	if user, ok := UserFromContext(r.Context()); !ok || user.Email != "officer@example.com" {
+		t.Errorf("Expected the user back from the context. Got %+v, %v", user, ok)
+	}
+	if key := clientKey(r, false); key != "client:user:7" {
+		t.Errorf("Expected rate limits to follow the user. Got %q", key)
+	}
+}
+
+func TestCanAdminister(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want bool
+	}{
+		{"no user", context.Background(), false},
+		{"admin", WithUser(context.Background(), models.User{UserID: 1, Role: models.RoleAdmin}), true},
+		{"officer", WithUser(context.Background(), models.User{UserID: 2, Role: models.RoleOfficer}), false},
+		{"member", WithUser(context.Background(), models.User{UserID: 3, Role: models.RoleMember}), false},
+	}
+	for _, tt := range tests {
+		if got := CanAdminister(tt.ctx); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
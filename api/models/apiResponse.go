@@ -7,15 +7,45 @@ import (
 	"net/http"
 )
 
+// Machine-readable error codes sent in the `code` field of fail and error responses
+const (
+    CodeBadRequest          = "BAD_REQUEST"
+    CodeInvalidBody         = "INVALID_BODY"
+    CodeInvalidParameter    = "INVALID_PARAMETER"
+    CodeValidationFailed    = "VALIDATION_FAILED"
+    CodeNotFound            = "NOT_FOUND"
+    CodeConflict            = "CONFLICT"
+    CodeInvalidReference    = "INVALID_REFERENCE"
+    CodeConstraintViolation = "CONSTRAINT_VIOLATION"
+    CodeInternal            = "INTERNAL_ERROR"
+    CodeUnavailable         = "SERVICE_UNAVAILABLE"
+    CodeTimeout             = "TIMEOUT"
+    CodeRateLimited         = "RATE_LIMITED"
+    CodeLockedOut           = "LOCKED_OUT"
+    CodeUnauthorized        = "UNAUTHORIZED"
+    CodeForbidden           = "FORBIDDEN"
+    CodeTooLarge            = "TOO_LARGE"
+    CodeUnsupportedType     = "UNSUPPORTED_TYPE"
+)
+
 
 // API response format in JSend notation. See: https://github.com/omniti-labs/jsend
 // @Description JSON response format for all API calls
 type APIResponse struct {
     Status      string `json:"status"`
     Message     string `json:"message,omitnil"` //Omit if nil
+    Code        string `json:"code,omitempty"` // Only set for fail and error responses
+    Errors      []FieldError `json:"errors,omitempty"` // Only set for validation failures
     Data        interface{} `json:"data,omitnil"` //Omit if nil
 }
 
+// @Description Validation error for a single request field
+type FieldError struct {
+    Field   string `json:"field"`
+    Rule    string `json:"rule"`
+    Message string `json:"message"`
+}
+
 // helper function to set headers and encode json
 func sendResponse(w http.ResponseWriter, statusCode int, response APIResponse) {
     w.Header().Set("Content-Type", "application/json")
@@ -25,6 +55,36 @@ func sendResponse(w http.ResponseWriter, statusCode int, response APIResponse) {
     }
 }
 
+// Default error code for a HTTP status code, used when handlers don't provide a more specific one
+func codeForStatus(statusCode int) string {
+    switch statusCode {
+    case http.StatusUnauthorized:
+        return CodeUnauthorized
+    case http.StatusForbidden:
+        return CodeForbidden
+    case http.StatusNotFound:
+        return CodeNotFound
+    case http.StatusConflict:
+        return CodeConflict
+    case http.StatusRequestEntityTooLarge:
+        return CodeTooLarge
+    case http.StatusUnsupportedMediaType:
+        return CodeUnsupportedType
+    case http.StatusUnprocessableEntity:
+        return CodeConstraintViolation
+    case http.StatusTooManyRequests:
+        return CodeRateLimited
+    case http.StatusServiceUnavailable:
+        return CodeUnavailable
+    case http.StatusGatewayTimeout:
+        return CodeTimeout
+    }
+    if statusCode >= 500 {
+        return CodeInternal
+    }
+    return CodeBadRequest
+}
+
 // 200~ status codes
 func RespondWithSuccess(w http.ResponseWriter, statusCode int, data interface{}) {
     response := APIResponse{
@@ -34,20 +94,55 @@ func RespondWithSuccess(w http.ResponseWriter, statusCode int, data interface{})
     sendResponse(w, statusCode, response)
 }
 
-// 500~ status codes (Server error)
+// 201 status code. Sets the Location header to the URI of the created resource
+func RespondWithCreated(w http.ResponseWriter, location string, data interface{}) {
+    w.Header().Set("Location", location)
+    RespondWithSuccess(w, http.StatusCreated, data)
+}
+
+// 500~ status codes (Server error). The message is sent to clients, so it must not contain internal details
 func RespondWithError(w http.ResponseWriter, statusCode int, message string) {
     response := APIResponse{
         Status:  "error",
         Message: message,
+        Code:    codeForStatus(statusCode),
+    }
+    sendResponse(w, statusCode, response)
+}
+
+// 500~ status codes (Server error) with data describing the error, e.g. failed readiness checks
+func RespondWithErrorData(w http.ResponseWriter, statusCode int, message string, data interface{}) {
+    response := APIResponse{
+        Status:  "error",
+        Message: message,
+        Code:    codeForStatus(statusCode),
+        Data:    data,
     }
     sendResponse(w, statusCode, response)
 }
 
 // 400~ status codes (Client error)
 func RespondWithFail(w http.ResponseWriter, statusCode int, message string) {
+    RespondWithFailCode(w, statusCode, codeForStatus(statusCode), message)
+}
+
+// 400~ status codes (Client error) with an explicit error code
+func RespondWithFailCode(w http.ResponseWriter, statusCode int, code string, message string) {
     response := APIResponse{
         Status: "fail",
         Message: message,
+        Code: code,
     }
     sendResponse(w, statusCode, response)
 }
+
+// 400 status code for requests with invalid fields
+func RespondWithValidationErrors(w http.ResponseWriter, fieldErrors []FieldError) {
+    response := APIResponse{
+        Status: "fail",
+        Message: "Invalid request parameters",
+        Code: CodeValidationFailed,
+        Errors: fieldErrors,
+    }
+    sendResponse(w, http.StatusBadRequest, response)
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// @Description File attached to a Brother or an Event. Download its contents from the attachment's URL
+type Attachment struct {
+	AttachmentID int    `json:"attachmentID"`
+	BrotherID    *int   `json:"brotherID,omitempty"`
+	EventID      *int   `json:"eventID,omitempty"`
+	FileName     string `json:"fileName"`
+	// Detected from the file's contents, not the name or the upload's headers
+	ContentType string    `json:"contentType"`
+	Size        int64     `json:"size"`
+	UploadedBy  string    `json:"uploadedBy"`
+	CreatedAt   time.Time `json:"createdAt"`
+	// Key of the contents in the blob store
+	BlobKey string `json:"-" swaggerignore:"true"`
+}
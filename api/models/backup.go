@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// @Description Describes a backup archive: its format, the schema it was taken from and the rows per table
+type BackupManifest struct {
+	// Always "tt-db-backup"
+	Format string `json:"format"`
+	// Version of the archive layout. Restores reject versions they don't know
+	FormatVersion int `json:"formatVersion"`
+	// Database schema version (latest applied migration) the data was exported from
+	SchemaVersion int       `json:"schemaVersion"`
+	CreatedAt     time.Time `json:"createdAt"`
+	// Number of rows exported per table
+	Tables map[string]int `json:"tables"`
+}
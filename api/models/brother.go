@@ -8,8 +8,12 @@ type Brother struct {
 	LastName    string `json:"lastName" validate:"required"`
 	Major       string `json:"major" validate:"required"`
 	Status      string `json:"status" validate:"required"`
+	// Name of the pledge class. Set classID instead; an unknown name creates the class
 	Class       string `json:"className"`
+	ClassID     *int   `json:"classID"`
 	Email       string `json:"email"`
 	PhoneNumber string `json:"phoneNumber"`
 	BadStanding int    `json:"badStanding"`
+	// Values of custom fields, keyed by field name
+	CustomFields map[string]interface{} `json:"customFields"`
 }
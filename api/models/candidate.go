@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// Stages of a rush candidate
+const (
+	StageInterested  = "interested"
+	StageAttended    = "attended"
+	StageBidExtended = "bid_extended"
+	StageAccepted    = "accepted"
+	StagePledged     = "pledged"
+)
+
+// Valid values for Candidate.Stage, in pipeline order
+var CandidateStages = []string{StageInterested, StageAttended, StageBidExtended, StageAccepted, StagePledged}
+
+// @Description Prospective member during rush. BrotherID is set once the candidate is converted to a Brother
+type Candidate struct {
+	CandidateID int    `json:"candidateID"`
+	FirstName   string `json:"firstName" validate:"required,max=50"`
+	LastName    string `json:"lastName" validate:"required,max=50"`
+	Email       string `json:"email" validate:"omitempty,email,max=255"`
+	PhoneNumber string `json:"phoneNumber" validate:"max=30"`
+	Major       string `json:"major" validate:"max=100"`
+	// Rush semester, e.g. "Fall 2024"
+	Semester  string    `json:"semester"`
+	Stage     string    `json:"stage" validate:"omitempty,oneof=interested attended bid_extended accepted pledged" enums:"interested,attended,bid_extended,accepted,pledged"`
+	BrotherID *int      `json:"brotherID"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// @Description Attendance of a candidate at a rush event
+type CandidateAttendance struct {
+	CandidateID      int       `json:"candidateID"`
+	EventID          int       `json:"eventID"`
+	AttendanceStatus string    `json:"attendanceStatus"`
+	FirstName        string    `json:"firstName"`
+	LastName         string    `json:"lastName"`
+	EventName        string    `json:"eventName"`
+	EventDate        time.Time `json:"eventDate"`
+}
+
+// @Description Number of candidates at a stage
+type StageCount struct {
+	Stage      string `json:"stage"`
+	Candidates int    `json:"candidates"`
+}
+
+// @Description Values for the Brother created from a candidate. Empty values default to the candidate's
+type CandidateConversion struct {
+	RollCall int `json:"rollCall" validate:"required"`
+	// Status in Semester (default: Active)
+	Status string `json:"status"`
+	// First semester as a Brother (default: the candidate's rush semester)
+	Semester     string                 `json:"semester"`
+	Major        string                 `json:"major"`
+	ClassName    string                 `json:"className"`
+	ClassID      *int                   `json:"classID"`
+	CustomFields map[string]interface{} `json:"customFields"`
+}
+
+// @Description Outcome of converting a candidate: the updated candidate, the new Brother, their first status and the number of rush attendance records linked to them
+type ConvertedCandidate struct {
+	Candidate        Candidate    `json:"candidate"`
+	Brother          Brother      `json:"brother"`
+	Status           StatusRecord `json:"status"`
+	AttendanceLinked int          `json:"attendanceLinked"`
+}
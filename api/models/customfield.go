@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Types of custom fields
+const (
+	CustomFieldText   = "text"
+	CustomFieldNumber = "number"
+	// Calendar date written as YYYY-MM-DD
+	CustomFieldDate = "date"
+	// One of the field's options
+	CustomFieldEnum = "enum"
+)
+
+// @Description Field defined by admins to track extra data on Brothers. Values are in each Brother's customFields, keyed by name
+type CustomField struct {
+	FieldID int `json:"fieldID"`
+	// Key of the values in customFields. Letters, digits and underscores, starting with a letter
+	Name  string `json:"name" validate:"required,max=50"`
+	Label string `json:"label" validate:"max=100"`
+	Type  string `json:"type" validate:"required,oneof=text number date enum" enums:"text,number,date,enum"`
+	// Allowed values of enum fields
+	Options   []string  `json:"options" validate:"required_if=Type enum,unique,dive,required,max=100"`
+	Required  bool      `json:"required"`
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import "database/sql"
+
+//  @Description Statistics of the database connection pool
+type PoolStats struct {
+    MaxOpenConnections  int     `json:"maxOpenConnections"`
+    OpenConnections     int     `json:"openConnections"`
+    InUse               int     `json:"inUse"`
+    Idle                int     `json:"idle"`
+    WaitCount           int64   `json:"waitCount"`
+    WaitDurationMs      int64   `json:"waitDurationMs"`
+    MaxIdleClosed       int64   `json:"maxIdleClosed"`
+    MaxIdleTimeClosed   int64   `json:"maxIdleTimeClosed"`
+    MaxLifetimeClosed   int64   `json:"maxLifetimeClosed"`
+}
+
+// Helper function to create PoolStats from database/sql pool statistics
+func NewPoolStats(stats sql.DBStats) PoolStats {
+    return PoolStats{
+        MaxOpenConnections: stats.MaxOpenConnections,
+        OpenConnections:    stats.OpenConnections,
+        InUse:              stats.InUse,
+        Idle:               stats.Idle,
+        WaitCount:          stats.WaitCount,
+        WaitDurationMs:     stats.WaitDuration.Milliseconds(),
+        MaxIdleClosed:      stats.MaxIdleClosed,
+        MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
+        MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+    }
+}
@@ -0,0 +1,23 @@
+package models
+
+//  @Description Result of a single readiness check
+type HealthCheck struct {
+    Name    string `json:"name"`
+    OK      bool   `json:"ok"`
+    Message string `json:"message,omitempty"`
+}
+
+//  @Description Readiness of the API to serve traffic
+type Readiness struct {
+    Ready   bool          `json:"ready"`
+    Checks  []HealthCheck `json:"checks"`
+}
+
+//  @Description Build information of the running API
+type VersionInfo struct {
+    Commit          string `json:"commit"`
+    BuildTime       string `json:"buildTime"`
+    GoVersion       string `json:"goVersion"`
+    SchemaVersion   int    `json:"schemaVersion"`
+    LatestSchema    int    `json:"latestSchemaVersion"`
+}
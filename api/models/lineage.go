@@ -0,0 +1,40 @@
+package models
+
+// @Description Big/little link between two Brothers
+type BigLink struct {
+	LittleID     int `json:"littleID"`
+	BigBrotherID int `json:"bigBrotherID" validate:"required"`
+}
+
+// @Description Brother in a lineage, with their big if they have one
+type LineageBrother struct {
+	BrotherID    int    `json:"brotherID"`
+	RollCall     int    `json:"rollCall"`
+	FirstName    string `json:"firstName"`
+	LastName     string `json:"lastName"`
+	Status       string `json:"status"`
+	BigBrotherID *int   `json:"bigBrotherID"`
+	// Generations away from the Brother whose ancestors or descendants were requested: 1 for their big or littles
+	Generation int `json:"generation,omitempty"`
+}
+
+// @Description Brother in a family tree with their littles
+type FamilyTreeNode struct {
+	BrotherID int              `json:"brotherID"`
+	RollCall  int              `json:"rollCall"`
+	FirstName string           `json:"firstName"`
+	LastName  string           `json:"lastName"`
+	Status    string           `json:"status"`
+	Littles   []FamilyTreeNode `json:"littles"`
+}
+
+// @Description Size of a family, named after its founder: the Brother at its root, who has no big
+type Family struct {
+	FounderID   int    `json:"founderID"`
+	RollCall    int    `json:"rollCall"`
+	FirstName   string `json:"firstName"`
+	LastName    string `json:"lastName"`
+	Size        int    `json:"size"`
+	Active      int    `json:"active"`
+	Generations int    `json:"generations"`
+}
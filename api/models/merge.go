@@ -0,0 +1,32 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Reasons two brother records are considered likely duplicates
+const (
+	DuplicateSameName    = "same_name"
+	DuplicateSameEmail   = "same_email"
+	DuplicateSamePhone   = "same_phone"
+	DuplicateSimilarName = "similar_name"
+)
+
+// @Description Pair of Brother records that likely belong to the same person
+type DuplicateCandidate struct {
+	Brothers []Brother `json:"brothers"`
+	Reasons  []string  `json:"reasons"`
+}
+
+// @Description Record of a duplicate Brother merged into a surviving Brother
+type BrotherMerge struct {
+	MergeID           int             `json:"mergeID"`
+	SurvivorID        int             `json:"survivorID"`
+	MergedBrotherID   int             `json:"mergedBrotherID"`
+	MergedBrother     json.RawMessage `json:"mergedBrother" swaggertype:"object"`
+	AttendanceMoved   int             `json:"attendanceMoved"`
+	StatusesMoved     int             `json:"statusesMoved"`
+	ConflictsResolved int             `json:"conflictsResolved"`
+	MergedAt          time.Time       `json:"mergedAt"`
+}
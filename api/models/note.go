@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Who can read a note
+const (
+	NoteVisibilityOfficers = "officers"
+	NoteVisibilityAll      = "all"
+)
+
+// @Description Note kept on a Brother's record. Officers-only notes are hidden from members
+type Note struct {
+	NoteID     int       `json:"noteID"`
+	BrotherID  int       `json:"brotherID"`
+	Body       string    `json:"body"`
+	Visibility string    `json:"visibility" enums:"officers,all"`
+	Author     string    `json:"author"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedBy  string    `json:"updatedBy"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// @Description Version of a Note, written by EditedBy at EditedAt
+type NoteRevision struct {
+	Body       string    `json:"body"`
+	Visibility string    `json:"visibility" enums:"officers,all"`
+	EditedBy   string    `json:"editedBy"`
+	EditedAt   time.Time `json:"editedAt"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// @Description Pledge class, named with Greek letters (e.g. "Omicron" or "Alpha Beta"). Members' roll calls must be between FirstRollCall and LastRollCall, if set
+type PledgeClass struct {
+	ClassID int    `json:"classID"`
+	Name    string `json:"name" validate:"required,max=50"`
+	// Semester the class pledged, e.g. "Fall 2024". Empty if unknown
+	Semester string `json:"semester"`
+	// YYYY-MM-DD, empty if unknown
+	InitiationDate string    `json:"initiationDate" validate:"omitempty,datetime=2006-01-02"`
+	FirstRollCall  *int      `json:"firstRollCall" validate:"omitempty,min=1"`
+	LastRollCall   *int      `json:"lastRollCall" validate:"omitempty,min=1"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// @Description Members of a pledge class by status
+type ClassStats struct {
+	Size int `json:"size"`
+	// Number of members per status, e.g. {"Active": 5, "Alumnus": 2}
+	Statuses    map[string]int `json:"statuses"`
+	BadStanding int            `json:"badStanding"`
+	// Share of the members' attendance records that are Present, 0 without records
+	AttendanceRate float64 `json:"attendanceRate"`
+}
+
+// @Description Pledge class with its members and stats
+type ClassRoster struct {
+	Class    PledgeClass `json:"class"`
+	Stats    ClassStats  `json:"stats"`
+	Brothers []Brother   `json:"brothers"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Kinds of positions
+const (
+	PositionOffice    = "office"
+	PositionCommittee = "committee"
+)
+
+// @Description Chapter office (Regent, Scribe...) or committee (Rush, Philanthropy...) that Brothers hold for a range of semesters
+type Position struct {
+	PositionID int    `json:"positionID"`
+	Name       string `json:"name" validate:"required,max=100"`
+	Kind       string `json:"kind" validate:"required,oneof=office committee" enums:"office,committee"`
+	// User role `ttdb sync-roles` gives holders while they serve. Empty grants none
+	Role      string    `json:"role" validate:"omitempty,oneof=admin officer" enums:"admin,officer"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// @Description Brother holding a Position from StartSemester to EndSemester, both included
+type PositionTerm struct {
+	TermID       int    `json:"termID"`
+	PositionID   int    `json:"positionID"`
+	PositionName string `json:"positionName"`
+	Kind         string `json:"kind" enums:"office,committee"`
+	BrotherID    int    `json:"brotherID"`
+	RollCall     int    `json:"rollCall"`
+	FirstName    string `json:"firstName"`
+	LastName     string `json:"lastName"`
+	// Role within the position, e.g. "Chair" of a committee
+	Title         string `json:"title"`
+	StartSemester string `json:"startSemester"`
+	// Empty while the term hasn't ended
+	EndSemester string `json:"endSemester"`
+}
@@ -52,3 +52,12 @@ func CreateBrotherStatusFromRow(row *sql.Rows) (BrotherStatus, error) {
 
     return brotherStatus, err
 }
+
+
+//  @Description Status record of a Brother for a single semester
+type StatusRecord struct {
+    BrotherID       int    `json:"brotherID"`
+    SemesterID      int    `json:"semesterID"`
+    SemesterLabel   string `json:"semesterLabel"`
+    Status          string `json:"status"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// @Description Label grouping Brothers and Events, e.g. "Rush Committee" or "Mandatory". Names are unique regardless of case
+type Tag struct {
+	TagID     int       `json:"tagID"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// @Description Number of Brothers and Events with a Tag
+type TagCount struct {
+	TagID    int    `json:"tagID"`
+	Name     string `json:"name"`
+	Brothers int    `json:"brothers"`
+	Events   int    `json:"events"`
+}
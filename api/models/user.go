@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Roles a user can have, from most to least privileged
+const (
+	RoleAdmin   = "admin"
+	RoleOfficer = "officer"
+	RoleMember  = "member"
+)
+
+// Valid values for User.Role
+var Roles = []string{RoleAdmin, RoleOfficer, RoleMember}
+
+// Reports whether role is more privileged than other. Unknown and empty roles are the least privileged
+func MorePrivileged(role string, other string) bool {
+	rank := func(r string) int {
+		for i, known := range Roles {
+			if r == known {
+				return i
+			}
+		}
+		return len(Roles)
+	}
+	return rank(role) < rank(other)
+}
+
+// @Description Account of a person who administers the database. The password hash is never returned
+type User struct {
+	UserID    int       `json:"userID"`
+	Email     string    `json:"email" validate:"required,email"`
+	Role      string    `json:"role" validate:"required,oneof=admin officer member"`
+	CreatedAt time.Time `json:"createdAt"`
+}
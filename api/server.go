@@ -2,51 +2,180 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/go-chi/chi"
-	"github.com/go-chi/chi/middleware"
-    "github.com/go-chi/cors"
 	"github.com/pacific-theta-tau/tt-db/api/handlers"
+	apimiddleware "github.com/pacific-theta-tau/tt-db/api/middleware"
+	"github.com/pacific-theta-tau/tt-db/blob"
 	"github.com/pacific-theta-tau/tt-db/db"
+	"github.com/pacific-theta-tau/tt-db/metrics"
     _ "github.com/pacific-theta-tau/tt-db/docs" // docs is generated by Swag CLI, you have to import it.
     "github.com/swaggo/http-swagger" // http-swagger middleware
 )
 
-// TODO: Create a Pgpool instead using dependency injection
 type Application struct {
 	Database    *db.PostgresDB
 	DatabaseURL string
 	Port        string
+	// Apply pending schema migrations before serving
+	AutoMigrate bool
+	Timeouts    RouteTimeouts
+	Server      ServerConfig
+	CORS        apimiddleware.CORSConfig
+	RateLimits  apimiddleware.RateLimitConfig
+	// Lockout of clients after failed sign ins
+	Lockout apimiddleware.LockoutConfig
+	// External base URL of the API, e.g. https://api.example.com. Empty serves Swagger with relative URLs
+	PublicURL string
+	// Where attachment contents are stored, and which uploads are accepted
+	Blob        blob.Config
+	Attachments blob.AttachmentConfig
+}
+
+// Settings for the HTTP server
+type ServerConfig struct {
+	// Time allowed to read request headers
+	ReadHeaderTimeout time.Duration
+	// Time allowed to read the whole request, including the body
+	ReadTimeout time.Duration
+	// Time allowed to write the response. Must be longer than the longest route timeout
+	WriteTimeout time.Duration
+	// Time keep-alive connections may stay idle
+	IdleTimeout time.Duration
+	// Time in-flight requests are given to finish on shutdown
+	ShutdownTimeout time.Duration
+	// Serve HTTPS when both paths are set, for deployments without a reverse proxy
+	TLSCertFile string
+	TLSKeyFile  string
+}
+
+// Returns the HTTP server settings used when none are configured
+func DefaultServerConfig() ServerConfig {
+	return ServerConfig{
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       15 * time.Second,
+		WriteTimeout:      40 * time.Second,
+		IdleTimeout:       60 * time.Second,
+		ShutdownTimeout:   30 * time.Second,
+	}
+}
+
+// Deadlines for API requests. Database calls made by a request are cancelled once its deadline passes
+type RouteTimeouts struct {
+	// Most endpoints
+	Default time.Duration
+	// Endpoints that scan or rewrite many rows (e.g. duplicate detection and merges)
+	Long time.Duration
+}
+
+// Returns the request deadlines used when none are configured
+func DefaultRouteTimeouts() RouteTimeouts {
+	return RouteTimeouts{
+		Default: 5 * time.Second,
+		Long:    30 * time.Second,
+	}
 }
 
 // Constructor for Application struct
 func NewApplication(db *db.PostgresDB, port string) *Application {
 	return &Application{
-		Database: db,
-		Port:     port,
+		Database:    db,
+		Port:        port,
+		Timeouts:    DefaultRouteTimeouts(),
+		Server:      DefaultServerConfig(),
+		CORS:        apimiddleware.DefaultCORSConfig(),
+		RateLimits:  apimiddleware.DefaultRateLimitConfig(),
+		Lockout:     apimiddleware.DefaultLockoutConfig(),
+		Blob:        blob.DefaultConfig(),
+		Attachments: blob.DefaultAttachmentConfig(),
 	}
 }
 
-// Connect to database, start routers, and serve app
+// URL the Swagger UI loads the API definition from
+func (app *Application) swaggerURL() string {
+	return strings.TrimSuffix(app.PublicURL, "/") + "/swagger/doc.json"
+}
+
+// Connect to database, start routers, and serve app until SIGINT or SIGTERM.
+// On shutdown, in-flight requests are drained before the database pool is closed
 func (app *Application) Serve() {
     log.Println("-- Application.Serve() --")
 	// Connect to database
-	app.Database.Connect()
+	if err := app.Database.Connect(); err != nil {
+		log.Fatalf("Unable to connect to database: %v", err)
+	}
+	if app.AutoMigrate {
+		if err := app.Database.Migrate(context.Background()); err != nil {
+			log.Fatalf("Error while migrating database: %v", err)
+		}
+	}
+
+	if err := metrics.RegisterDBStats(app.Database.Conn); err != nil {
+		log.Printf("Unable to register database pool metrics: %v", err)
+	}
 
 	// Start routers and middleware
 	handler := handlers.NewHandler(app.Database.Conn)
-	routes := setupRoutes(handler)
+	blobs, err := blob.Open(context.Background(), app.Blob)
+	if err != nil {
+		log.Fatalf("Unable to open attachment storage: %v", err)
+	}
+	handler.UseAttachments(blobs, app.Attachments)
+	routes := setupRoutes(handler, app)
 
-	//TODO: cleaner address
 	addr := fmt.Sprint(":", app.Port)
-	log.Printf("App address: %s", addr)
-	err := http.ListenAndServe(addr, routes)
-	if err != nil {
-        log.Fatalf("Error while serving application: %v", err)
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           routes,
+		ReadHeaderTimeout: app.Server.ReadHeaderTimeout,
+		ReadTimeout:       app.Server.ReadTimeout,
+		WriteTimeout:      app.Server.WriteTimeout,
+		IdleTimeout:       app.Server.IdleTimeout,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Printf("App address: %s", addr)
+		if app.Server.TLSCertFile != "" && app.Server.TLSKeyFile != "" {
+			log.Println("Serving with TLS")
+			serveErr <- server.ListenAndServeTLS(app.Server.TLSCertFile, app.Server.TLSKeyFile)
+		} else {
+			serveErr <- server.ListenAndServe()
+		}
+	}()
+
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Error while serving application: %v", err)
+		}
+	case <-ctx.Done():
+		stop()
+		log.Printf("Shutting down, waiting up to %s for in-flight requests...", app.Server.ShutdownTimeout)
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), app.Server.ShutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Error while shutting down server: %v", err)
+		}
+	}
+
+	if err := app.Database.Close(); err != nil {
+		log.Printf("Error while closing database: %v", err)
 	}
+	log.Println("Server stopped")
 }
 
 
@@ -64,74 +193,183 @@ func (app *Application) Serve() {
 
 //	@host		petstore.swagger.io
 //	@BasePath	/api
-func setupRoutes(handler *handlers.Handler) *chi.Mux {
+
+//	@securityDefinitions.basic	BasicAuth
+func setupRoutes(handler *handlers.Handler, app *Application) *chi.Mux {
     log.Println("Setting up routes...")
 	r := chi.NewRouter()
 
     // Setup Middleware
-    // TODO: look into slog for structured logging
-	r.Use(middleware.Logger)
-    corsHandler := cors.New(cors.Options{
-        AllowedOrigins:   []string{"*"},     // Allow all origins
-        AllowedMethods:   []string{"GET", "PATCH", "POST", "PUT", "DELETE", "OPTIONS"},
-        AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-        ExposedHeaders:   []string{"Link"},
-        AllowCredentials: true,
-        MaxAge:           300, // Maximum value not ignored by any of major browsers
-    })
-    r.Use(corsHandler.Handler)
+	r.Use(apimiddleware.Tracing)
+	r.Use(apimiddleware.RequestID)
+	r.Use(apimiddleware.Logger)
+	r.Use(apimiddleware.Metrics)
+	r.Use(apimiddleware.CORS(app.CORS))
+	// Sign in requests with Basic credentials. Officers-only data is denied to requests without a user.
+	// Clients are locked out after repeated failed sign ins, identified by IP like in rate limits
+	r.Use(apimiddleware.Lockout(apimiddleware.NewLoginGuard(app.Lockout, app.RateLimits.TrustProxy)))
+	r.Use(apimiddleware.Authenticate(handler.LookupUser))
+
+    // Token buckets per route group and client. Nil limiters let every request through
+    var reads, writes, long *apimiddleware.RateLimiter
+    if app.RateLimits.Enabled {
+        reads = apimiddleware.NewRateLimiter("read", app.RateLimits.Read, app.RateLimits.TrustProxy)
+        writes = apimiddleware.NewRateLimiter("write", app.RateLimits.Write, app.RateLimits.TrustProxy)
+        long = apimiddleware.NewRateLimiter("long", app.RateLimits.Long, app.RateLimits.TrustProxy)
+    }
+
+    // API endpoints run with a request deadline and rate limit. Long-running ones get their own
+    apiRoutes := r.With(apimiddleware.Timeout(app.Timeouts.Default), apimiddleware.RateLimit(reads, writes))
+    longRoutes := r.With(apimiddleware.Timeout(app.Timeouts.Long), apimiddleware.RateLimit(long, long))
+    // Uploads and downloads stream file contents, so they get the long deadline but the usual rate limits
+    fileRoutes := r.With(apimiddleware.Timeout(app.Timeouts.Long), apimiddleware.RateLimit(reads, writes))
 
     // Endpoints
     r.Get("/swagger/*", httpSwagger.Handler(
-		httpSwagger.URL("http://localhost:8080/swagger/doc.json"), //The url pointing to API definition
+		httpSwagger.URL(app.swaggerURL()), //The url pointing to API definition
 	))
 	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte("Hello World!"))
 	})
 
+	// probes
+	r.Get("/healthz", handler.Healthz)
+	r.Get("/readyz", handler.Readyz)
+	r.Get("/version", handler.Version)
+	r.Method(http.MethodGet, "/metrics", metrics.Handler())
+
+    // auth endpoints
+    apiRoutes.Post("/api/login", handler.Login)
+
 	// brothers endpoint
-	r.Get("/api/brothers", handler.GetAllBrothers)
+	apiRoutes.Get("/api/brothers", handler.GetAllBrothers)
 	//r.Get("/api/brothers/{rollCall}", handler.GetBrotherByRollCall)
-	r.Get("/api/brothers/{id}", handler.GetBrotherByID)
-	r.Post("/api/brothers", handler.AddBrother)
-	r.Patch("/api/brothers/{id}", handler.UpdateBrother)
-	r.Delete("/api/brothers", handler.RemoveBrother)
+	apiRoutes.Get("/api/brothers/{id}", handler.GetBrotherByID)
+	apiRoutes.Post("/api/brothers", handler.AddBrother)
+	apiRoutes.Patch("/api/brothers/{id}", handler.UpdateBrother)
+	apiRoutes.Delete("/api/brothers", handler.RemoveBrother)
     // brothers count
-	r.Get("/api/brothers/count", handler.GetBrothersCount)
-	r.Get("/api/brothers/majors/count", handler.GetBrothersMajorsCount)
-    r.Get("/api/brothers/statuses", handler.GetAllBrotherStatuses)
-	r.Get("/api/brothers/statuses/count", handler.GetBrotherStatusCount)
+	apiRoutes.Get("/api/brothers/count", handler.GetBrothersCount)
+	longRoutes.Get("/api/brothers/duplicates", handler.GetDuplicateBrothers)
+	longRoutes.Post("/api/brothers/merge", handler.MergeBrothers)
+	apiRoutes.Get("/api/brothers/majors/count", handler.GetBrothersMajorsCount)
+    apiRoutes.Get("/api/brothers/statuses", handler.GetAllBrotherStatuses)
+	apiRoutes.Get("/api/brothers/statuses/count", handler.GetBrotherStatusCount)
 
     // brotherStatus endpoints
-    // r.Get("/api/statuses", handler.GetAllBrotherStatuses)
-    r.Get("/api/statuses", handler.GetAllStatusLabels)
-    r.Get("/api/brothers/{id}/statuses", handler.GetBrotherStatusHistory)
-    r.Post("/api/brothers/{id}/statuses", handler.CreateBrotherStatus)
-    r.Patch("/api/brothers/{id}/statuses", handler.UpdateBrotherStatusByBrotherID)
-    r.Delete("/v1/brothers/{brotherID}/statuses/{semesterID}", handler.DeleteStatusByMemberAndSemesterHandler)
+    // apiRoutes.Get("/api/statuses", handler.GetAllBrotherStatuses)
+    apiRoutes.Get("/api/statuses", handler.GetAllStatusLabels)
+    apiRoutes.Get("/api/brothers/{id}/statuses", handler.GetBrotherStatusHistory)
+    apiRoutes.Post("/api/brothers/{id}/statuses", handler.CreateBrotherStatus)
+    apiRoutes.Patch("/api/brothers/{id}/statuses", handler.UpdateBrotherStatusByBrotherID)
+    apiRoutes.Delete("/v1/brothers/{brotherID}/statuses/{semesterID}", handler.DeleteStatusByMemberAndSemesterHandler)
+
+    // brother notes endpoints
+    apiRoutes.Get("/api/brothers/{id}/notes", handler.GetBrotherNotes)
+    apiRoutes.Post("/api/brothers/{id}/notes", handler.CreateBrotherNote)
+    apiRoutes.Patch("/api/brothers/{id}/notes/{noteID}", handler.UpdateBrotherNote)
+    apiRoutes.Delete("/api/brothers/{id}/notes/{noteID}", handler.DeleteBrotherNote)
+    apiRoutes.Get("/api/brothers/{id}/notes/{noteID}/history", handler.GetBrotherNoteHistory)
+
+    // attachment endpoints
+    apiRoutes.Get("/api/brothers/{id}/attachments", handler.GetBrotherAttachments)
+    fileRoutes.Post("/api/brothers/{id}/attachments", handler.UploadBrotherAttachment)
+    fileRoutes.Get("/api/brothers/{id}/attachments/{attachmentID}", handler.DownloadBrotherAttachment)
+    apiRoutes.Delete("/api/brothers/{id}/attachments/{attachmentID}", handler.DeleteBrotherAttachment)
+    apiRoutes.Get("/api/events/{eventID}/attachments", handler.GetEventAttachments)
+    fileRoutes.Post("/api/events/{eventID}/attachments", handler.UploadEventAttachment)
+    fileRoutes.Get("/api/events/{eventID}/attachments/{attachmentID}", handler.DownloadEventAttachment)
+    apiRoutes.Delete("/api/events/{eventID}/attachments/{attachmentID}", handler.DeleteEventAttachment)
+
+    // custom field endpoints
+    apiRoutes.Get("/api/custom-fields", handler.GetCustomFields)
+    apiRoutes.Post("/api/custom-fields", handler.CreateCustomField)
+    apiRoutes.Patch("/api/custom-fields/{fieldID}", handler.UpdateCustomField)
+    apiRoutes.Delete("/api/custom-fields/{fieldID}", handler.DeleteCustomField)
+
+    // tag endpoints
+    apiRoutes.Get("/api/tags", handler.GetTags)
+    apiRoutes.Get("/api/tags/count", handler.GetTagCounts)
+    apiRoutes.Post("/api/tags", handler.CreateTag)
+    apiRoutes.Patch("/api/tags/{tagID}", handler.RenameTag)
+    apiRoutes.Delete("/api/tags/{tagID}", handler.DeleteTag)
+    apiRoutes.Get("/api/brothers/{id}/tags", handler.GetBrotherTags)
+    apiRoutes.Put("/api/brothers/{id}/tags/{tagID}", handler.TagBrother)
+    apiRoutes.Delete("/api/brothers/{id}/tags/{tagID}", handler.UntagBrother)
+    apiRoutes.Get("/api/events/{eventID}/tags", handler.GetEventTags)
+    apiRoutes.Put("/api/events/{eventID}/tags/{tagID}", handler.TagEvent)
+    apiRoutes.Delete("/api/events/{eventID}/tags/{tagID}", handler.UntagEvent)
+
+    // position endpoints
+    apiRoutes.Get("/api/positions", handler.GetPositions)
+    apiRoutes.Post("/api/positions", handler.CreatePosition)
+    apiRoutes.Patch("/api/positions/{positionID}", handler.UpdatePosition)
+    apiRoutes.Delete("/api/positions/{positionID}", handler.DeletePosition)
+    apiRoutes.Get("/api/positions/{positionID}/terms", handler.GetPositionTerms)
+    apiRoutes.Post("/api/positions/{positionID}/terms", handler.CreatePositionTerm)
+    apiRoutes.Patch("/api/positions/{positionID}/terms/{termID}", handler.UpdatePositionTerm)
+    apiRoutes.Delete("/api/positions/{positionID}/terms/{termID}", handler.DeletePositionTerm)
+    apiRoutes.Get("/api/brothers/{id}/positions", handler.GetBrotherPositions)
+
+    // lineage endpoints
+    apiRoutes.Put("/api/brothers/{id}/big", handler.SetBrotherBig)
+    apiRoutes.Delete("/api/brothers/{id}/big", handler.RemoveBrotherBig)
+    apiRoutes.Get("/api/brothers/{id}/ancestors", handler.GetBrotherAncestors)
+    apiRoutes.Get("/api/brothers/{id}/descendants", handler.GetBrotherDescendants)
+    apiRoutes.Get("/api/lineage", handler.GetLineage)
+    apiRoutes.Get("/api/lineage/families", handler.GetFamilies)
+
+    // pledge class endpoints
+    apiRoutes.Get("/api/classes", handler.GetClasses)
+    apiRoutes.Post("/api/classes", handler.CreateClass)
+    apiRoutes.Get("/api/classes/{classID}", handler.GetClass)
+    apiRoutes.Patch("/api/classes/{classID}", handler.UpdateClass)
+    apiRoutes.Delete("/api/classes/{classID}", handler.DeleteClass)
+    apiRoutes.Get("/api/classes/{classID}/brothers", handler.GetClassBrothers)
+
+    // candidate endpoints
+    apiRoutes.Get("/api/candidates", handler.GetCandidates)
+    apiRoutes.Post("/api/candidates", handler.CreateCandidate)
+    apiRoutes.Get("/api/candidates/pipeline", handler.GetCandidatePipeline)
+    apiRoutes.Get("/api/candidates/{candidateID}", handler.GetCandidate)
+    apiRoutes.Patch("/api/candidates/{candidateID}", handler.UpdateCandidate)
+    apiRoutes.Delete("/api/candidates/{candidateID}", handler.DeleteCandidate)
+    apiRoutes.Get("/api/candidates/{candidateID}/attendance", handler.GetCandidateAttendance)
+    apiRoutes.Put("/api/candidates/{candidateID}/attendance/{eventID}", handler.SetCandidateAttendance)
+    apiRoutes.Delete("/api/candidates/{candidateID}/attendance/{eventID}", handler.RemoveCandidateAttendance)
+    apiRoutes.Post("/api/candidates/{candidateID}/convert", handler.ConvertCandidate)
+    apiRoutes.Get("/api/events/{eventID}/candidates", handler.GetEventCandidates)
 
     // events endpoint
-	r.Get("/api/events", handler.GetAllEvents)
-	r.Get("/api/events/{eventID}", handler.GetEventByEventID)
-	r.Get("/api/events/{eventID}/attendance", handler.GetEventAttendance)
-	r.Post("/api/events/{eventID}/attendance", handler.CreateAttendanceRecordForEvent)
-	r.Patch("/api/events/{eventID}/attendance", handler.UpdateAttendanceByEventID)
-    r.Post("/api/events", handler.CreateEvent)
-    r.Patch("/api/events/{eventID}", handler.UpdateEventByID)
-    r.Delete("/api/events", handler.DeleteEventByEventID)
+	apiRoutes.Get("/api/events", handler.GetAllEvents)
+	apiRoutes.Get("/api/events/{eventID}", handler.GetEventByEventID)
+	apiRoutes.Get("/api/events/{eventID}/attendance", handler.GetEventAttendance)
+	apiRoutes.Post("/api/events/{eventID}/attendance", handler.CreateAttendanceRecordForEvent)
+	apiRoutes.Patch("/api/events/{eventID}/attendance", handler.UpdateAttendanceByEventID)
+    apiRoutes.Post("/api/events", handler.CreateEvent)
+    apiRoutes.Patch("/api/events/{eventID}", handler.UpdateEventByID)
+    apiRoutes.Delete("/api/events", handler.DeleteEventByEventID)
 
     // attendance endpoints
-    r.Get("/api/attendance", handler.GetAllAttendanceRecords)
-    r.Get("/api/attendance/{eventID}", handler.GetAttendanceFromEventID)
-    r.Post("/api/attendance", handler.CreateAttendance)
-    r.Put("/api/attendance", handler.UpdateAttendanceRecord)
-    r.Delete("/api/attendance", handler.DeleteAttendanceRecord)
+    apiRoutes.Get("/api/attendance", handler.GetAllAttendanceRecords)
+    apiRoutes.Get("/api/attendance/{eventID}", handler.GetAttendanceFromEventID)
+    apiRoutes.Post("/api/attendance", handler.CreateAttendance)
+    apiRoutes.Put("/api/attendance", handler.UpdateAttendanceRecord)
+    apiRoutes.Delete("/api/attendance", handler.DeleteAttendanceRecord)
 
     // semester endpoints
-    r.Get("/api/semesters", handler.GetAllSemesterLabels)
-    r.Post("/api/semesters", handler.CreateSemesterLabel)
-    r.Get("/api/semesters/{semester}/statuses", handler.GetAllBrotherStatusesForSemester)
-    r.Post("/api/semesters/{semester}/statuses", handler.CreateBrotherStatusForSemester)
+    apiRoutes.Get("/api/semesters", handler.GetAllSemesterLabels)
+    apiRoutes.Post("/api/semesters", handler.CreateSemesterLabel)
+    apiRoutes.Get("/api/semesters/{semester}", handler.GetSemesterByLabel)
+    apiRoutes.Get("/api/semesters/{semester}/statuses", handler.GetAllBrotherStatusesForSemester)
+    apiRoutes.Post("/api/semesters/{semester}/statuses", handler.CreateBrotherStatusForSemester)
+    apiRoutes.Get("/api/semesters/{semester}/officers", handler.GetSemesterOfficers)
+
+    // database endpoints
+    apiRoutes.Get("/api/db/stats", handler.GetDatabaseStats)
+
+    // admin endpoints
+    longRoutes.Get("/api/admin/export", handler.ExportBackup)
 
 	return r
 }
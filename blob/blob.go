@@ -0,0 +1,130 @@
+// Package blob stores file contents, such as attachments, in a pluggable backend:
+// a local directory (default) or an S3-compatible bucket
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+)
+
+// Returned by Store.Get when no blob is stored under the key
+var ErrNotFound = errors.New("blob not found")
+
+// Store keeps blobs under slash-separated keys, e.g. "events/12/3f2a..."
+type Store interface {
+	// Writes the contents of r under key, replacing any blob already there.
+	// size is the length of r, or -1 if unknown
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Opens the blob stored under key. Returns ErrNotFound if there is none
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Removes the blob stored under key. Removing a missing blob is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// Backends accepted by ATTACHMENTS_BACKEND
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+)
+
+// Settings for the blob store
+type Config struct {
+	// "local" (default) or "s3" (ATTACHMENTS_BACKEND)
+	Backend string
+	// Directory of the local backend (ATTACHMENTS_DIR)
+	Dir string
+	S3  S3Config
+}
+
+// Settings for attachment uploads
+type AttachmentConfig struct {
+	// Largest file accepted, in bytes (ATTACHMENTS_MAX_SIZE)
+	MaxSize int64
+	// Content types accepted, detected from the file's first bytes (ATTACHMENTS_ALLOWED_TYPES)
+	AllowedTypes []string
+}
+
+// Returns the upload limits used when none are configured: photos, PDFs and Word documents up to 10MB
+func DefaultAttachmentConfig() AttachmentConfig {
+	return AttachmentConfig{
+		MaxSize: 10 << 20,
+		AllowedTypes: []string{
+			"image/jpeg",
+			"image/png",
+			"image/gif",
+			"image/webp",
+			"image/heic",
+			"application/pdf",
+			"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+		},
+	}
+}
+
+// Checks that uploads can be accepted
+func (c AttachmentConfig) Validate() error {
+	if c.MaxSize <= 0 {
+		return errors.New("ATTACHMENTS_MAX_SIZE must be positive")
+	}
+	if len(c.AllowedTypes) == 0 {
+		return errors.New("ATTACHMENTS_ALLOWED_TYPES must list at least one content type")
+	}
+	return nil
+}
+
+// Settings for an S3-compatible bucket
+type S3Config struct {
+	// Endpoint URL, e.g. https://s3.us-west-2.amazonaws.com or http://minio:9000 (S3_ENDPOINT)
+	Endpoint string
+	Bucket   string
+	Region   string
+	// Credentials. Empty uses the standard AWS_* variables or the instance's IAM role
+	AccessKeyID     string
+	SecretAccessKey string
+	// Address the bucket as part of the path instead of the host name, as most self-hosted servers need
+	PathStyle bool
+}
+
+// Returns the blob store settings used when none are configured
+func DefaultConfig() Config {
+	return Config{
+		Backend: BackendLocal,
+		Dir:     "data/attachments",
+	}
+}
+
+// Checks that the settings of the selected backend are set
+func (c Config) Validate() error {
+	switch c.Backend {
+	case BackendLocal:
+		if c.Dir == "" {
+			return errors.New("ATTACHMENTS_DIR is required with the local backend")
+		}
+	case BackendS3:
+		if c.S3.Bucket == "" {
+			return errors.New("S3_BUCKET is required with the s3 backend")
+		}
+		if u, err := url.Parse(c.S3.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("S3_ENDPOINT must be an http(s) URL such as https://s3.us-west-2.amazonaws.com, got %q", c.S3.Endpoint)
+		}
+		if (c.S3.AccessKeyID == "") != (c.S3.SecretAccessKey == "") {
+			return errors.New("S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY must be set together")
+		}
+	default:
+		return fmt.Errorf("ATTACHMENTS_BACKEND must be local or s3, got %q", c.Backend)
+	}
+	return nil
+}
+
+// Opens the configured backend, creating the local directory if needed
+func Open(ctx context.Context, config Config) (Store, error) {
+	switch config.Backend {
+	case BackendLocal:
+		return NewLocal(config.Dir)
+	case BackendS3:
+		return NewS3(ctx, config.S3)
+	}
+	return nil, fmt.Errorf("unknown blob backend %q", config.Backend)
+}
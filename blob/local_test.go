@@ -0,0 +1,52 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLocalRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocal: %v", err)
+	}
+
+	if err := store.Put(ctx, "events/1/sheet", strings.NewReader("sign-in sheet"), -1, "text/plain"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	r, err := store.Get(ctx, "events/1/sheet")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	content, _ := io.ReadAll(r)
+	r.Close()
+	if string(content) != "sign-in sheet" {
+		t.Errorf("Expected the stored content back. Got %q", content)
+	}
+
+	if err := store.Delete(ctx, "events/1/sheet"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, "events/1/sheet"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete. Got %v", err)
+	}
+	if err := store.Delete(ctx, "events/1/sheet"); err != nil {
+		t.Errorf("Expected deleting a missing blob to succeed. Got %v", err)
+	}
+}
+
+func TestLocalRejectsKeysOutsideDir(t *testing.T) {
+	store, err := NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocal: %v", err)
+	}
+	for _, key := range []string{"", "../secret", "events/../../secret", "/etc/passwd", `events\..\secret`} {
+		if err := store.Put(context.Background(), key, strings.NewReader("x"), 1, ""); err == nil {
+			t.Errorf("Expected key %q to be rejected", key)
+		}
+	}
+}
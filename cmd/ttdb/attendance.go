@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/csv"
+	"io"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/pacific-theta-tau/tt-db/store"
+	"github.com/urfave/cli/v2"
+)
+
+var exportCommand = &cli.Command{
+	Name:  "export",
+	Usage: "Export records as CSV",
+	Subcommands: []*cli.Command{
+		exportBrothersCommand,
+		{
+			Name:  "attendance",
+			Usage: "Export the attendance of every event held during a semester",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "semester", Usage: "Semester label, e.g. \"Fall 2024\"", Required: true},
+				&cli.StringFlag{Name: "output", Aliases: []string{"o"}, Usage: "File to write instead of stdout"},
+			},
+			Action: func(c *cli.Context) error {
+				database, err := connect(c)
+				if err != nil {
+					return err
+				}
+				defer database.Close()
+
+				records, err := store.SemesterAttendance(c.Context, database.Conn, c.String("semester"))
+				if err != nil {
+					return err
+				}
+
+				var out io.Writer = c.App.Writer
+				if path := c.String("output"); path != "" {
+					file, err := os.Create(path)
+					if err != nil {
+						return err
+					}
+					defer file.Close()
+					out = file
+				}
+
+				w := csv.NewWriter(out)
+				w.Write([]string{"eventDate", "eventName", "eventCategory", "eventLocation", "rollCall", "firstName", "lastName", "attendanceStatus"})
+				for _, record := range records {
+					w.Write([]string{
+						record.EventDate.Format("2006-01-02"),
+						record.EventName,
+						record.EventCategory,
+						record.EventLocation,
+						strconv.Itoa(record.RollCall),
+						record.FirstName,
+						record.LastName,
+						record.AttendanceStatus,
+					})
+				}
+				w.Flush()
+				if err := w.Error(); err != nil {
+					return err
+				}
+				log.Printf("Exported %d attendance records for %s", len(records), c.String("semester"))
+				return nil
+			},
+		},
+	},
+}
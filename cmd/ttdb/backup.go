@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/pacific-theta-tau/tt-db/store"
+	"github.com/urfave/cli/v2"
+)
+
+var backupCommand = &cli.Command{
+	Name:  "backup",
+	Usage: "Export every table to a versioned ZIP archive (the same as GET /api/admin/export)",
+	Flags: []cli.Flag{
+		&cli.StringFlag{Name: "output", Aliases: []string{"o"}, Usage: "Archive to write (default: tt-db-backup-<time>.zip)"},
+	},
+	Action: func(c *cli.Context) error {
+		database, err := connect(c)
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		tx, err := database.Conn.BeginTx(c.Context, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+		backup, err := store.ExportBackup(c.Context, tx)
+		if err != nil {
+			return err
+		}
+
+		path := c.String("output")
+		if path == "" {
+			path = fmt.Sprintf("tt-db-backup-%s.zip", time.Now().Format("20060102-150405"))
+		}
+		if err := writeArchive(path, backup); err != nil {
+			return err
+		}
+		log.Printf("Wrote %s (schema version %d): %v", path, backup.Manifest.SchemaVersion, backup.Manifest.Tables)
+		return nil
+	},
+}
+
+var restoreCommand = &cli.Command{
+	Name:      "restore",
+	Usage:     "Restore a backup archive into an empty database",
+	ArgsUsage: "BACKUP.zip",
+	Description: "The database must be migrated to the backup's schema version and its tables must be empty.\n" +
+		"Everything is restored in one transaction, so a failed restore changes nothing.",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{Name: "dry-run", Usage: "Check the archive and restore in a transaction that is rolled back"},
+	},
+	Action: func(c *cli.Context) error {
+		if c.NArg() != 1 {
+			return cli.Exit("expected exactly one backup archive", 2)
+		}
+		backup, err := readArchive(c.Args().First())
+		if err != nil {
+			return err
+		}
+		log.Printf("Backup from %s (schema version %d): %v", backup.Manifest.CreatedAt.Format(time.RFC3339), backup.Manifest.SchemaVersion, backup.Manifest.Tables)
+
+		return inTransaction(c, c.Bool("dry-run"), func(ctx context.Context, tx *sql.Tx) error {
+			if err := store.RestoreBackup(ctx, tx, backup); err != nil {
+				return err
+			}
+			log.Println("Restored backup")
+			return nil
+		})
+	},
+}
+
+// Writes a backup archive to path
+func writeArchive(path string, backup store.Backup) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := store.WriteBackup(file, backup); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Reads and checks the backup archive at path
+func readArchive(path string) (store.Backup, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return store.Backup{}, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return store.Backup{}, err
+	}
+	return store.ReadBackup(file, info.Size())
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/pacific-theta-tau/tt-db/api/models"
+	"github.com/pacific-theta-tau/tt-db/store"
+	"github.com/urfave/cli/v2"
+)
+
+var importCommand = &cli.Command{
+	Name:  "import",
+	Usage: "Import records from a file",
+	Subcommands: []*cli.Command{
+		{
+			Name:      "brothers",
+			Usage:     "Import brothers from a CSV file",
+			ArgsUsage: "FILE.csv",
+			Description: "The first row names the columns, using the API's field names: rollCall, firstName,\n" +
+				"lastName, major, status (required), className, email, phoneNumber and badStanding.\n" +
+				"Other columns must be named after a custom field; empty cells leave the field unset.\n" +
+				"A className without a pledge class creates it; roll calls must be in their class's range.\n" +
+				"All rows are checked before anything is inserted, and they are inserted in a single\n" +
+				"transaction: either every brother is imported or none is.",
+			Flags: []cli.Flag{
+				&cli.BoolFlag{Name: "dry-run", Usage: "Check the file and insert in a transaction that is rolled back"},
+			},
+			Action: importBrothers,
+		},
+	},
+}
+
+// Subcommand of export
+var exportBrothersCommand = &cli.Command{
+	Name:  "brothers",
+	Usage: "Export every brother, with a column per custom field",
+	Description: "The columns match the ones import brothers reads, so the file can be edited and\n" +
+		"imported into another database that defines the same custom fields.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{Name: "output", Aliases: []string{"o"}, Usage: "File to write instead of stdout"},
+	},
+	Action: func(c *cli.Context) error {
+		database, err := connect(c)
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		fields, err := store.CustomFields(c.Context, database.Conn)
+		if err != nil {
+			return err
+		}
+		brothers, err := store.ListBrothers(c.Context, database.Conn, store.BrotherFilter{})
+		if err != nil {
+			return err
+		}
+
+		var out io.Writer = c.App.Writer
+		if path := c.String("output"); path != "" {
+			file, err := os.Create(path)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			out = file
+		}
+		if err := writeBrothersCSV(out, brothers, fields); err != nil {
+			return err
+		}
+		log.Printf("Exported %d brothers with %d custom fields", len(brothers), len(fields))
+		return nil
+	},
+}
+
+func importBrothers(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return cli.Exit("expected exactly one CSV file", 2)
+	}
+	file, err := os.Open(c.Args().First())
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return inTransaction(c, c.Bool("dry-run"), func(ctx context.Context, tx *sql.Tx) error {
+		fields, err := store.CustomFields(ctx, tx)
+		if err != nil {
+			return err
+		}
+		brothers, err := readBrothersCSV(file, fields)
+		if err != nil {
+			return err
+		}
+		for i, brother := range brothers {
+			// Line 1 is the header. Unknown class names create the class
+			fieldErrors, err := store.ResolveBrotherClass(ctx, tx, &brother)
+			if err == nil && len(fieldErrors) > 0 {
+				err = errors.New(fieldErrors[0].Message)
+			}
+			if err == nil {
+				_, err = store.InsertBrother(ctx, tx, brother)
+			}
+			if err != nil {
+				return fmt.Errorf("line %d (roll call %d): %w", i+2, brother.RollCall, err)
+			}
+		}
+		log.Printf("Imported %d brothers", len(brothers))
+		return nil
+	})
+}
+
+// Columns of a brothers CSV file, keyed by JSON field name
+var brotherFields = map[string]func(b *models.Brother, value string) error{
+	"rollcall":    func(b *models.Brother, v string) (err error) { b.RollCall, err = atoi(v); return err },
+	"firstname":   func(b *models.Brother, v string) error { b.FirstName = v; return nil },
+	"lastname":    func(b *models.Brother, v string) error { b.LastName = v; return nil },
+	"major":       func(b *models.Brother, v string) error { b.Major = v; return nil },
+	"status":      func(b *models.Brother, v string) error { b.Status = v; return nil },
+	"classname":   func(b *models.Brother, v string) error { b.Class = v; return nil },
+	"email":       func(b *models.Brother, v string) error { b.Email = v; return nil },
+	"phonenumber": func(b *models.Brother, v string) error { b.PhoneNumber = v; return nil },
+	"badstanding": func(b *models.Brother, v string) (err error) { b.BadStanding, err = atoi(v); return err },
+}
+
+// Writes brothers in the format readBrothersCSV reads. Unset custom fields are left empty
+func writeBrothersCSV(out io.Writer, brothers []models.Brother, fields []models.CustomField) error {
+	w := csv.NewWriter(out)
+	header := []string{"rollCall", "firstName", "lastName", "major", "status", "className", "email", "phoneNumber", "badStanding"}
+	for _, field := range fields {
+		header = append(header, field.Name)
+	}
+	w.Write(header)
+	for _, b := range brothers {
+		record := []string{
+			strconv.Itoa(b.RollCall),
+			b.FirstName,
+			b.LastName,
+			b.Major,
+			b.Status,
+			b.Class,
+			b.Email,
+			b.PhoneNumber,
+			strconv.Itoa(b.BadStanding),
+		}
+		for _, field := range fields {
+			var cell string
+			switch value := b.CustomFields[field.Name].(type) {
+			case float64:
+				cell = strconv.FormatFloat(value, 'f', -1, 64)
+			case string:
+				cell = value
+			}
+			record = append(record, cell)
+		}
+		w.Write(record)
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// Empty cells are read as 0
+func atoi(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", value)
+	}
+	return n, nil
+}
+
+// Column of a custom field: parses the cell into the brother's custom fields. Empty cells are left unset
+func customFieldSetter(field models.CustomField) func(*models.Brother, string) error {
+	return func(b *models.Brother, v string) error {
+		if v == "" {
+			return nil
+		}
+		value, err := store.ParseCustomFieldValue(field, v)
+		if err != nil {
+			return err
+		}
+		b.CustomFields[field.Name] = value
+		return nil
+	}
+}
+
+// Reads and validates every row of a brothers CSV file. Columns other than the brother fields
+// must be custom fields. Problems on all lines are reported together
+func readBrothersCSV(r io.Reader, fields []models.CustomField) ([]models.Brother, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+	customFields := make(map[string]models.CustomField, len(fields))
+	for _, field := range fields {
+		customFields[strings.ToLower(field.Name)] = field
+	}
+	setters := make([]func(*models.Brother, string) error, len(header))
+	for i, column := range header {
+		name := strings.ToLower(strings.TrimSpace(column))
+		if set, ok := brotherFields[name]; ok {
+			setters[i] = set
+		} else if field, ok := customFields[name]; ok {
+			setters[i] = customFieldSetter(field)
+		} else {
+			return nil, fmt.Errorf("unknown column %q", column)
+		}
+	}
+
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		return strings.Split(field.Tag.Get("json"), ",")[0]
+	})
+
+	var brothers []models.Brother
+	var errs []error
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		brother := models.Brother{CustomFields: map[string]interface{}{}}
+		invalid := map[string]bool{}
+		for i, value := range record {
+			if err := setters[i](&brother, strings.TrimSpace(value)); err != nil {
+				errs = append(errs, fmt.Errorf("line %d: %s: %w", line, header[i], err))
+				invalid[strings.ToLower(strings.TrimSpace(header[i]))] = true
+			}
+		}
+		var fieldErrors validator.ValidationErrors
+		if errors.As(validate.Struct(brother), &fieldErrors) {
+			for _, fe := range fieldErrors {
+				errs = append(errs, fmt.Errorf("line %d: %s is %s", line, fe.Field(), fe.Tag()))
+			}
+		}
+		// Values were checked while parsing; this catches missing required fields that weren't already reported
+		_, customErrors := store.ValidateCustomFieldValues(fields, brother.CustomFields, false)
+		for _, fe := range customErrors {
+			if !invalid[strings.ToLower(strings.TrimPrefix(fe.Field, "customFields."))] {
+				errs = append(errs, fmt.Errorf("line %d: %s", line, fe.Message))
+			}
+		}
+		brothers = append(brothers, brother)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	if len(brothers) == 0 {
+		return nil, errors.New("file has no rows")
+	}
+	return brothers, nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+)
+
+func TestReadBrothersCSV(t *testing.T) {
+	file := `rollCall,firstName,lastName,major,status,email
+101, Jane ,Doe,Computer Science,Active,jane@example.com
+102,John,Smith,Civil Engineering,Alumnus,
+`
+	brothers, err := readBrothersCSV(strings.NewReader(file), nil)
+	if err != nil {
+		t.Fatalf("readBrothersCSV: %v", err)
+	}
+	if len(brothers) != 2 {
+		t.Fatalf("Expected 2 brothers. Got %d", len(brothers))
+	}
+	if b := brothers[0]; b.RollCall != 101 || b.FirstName != "Jane" || b.Email != "jane@example.com" {
+		t.Errorf("Unexpected first brother: %+v", b)
+	}
+}
+
+func TestReadBrothersCSVReportsEveryLine(t *testing.T) {
+	file := `rollCall,firstName,lastName,major,status
+abc,Jane,Doe,Computer Science,Active
+103,,Smith,Civil Engineering,Alumnus
+`
+	_, err := readBrothersCSV(strings.NewReader(file), nil)
+	if err == nil {
+		t.Fatal("Expected errors")
+	}
+	for _, expected := range []string{`line 2: rollCall: "abc" is not a number`, "line 2: rollCall is required", "line 3: firstName is required"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected error to contain %q. Got:\n%v", expected, err)
+		}
+	}
+	if strings.Contains(err.Error(), "line 2: customFields.shirtSize is required") {
+		t.Errorf("Expected an invalid value not to be reported as missing too. Got:\n%v", err)
+	}
+}
+
+func TestReadBrothersCSVRejectsUnknownColumns(t *testing.T) {
+	if _, err := readBrothersCSV(strings.NewReader("rollCall,nickname\n1,JD\n"), nil); err == nil || !strings.Contains(err.Error(), "nickname") {
+		t.Errorf("Expected unknown column error. Got %v", err)
+	}
+}
+
+func TestReadBrothersCSVCustomFields(t *testing.T) {
+	fields := []models.CustomField{
+		{Name: "shirtSize", Type: models.CustomFieldEnum, Options: []string{"S", "M", "L"}, Required: true},
+		{Name: "gradYear", Type: models.CustomFieldNumber},
+	}
+	file := `rollCall,firstName,lastName,major,status,shirtSize,gradYear
+101,Jane,Doe,Computer Science,Active,M,2026
+102,John,Smith,Civil Engineering,Alumnus,M,
+`
+	brothers, err := readBrothersCSV(strings.NewReader(file), fields)
+	if err != nil {
+		t.Fatalf("readBrothersCSV: %v", err)
+	}
+	if b := brothers[0]; b.CustomFields["shirtSize"] != "M" || b.CustomFields["gradYear"] != 2026.0 {
+		t.Errorf("Unexpected custom fields: %v", b.CustomFields)
+	}
+	if _, ok := brothers[1].CustomFields["gradYear"]; ok {
+		t.Errorf("Expected empty cells to leave the field unset. Got %v", brothers[1].CustomFields)
+	}
+
+	file = `rollCall,firstName,lastName,major,status,shirtSize,gradYear
+103,Jane,Doe,Computer Science,Active,XL,soon
+104,John,Smith,Civil Engineering,Alumnus,,
+`
+	_, err = readBrothersCSV(strings.NewReader(file), fields)
+	if err == nil {
+		t.Fatal("Expected errors")
+	}
+	for _, expected := range []string{"line 2: shirtSize: must be one of: S, M, L", `line 2: gradYear: "soon" is not a number`, "line 3: customFields.shirtSize is required"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected error to contain %q. Got:\n%v", expected, err)
+		}
+	}
+	if strings.Contains(err.Error(), "line 2: customFields.shirtSize is required") {
+		t.Errorf("Expected an invalid value not to be reported as missing too. Got:\n%v", err)
+	}
+}
+
+func TestWriteBrothersCSVRoundTrips(t *testing.T) {
+	fields := []models.CustomField{
+		{Name: "shirtSize", Type: models.CustomFieldEnum, Options: []string{"S", "M"}},
+		{Name: "gradYear", Type: models.CustomFieldNumber},
+	}
+	brothers := []models.Brother{
+		{RollCall: 101, FirstName: "Jane", LastName: "Doe", Major: "Computer Science", Status: "Active",
+			CustomFields: map[string]interface{}{"shirtSize": "M", "gradYear": 2026.0}},
+		{RollCall: 102, FirstName: "John", LastName: "Smith", Major: "Civil Engineering", Status: "Alumnus",
+			CustomFields: map[string]interface{}{}},
+	}
+
+	var out strings.Builder
+	if err := writeBrothersCSV(&out, brothers, fields); err != nil {
+		t.Fatalf("writeBrothersCSV: %v", err)
+	}
+	if !strings.Contains(out.String(), "101,Jane,Doe,Computer Science,Active,,,,0,M,2026\n") {
+		t.Errorf("Unexpected CSV:\n%s", out.String())
+	}
+	read, err := readBrothersCSV(strings.NewReader(out.String()), fields)
+	if err != nil {
+		t.Fatalf("readBrothersCSV: %v", err)
+	}
+	if read[0].CustomFields["gradYear"] != 2026.0 || len(read[1].CustomFields) != 0 {
+		t.Errorf("Expected the export to import back. Got %+v", read)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/pacific-theta-tau/tt-db/db"
+	"github.com/pacific-theta-tau/tt-db/store"
+	"github.com/urfave/cli/v2"
+)
+
+var migrateCommand = &cli.Command{
+	Name:  "migrate",
+	Usage: "Apply pending schema migrations",
+	Action: func(c *cli.Context) error {
+		database, err := connect(c)
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		if err := database.Migrate(c.Context); err != nil {
+			return err
+		}
+		return printSchemaStatus(c, database)
+	},
+	Subcommands: []*cli.Command{
+		{
+			Name:  "status",
+			Usage: "Show the applied schema version and pending migrations without changing anything",
+			Action: func(c *cli.Context) error {
+				database, err := connect(c)
+				if err != nil {
+					return err
+				}
+				defer database.Close()
+				return printSchemaStatus(c, database)
+			},
+		},
+	},
+}
+
+func printSchemaStatus(c *cli.Context, database *db.PostgresDB) error {
+	status, err := db.GetSchemaStatus(c.Context, database.Conn)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.App.Writer, "Schema version %d of %d\n", status.Version, status.Latest)
+	for _, migration := range status.Pending {
+		fmt.Fprintf(c.App.Writer, "Pending: %06d_%s\n", migration.Version, migration.Name)
+	}
+	return nil
+}
+
+var seedCommand = &cli.Command{
+	Name:  "seed",
+	Usage: "Insert sample brothers, events, semesters and statuses for development",
+	Description: "Rows that already exist are skipped, so seeding twice is safe.\n" +
+		"Requires the unique constraints from migration 000001: run `ttdb migrate` first.",
+	Action: func(c *cli.Context) error {
+		return inTransaction(c, false, func(ctx context.Context, tx *sql.Tx) error {
+			if err := store.Seed(ctx, tx); err != nil {
+				return err
+			}
+			log.Println("Seeded sample data")
+			return nil
+		})
+	},
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"crypto/rand"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/pacific-theta-tau/tt-db/db"
+	"github.com/pacific-theta-tau/tt-db/devdata"
+	"github.com/urfave/cli/v2"
+)
+
+var anonymizeCommand = &cli.Command{
+	Name:      "anonymize",
+	Usage:     "Copy a backup archive with brothers' names, emails and phone numbers replaced",
+	ArgsUsage: "BACKUP.zip",
+	Description: "Equal values get equal replacements for the same key, so the copy keeps likely duplicates\n" +
+		"and can be regenerated identically. Keep the key secret: it is what prevents mapping fake\n" +
+		"names back to real ones. Load the result with `ttdb restore`.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{Name: "output", Aliases: []string{"o"}, Usage: "Archive to write", Required: true},
+		&cli.StringFlag{Name: "key", Usage: "Secret the replacements are derived from (default: random, so every run differs)", EnvVars: []string{"TTDB_ANONYMIZE_KEY"}},
+	},
+	Action: func(c *cli.Context) error {
+		if c.NArg() != 1 {
+			return cli.Exit("expected exactly one backup archive", 2)
+		}
+		backup, err := readArchive(c.Args().First())
+		if err != nil {
+			return err
+		}
+
+		key := []byte(c.String("key"))
+		if len(key) == 0 {
+			key = make([]byte, 32)
+			if _, err := rand.Read(key); err != nil {
+				return err
+			}
+			log.Println("No --key given: using a random key")
+		}
+		anonymized, err := devdata.NewAnonymizer(key).Backup(backup)
+		if err != nil {
+			return err
+		}
+		if err := writeArchive(c.String("output"), anonymized); err != nil {
+			return err
+		}
+		log.Printf("Wrote anonymized copy to %s: %v", c.String("output"), anonymized.Manifest.Tables)
+		return nil
+	},
+}
+
+// Label of the semester containing t, using the same Spring/Fall split as the frontend
+func currentSemester(t time.Time) string {
+	if t.Month() < time.July {
+		return fmt.Sprintf("Spring %d", t.Year())
+	}
+	return fmt.Sprintf("Fall %d", t.Year())
+}
+
+var generateCommand = &cli.Command{
+	Name:  "generate",
+	Usage: "Create a synthetic chapter as a backup archive, for development and load testing",
+	Description: "Each semester recruits a pledge class; brothers have plausible status histories and attend\n" +
+		"most events while active. Load the result into an empty database with `ttdb restore`.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{Name: "output", Aliases: []string{"o"}, Usage: "Archive to write", Required: true},
+		&cli.IntFlag{Name: "brothers", Usage: "Number of brothers", Value: 200},
+		&cli.IntFlag{Name: "semesters", Usage: "Number of semesters", Value: 12},
+		&cli.StringFlag{Name: "until", Usage: "Last semester (default: the current one)"},
+		&cli.Uint64Flag{Name: "seed", Usage: "Random seed. The same seed gives the same dataset", Value: 1},
+	},
+	Action: func(c *cli.Context) error {
+		until := c.String("until")
+		if until == "" {
+			until = currentSemester(time.Now())
+		}
+		schemaVersion, err := db.LatestSchemaVersion()
+		if err != nil {
+			return err
+		}
+
+		backup, err := devdata.Generate(devdata.Options{
+			Brothers:      c.Int("brothers"),
+			Semesters:     c.Int("semesters"),
+			Until:         until,
+			Seed:          c.Uint64("seed"),
+			SchemaVersion: schemaVersion,
+		})
+		if err != nil {
+			return err
+		}
+		if err := writeArchive(c.String("output"), backup); err != nil {
+			return err
+		}
+		log.Printf("Wrote synthetic dataset to %s: %v", c.String("output"), backup.Manifest.Tables)
+		return nil
+	},
+}
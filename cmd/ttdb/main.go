@@ -0,0 +1,95 @@
+// Command ttdb runs database operations (migrations, seeding, imports, exports, users,
+// semester rollover, backups, restores and development datasets) with the same configuration
+// and queries as the API.
+//
+// Run `go run ./cmd/ttdb --help` for usage
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/pacific-theta-tau/tt-db/config"
+	"github.com/pacific-theta-tau/tt-db/db"
+	"github.com/pacific-theta-tau/tt-db/logging"
+	"github.com/urfave/cli/v2"
+)
+
+func main() {
+	app := &cli.App{
+		Name:  "ttdb",
+		Usage: "Administer the tt-db database",
+		Description: "Reads the same settings as the API: env vars and the optional <env>.env file.\n" +
+			"Progress is logged to stderr; exported data is written to stdout unless --output is set.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "env", Usage: "Environment whose <env>.env file is read", EnvVars: []string{"APP_ENV"}, Value: "dev"},
+			&cli.StringFlag{Name: "config-file", Usage: "Env file to read settings from instead of <env>.env"},
+		},
+		Commands: []*cli.Command{
+			migrateCommand,
+			seedCommand,
+			importCommand,
+			exportCommand,
+			createUserCommand,
+			rolloverCommand,
+			backupCommand,
+			restoreCommand,
+			anonymizeCommand,
+			generateCommand,
+			syncRolesCommand,
+		},
+	}
+	if err := app.Run(os.Args); err != nil {
+		log.Fatalf("ERROR: %v", err)
+	}
+}
+
+// Loads the configuration and connects to the database. Close the returned database when done
+func connect(c *cli.Context) (*db.PostgresDB, error) {
+	args := []string{"-env", c.String("env")}
+	if file := c.String("config-file"); file != "" {
+		args = append(args, "-config-file", file)
+	}
+	cfg, err := config.Load(args)
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	logging.Setup(os.Stderr, cfg.LogLevel)
+	log.SetOutput(os.Stderr)
+
+	database := db.NewPostgresDB(cfg.DatabaseURL)
+	database.Pool = cfg.Pool
+	if err := database.Connect(); err != nil {
+		return nil, err
+	}
+	return database, nil
+}
+
+// Runs fn in a transaction on a new connection. The transaction is committed if fn succeeds
+// and dryRun is false, and rolled back otherwise
+func inTransaction(c *cli.Context, dryRun bool, fn func(ctx context.Context, tx *sql.Tx) error) error {
+	database, err := connect(c)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	ctx := c.Context
+	tx, err := database.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(ctx, tx); err != nil {
+		return err
+	}
+	if dryRun {
+		log.Println("Dry run: rolled back all changes")
+		return nil
+	}
+	return tx.Commit()
+}
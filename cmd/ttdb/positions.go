@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+	"github.com/pacific-theta-tau/tt-db/store"
+	"github.com/urfave/cli/v2"
+)
+
+var syncRolesCommand = &cli.Command{
+	Name:  "sync-roles",
+	Usage: "Give users the roles of the positions they hold",
+	Description: "Users are matched to brothers by email. Holders of a position with a role get it; users who held\n" +
+		"one before but no longer serve become members. Admins are never lowered, so demote them by hand.\n" +
+		"Run it after assigning the officers of a new semester.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{Name: "semester", Usage: "Semester whose officers get roles, e.g. \"Fall 2025\" (default: whoever serves today)"},
+		&cli.BoolFlag{Name: "dry-run", Usage: "Report what would change and roll back"},
+	},
+	Action: func(c *cli.Context) error {
+		on := time.Now()
+		if semester := c.String("semester"); semester != "" {
+			start, _, err := store.SemesterDates(semester)
+			if err != nil {
+				return cli.Exit(err.Error(), 2)
+			}
+			on = start
+		}
+
+		return inTransaction(c, c.Bool("dry-run"), func(ctx context.Context, tx *sql.Tx) error {
+			granted, err := store.PositionRoles(ctx, tx, on)
+			if err != nil {
+				return err
+			}
+			holders, err := store.RoleHolderEmails(ctx, tx)
+			if err != nil {
+				return err
+			}
+			users, err := store.Users(ctx, tx)
+			if err != nil {
+				return err
+			}
+
+			changed := 0
+			for _, user := range users {
+				role, ok := syncedRole(user, granted, holders)
+				if !ok {
+					continue
+				}
+				if err := store.SetUserRole(ctx, tx, user.UserID, role); err != nil {
+					return err
+				}
+				log.Printf("Changed role of %s from %s to %s", user.Email, user.Role, role)
+				changed++
+			}
+			log.Printf("Changed the role of %d of %d users", changed, len(users))
+			return nil
+		})
+	},
+}
+
+// Returns the role sync-roles gives a user, and false if the user's role should be left alone.
+// granted maps emails to the roles of the positions they hold; holders has every email that ever held one
+func syncedRole(user models.User, granted map[string]string, holders map[string]bool) (string, bool) {
+	email := strings.ToLower(strings.TrimSpace(user.Email))
+	role, serving := granted[email]
+	if !serving {
+		if !holders[email] {
+			return "", false
+		}
+		role = models.RoleMember
+	}
+	if role == user.Role || user.Role == models.RoleAdmin {
+		return "", false
+	}
+	return role, true
+}
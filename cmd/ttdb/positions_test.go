@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+)
+
+func TestSyncedRole(t *testing.T) {
+	granted := map[string]string{"regent@example.com": models.RoleAdmin, "scribe@example.com": models.RoleOfficer}
+	holders := map[string]bool{"regent@example.com": true, "scribe@example.com": true, "former@example.com": true}
+
+	tests := []struct {
+		email   string
+		role    string
+		synced  string
+		changed bool
+	}{
+		{"Scribe@Example.com", models.RoleMember, models.RoleOfficer, true},
+		{"regent@example.com", models.RoleOfficer, models.RoleAdmin, true},
+		{"scribe@example.com", models.RoleOfficer, "", false},
+		{"former@example.com", models.RoleOfficer, models.RoleMember, true},
+		// Admins are never lowered and users who never held a position are left alone
+		{"scribe@example.com", models.RoleAdmin, "", false},
+		{"advisor@example.com", models.RoleOfficer, "", false},
+	}
+	for _, test := range tests {
+		synced, changed := syncedRole(models.User{Email: test.email, Role: test.role}, granted, holders)
+		if synced != test.synced || changed != test.changed {
+			t.Errorf("syncedRole(%s, %s): expected %q, %v. Got %q, %v", test.email, test.role, test.synced, test.changed, synced, changed)
+		}
+	}
+}
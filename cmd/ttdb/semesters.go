@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/pacific-theta-tau/tt-db/store"
+	"github.com/urfave/cli/v2"
+)
+
+var rolloverCommand = &cli.Command{
+	Name:  "rollover",
+	Usage: "Start a semester by copying every brother's status from the previous one",
+	Description: "Creates the semester if it doesn't exist. Brothers that already have a status for it keep it,\n" +
+		"so statuses can be adjusted afterwards with the API and rollover can be re-run safely.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{Name: "semester", Usage: "Semester to start, e.g. \"Spring 2025\"", Required: true},
+		&cli.StringFlag{Name: "from", Usage: "Semester to copy statuses from (default: the latest one before --semester)"},
+		&cli.BoolFlag{Name: "dry-run", Usage: "Report what would change and roll back"},
+	},
+	Action: func(c *cli.Context) error {
+		return inTransaction(c, c.Bool("dry-run"), func(ctx context.Context, tx *sql.Tx) error {
+			result, err := store.RolloverStatuses(ctx, tx, c.String("from"), c.String("semester"))
+			if err != nil {
+				return err
+			}
+			if result.CreatedSemester {
+				log.Printf("Created semester %s", result.To)
+			}
+			log.Printf("Copied %d statuses from %s to %s (%d brothers already had one)", result.Copied, result.From, result.To, result.Skipped)
+			return nil
+		})
+	},
+}
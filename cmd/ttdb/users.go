@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+	"github.com/pacific-theta-tau/tt-db/store"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Shortest password accepted from --password-stdin
+const minPasswordLength = 12
+
+var createUserCommand = &cli.Command{
+	Name:  "create-user",
+	Usage: "Create a user account",
+	Description: "Without --password-stdin, a random password is generated and printed once.\n" +
+		"Example: echo \"$PASSWORD\" | ttdb create-user --email admin@example.com --role admin --password-stdin",
+	Flags: []cli.Flag{
+		&cli.StringFlag{Name: "email", Usage: "Email the user logs in with", Required: true},
+		&cli.StringFlag{Name: "role", Usage: "One of " + strings.Join(models.Roles, ", "), Value: models.RoleMember},
+		&cli.BoolFlag{Name: "password-stdin", Usage: "Read the password from the first line of stdin"},
+	},
+	Action: func(c *cli.Context) error {
+		user := models.User{Email: strings.TrimSpace(c.String("email")), Role: c.String("role")}
+		if !slices.Contains(models.Roles, user.Role) {
+			return cli.Exit(fmt.Sprintf("--role must be one of %s", strings.Join(models.Roles, ", ")), 2)
+		}
+
+		password, generated, err := readPassword(c)
+		if err != nil {
+			return err
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return err
+		}
+
+		return inTransaction(c, false, func(ctx context.Context, tx *sql.Tx) error {
+			created, err := store.CreateUser(ctx, tx, user, string(hash))
+			if err != nil {
+				return err
+			}
+			log.Printf("Created %s user %s (userID %d)", created.Role, created.Email, created.UserID)
+			if generated {
+				fmt.Fprintf(c.App.Writer, "Generated password (shown only once): %s\n", password)
+			}
+			return nil
+		})
+	},
+}
+
+// Returns the password from stdin, or a random one when --password-stdin is not set
+func readPassword(c *cli.Context) (password string, generated bool, err error) {
+	if !c.Bool("password-stdin") {
+		b := make([]byte, 18)
+		if _, err := rand.Read(b); err != nil {
+			return "", false, err
+		}
+		return base64.RawURLEncoding.EncodeToString(b), true, nil
+	}
+
+	line, err := bufio.NewReader(c.App.Reader).ReadString('\n')
+	if err != nil && line == "" {
+		return "", false, errors.New("no password on stdin")
+	}
+	password = strings.TrimRight(line, "\r\n")
+	if len(password) < minPasswordLength {
+		return "", false, fmt.Errorf("password must be at least %d characters", minPasswordLength)
+	}
+	return password, false, nil
+}
@@ -0,0 +1,433 @@
+// Package config loads the API's settings into a typed Config.
+//
+// Each setting is read, from highest to lowest precedence, from:
+//  1. a command-line flag (only some settings have one)
+//  2. the process environment
+//  3. an optional env file (`<env>.env`, or the path given with -config-file)
+//  4. its default
+//
+// A setting that is present but empty, e.g. `DATABASE_URL=` in the environment, clears the sources below it.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/pacific-theta-tau/tt-db/api"
+	"github.com/pacific-theta-tau/tt-db/api/middleware"
+	"github.com/pacific-theta-tau/tt-db/blob"
+	"github.com/pacific-theta-tau/tt-db/db"
+	"github.com/pacific-theta-tau/tt-db/logging"
+	"github.com/pacific-theta-tau/tt-db/tracing"
+)
+
+// Where the effective value of a setting came from
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+// Config holds every setting of the API
+type Config struct {
+	// Name of the environment, e.g. dev or prod. Selects the default env file
+	Env string
+	// Port the API listens on
+	Port int
+	// Postgres connection URL
+	DatabaseURL string
+	// Apply pending schema migrations on startup
+	AutoMigrate bool
+	// Minimum level of logged messages
+	LogLevel slog.Level
+
+	Pool       db.PoolConfig
+	Timeouts   api.RouteTimeouts
+	Server     api.ServerConfig
+	Tracing    tracing.Config
+	CORS       middleware.CORSConfig
+	RateLimits middleware.RateLimitConfig
+	Lockout    middleware.LockoutConfig
+	// External base URL of the API, used for the Swagger UI
+	PublicURL   string
+	Blob        blob.Config
+	Attachments blob.AttachmentConfig
+
+	// Print the effective settings and exit
+	PrintConfig bool
+	// Env file the settings were read from, empty if none
+	File string
+
+	settings []*setting
+}
+
+// A single setting, bound to a field of Config
+type setting struct {
+	env    string
+	flag   string
+	usage  string
+	set    func(string) error
+	get    func() string
+	source string
+}
+
+// Returns a Config with every setting at its default
+func Default() *Config {
+	c := &Config{
+		Env:         "dev",
+		Port:        8080,
+		LogLevel:    slog.LevelInfo,
+		Pool:        db.DefaultPoolConfig(),
+		Timeouts:    api.DefaultRouteTimeouts(),
+		Server:      api.DefaultServerConfig(),
+		Tracing:     tracing.DefaultConfig(),
+		CORS:        middleware.DefaultCORSConfig(),
+		RateLimits:  middleware.DefaultRateLimitConfig(),
+		Lockout:     middleware.DefaultLockoutConfig(),
+		Blob:        blob.DefaultConfig(),
+		Attachments: blob.DefaultAttachmentConfig(),
+	}
+	c.bind()
+	return c
+}
+
+// Declares every setting. Add new settings here
+func (c *Config) bind() {
+	c.settings = []*setting{
+		stringSetting("APP_ENV", "env", "Environment to serve app. Options: dev (default) | prod", &c.Env),
+		intSetting("APP_PORT", "port", "Port the API listens on", &c.Port),
+		{env: "DATABASE_URL", flag: "database-url", usage: "Postgres connection URL. Printed with the password masked",
+			set: func(v string) error { c.DatabaseURL = v; return nil },
+			get: func() string { return db.RedactURL(c.DatabaseURL) }},
+		boolSetting("DB_AUTO_MIGRATE", "auto-migrate", "Apply pending schema migrations on startup", &c.AutoMigrate),
+		{env: "LOG_LEVEL", flag: "log-level", usage: "Log level: debug, info, warn or error",
+			set: func(v string) (err error) { c.LogLevel, err = logging.ParseLevel(v); return err },
+			get: func() string { return c.LogLevel.String() }},
+
+		intSetting("DB_MAX_CONNS", "", "Maximum open database connections", &c.Pool.MaxConns),
+		intSetting("DB_WARMUP_CONNS", "", "Database connections opened at startup to warm up the pool", &c.Pool.WarmUpConns),
+		durationSetting("DB_MAX_CONN_LIFETIME", "Maximum time a database connection is reused", &c.Pool.MaxConnLifetime),
+		durationSetting("DB_MAX_CONN_IDLE_TIME", "Idle time before a database connection is closed", &c.Pool.MaxConnIdleTime),
+		stringSetting("DB_STATEMENT_CACHE_MODE", "", "Statement cache mode: prepare, describe or disabled", &c.Pool.StatementCacheMode),
+		intSetting("DB_STATEMENT_CACHE_CAPACITY", "", "Statements cached per database connection", &c.Pool.StatementCacheCapacity),
+		intSetting("DB_CONNECT_RETRIES", "", "Retries while waiting for the database at startup", &c.Pool.ConnectRetries),
+		durationSetting("DB_CONNECT_BACKOFF", "Wait before the first connect retry, doubled after each attempt", &c.Pool.ConnectBackoff),
+		durationSetting("DB_CONNECT_MAX_BACKOFF", "Maximum wait between connect retries", &c.Pool.ConnectMaxBackoff),
+
+		durationSetting("API_REQUEST_TIMEOUT", "Deadline of most API requests", &c.Timeouts.Default),
+		durationSetting("API_LONG_REQUEST_TIMEOUT", "Deadline of long-running API requests", &c.Timeouts.Long),
+		durationSetting("HTTP_READ_HEADER_TIMEOUT", "Time allowed to read request headers", &c.Server.ReadHeaderTimeout),
+		durationSetting("HTTP_READ_TIMEOUT", "Time allowed to read a whole request", &c.Server.ReadTimeout),
+		durationSetting("HTTP_WRITE_TIMEOUT", "Time allowed to write a response", &c.Server.WriteTimeout),
+		durationSetting("HTTP_IDLE_TIMEOUT", "Keep-alive idle time", &c.Server.IdleTimeout),
+		durationSetting("HTTP_SHUTDOWN_TIMEOUT", "Time in-flight requests get to finish on shutdown", &c.Server.ShutdownTimeout),
+		stringSetting("TLS_CERT_FILE", "tls-cert", "TLS certificate file. Serves HTTPS when set with TLS_KEY_FILE", &c.Server.TLSCertFile),
+		stringSetting("TLS_KEY_FILE", "tls-key", "TLS private key file", &c.Server.TLSKeyFile),
+
+		listSetting("CORS_ALLOWED_ORIGINS", "Comma-separated origins allowed to call the API. Empty allows none", &c.CORS.AllowedOrigins),
+		listSetting("CORS_ALLOWED_METHODS", "Comma-separated methods allowed on cross-origin requests", &c.CORS.AllowedMethods),
+		listSetting("CORS_ALLOWED_HEADERS", "Comma-separated request headers allowed on cross-origin requests", &c.CORS.AllowedHeaders),
+		listSetting("CORS_EXPOSED_HEADERS", "Comma-separated response headers readable by browser code", &c.CORS.ExposedHeaders),
+		boolSetting("CORS_ALLOW_CREDENTIALS", "", "Allow cookies and Authorization headers on cross-origin requests", &c.CORS.AllowCredentials),
+		durationSetting("CORS_MAX_AGE", "How long browsers may cache a preflight response", &c.CORS.MaxAge),
+		boolSetting("RATE_LIMIT_ENABLED", "", "Limit requests per client with token buckets", &c.RateLimits.Enabled),
+		boolSetting("RATE_LIMIT_TRUST_PROXY", "", "Identify clients by X-Forwarded-For. Only enable behind a reverse proxy", &c.RateLimits.TrustProxy),
+		intSetting("RATE_LIMIT_READ_PER_MINUTE", "", "Read requests per client per minute. 0 disables", &c.RateLimits.Read.PerMinute),
+		intSetting("RATE_LIMIT_READ_BURST", "", "Read requests allowed in a burst", &c.RateLimits.Read.Burst),
+		intSetting("RATE_LIMIT_WRITE_PER_MINUTE", "", "Write requests per client per minute. 0 disables", &c.RateLimits.Write.PerMinute),
+		intSetting("RATE_LIMIT_WRITE_BURST", "", "Write requests allowed in a burst", &c.RateLimits.Write.Burst),
+		intSetting("RATE_LIMIT_LONG_PER_MINUTE", "", "Requests to long-running endpoints per client per minute. 0 disables", &c.RateLimits.Long.PerMinute),
+		intSetting("RATE_LIMIT_LONG_BURST", "", "Requests to long-running endpoints allowed in a burst", &c.RateLimits.Long.Burst),
+		intSetting("LOGIN_MAX_FAILURES", "", "Failed sign ins per client before it is locked out. 0 disables", &c.Lockout.MaxFailures),
+		durationSetting("LOGIN_FAILURE_WINDOW", "Period failed sign ins are counted over", &c.Lockout.Window),
+		durationSetting("LOGIN_LOCKOUT_DURATION", "How long a client is locked out after too many failed sign ins", &c.Lockout.Duration),
+		stringSetting("PUBLIC_URL", "public-url", "External base URL of the API, e.g. https://api.example.com. Empty uses relative URLs", &c.PublicURL),
+
+		stringSetting("ATTACHMENTS_BACKEND", "", "Where attachment contents are stored: local or s3", &c.Blob.Backend),
+		stringSetting("ATTACHMENTS_DIR", "", "Directory of the local attachment backend", &c.Blob.Dir),
+		sizeSetting("ATTACHMENTS_MAX_SIZE", "Largest attachment accepted, e.g. 10MB", &c.Attachments.MaxSize),
+		listSetting("ATTACHMENTS_ALLOWED_TYPES", "Comma-separated content types accepted as attachments", &c.Attachments.AllowedTypes),
+		stringSetting("S3_ENDPOINT", "", "Endpoint URL of the S3-compatible attachment backend", &c.Blob.S3.Endpoint),
+		stringSetting("S3_BUCKET", "", "Bucket for attachments", &c.Blob.S3.Bucket),
+		stringSetting("S3_REGION", "", "Region of the bucket", &c.Blob.S3.Region),
+		stringSetting("S3_ACCESS_KEY_ID", "", "S3 access key. Empty uses AWS_* variables or the instance role", &c.Blob.S3.AccessKeyID),
+		{env: "S3_SECRET_ACCESS_KEY", usage: "S3 secret key. Printed masked",
+			set: func(v string) error { c.Blob.S3.SecretAccessKey = v; return nil },
+			get: func() string { return maskSecret(c.Blob.S3.SecretAccessKey) }},
+		boolSetting("S3_PATH_STYLE", "", "Use path-style bucket URLs, as most self-hosted S3 servers need", &c.Blob.S3.PathStyle),
+
+		stringSetting("TRACING_EXPORTER", "", "Trace exporter: none, stdout or otlp", &c.Tracing.Exporter),
+		stringSetting("OTEL_SERVICE_NAME", "", "Service name in traces", &c.Tracing.ServiceName),
+		floatSetting("TRACING_SAMPLE_RATIO", "Fraction of new traces to record", &c.Tracing.SampleRatio),
+	}
+}
+
+// Loads the configuration from flags in args, the environment and the env file, then validates it
+func Load(args []string) (*Config, error) {
+	c := Default()
+
+	fs := flag.NewFlagSet("tt-db", flag.ContinueOnError)
+	flagValues := map[string]*string{}
+	for _, s := range c.settings {
+		if s.flag != "" {
+			flagValues[s.flag] = fs.String(s.flag, "", fmt.Sprintf("%s (env %s)", s.usage, s.env))
+		}
+	}
+	file := fs.String("config-file", "", "Env file to read settings from (default <env>.env, ignored if missing)")
+	fs.BoolVar(&c.PrintConfig, "print-config", false, "Print the effective settings with secrets masked and exit")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	setFlags := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { setFlags[f.Name] = true })
+
+	// The environment name picks the default env file, so resolve it first
+	env := c.Env
+	if value, ok := os.LookupEnv("APP_ENV"); ok && value != "" {
+		env = value
+	}
+	if setFlags["env"] {
+		env = *flagValues["env"]
+	}
+
+	fileValues := map[string]string{}
+	c.File = *file
+	if c.File == "" {
+		c.File = env + ".env"
+	}
+	values, err := godotenv.Read(c.File)
+	switch {
+	case err == nil:
+		fileValues = values
+	case *file == "" && errors.Is(err, os.ErrNotExist):
+		// The default env file is optional, e.g. in containers that inject env directly
+		c.File = ""
+	default:
+		return nil, fmt.Errorf("reading config file %s: %w", c.File, err)
+	}
+
+	var errs []error
+	for _, s := range c.settings {
+		value, source := "", SourceDefault
+		if v, ok := fileValues[s.env]; ok {
+			value, source = v, SourceFile
+		}
+		if v, ok := os.LookupEnv(s.env); ok {
+			value, source = v, SourceEnv
+		}
+		if s.flag != "" && setFlags[s.flag] {
+			value, source = *flagValues[s.flag], SourceFlag
+		}
+		// An empty value clears the sources below it, leaving the default
+		if value == "" {
+			continue
+		}
+		if err := s.set(value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.name(), err))
+			continue
+		}
+		s.source = source
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return c, c.Validate()
+}
+
+// Checks that the settings are usable, reporting every problem at once
+func (c *Config) Validate() error {
+	var errs []error
+	if c.DatabaseURL == "" {
+		errs = append(errs, errors.New("DATABASE_URL is required"))
+	} else if u, err := url.Parse(c.DatabaseURL); err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") || u.Host == "" {
+		// The value isn't echoed: it may hold a password url.Parse couldn't find
+		errs = append(errs, errors.New("DATABASE_URL must be a postgres:// URL with a host such as postgres://user:password@db:5432/ttdb"))
+	}
+	if c.Port < 1 || c.Port > 65535 {
+		errs = append(errs, fmt.Errorf("APP_PORT must be between 1 and 65535, got %d", c.Port))
+	}
+	if err := c.Pool.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if c.Timeouts.Default <= 0 || c.Timeouts.Long <= 0 {
+		errs = append(errs, errors.New("API_REQUEST_TIMEOUT and API_LONG_REQUEST_TIMEOUT must be positive"))
+	}
+	if c.Server.WriteTimeout > 0 && c.Server.WriteTimeout <= c.Timeouts.Long {
+		errs = append(errs, fmt.Errorf("HTTP_WRITE_TIMEOUT (%s) must be longer than API_LONG_REQUEST_TIMEOUT (%s)", c.Server.WriteTimeout, c.Timeouts.Long))
+	}
+	if (c.Server.TLSCertFile == "") != (c.Server.TLSKeyFile == "") {
+		errs = append(errs, errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together"))
+	}
+	if err := c.CORS.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.RateLimits.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.Lockout.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if c.PublicURL != "" {
+		if u, err := url.Parse(c.PublicURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("PUBLIC_URL must be an http(s) URL such as https://api.example.com, got %q", c.PublicURL))
+		}
+	}
+	if err := c.Blob.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.Attachments.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.Tracing.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// Writes every setting as NAME=value with where it came from. Secrets are masked
+func (c *Config) Print(w io.Writer) {
+	if c.File != "" {
+		fmt.Fprintf(w, "# config file: %s\n", c.File)
+	}
+	for _, s := range c.settings {
+		source := s.source
+		if source == "" {
+			source = SourceDefault
+		}
+		fmt.Fprintf(w, "%s=%s  # %s\n", s.env, s.get(), source)
+	}
+}
+
+// Name used in errors: the env var, and the flag if there is one
+func (s *setting) name() string {
+	if s.flag != "" {
+		return fmt.Sprintf("%s (-%s)", s.env, s.flag)
+	}
+	return s.env
+}
+
+// Hides a secret, showing only whether it is set
+func maskSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return "xxxxx"
+}
+
+func stringSetting(env string, flagName string, usage string, target *string) *setting {
+	return &setting{env: env, flag: flagName, usage: usage,
+		set: func(v string) error { *target = v; return nil },
+		get: func() string { return *target }}
+}
+
+func intSetting(env string, flagName string, usage string, target *int) *setting {
+	return &setting{env: env, flag: flagName, usage: usage,
+		set: func(v string) error {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return fmt.Errorf("must be an integer, got %q", v)
+			}
+			*target = n
+			return nil
+		},
+		get: func() string { return strconv.Itoa(*target) }}
+}
+
+func boolSetting(env string, flagName string, usage string, target *bool) *setting {
+	return &setting{env: env, flag: flagName, usage: usage,
+		set: func(v string) error {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("must be true or false, got %q", v)
+			}
+			*target = b
+			return nil
+		},
+		get: func() string { return strconv.FormatBool(*target) }}
+}
+
+func durationSetting(env string, usage string, target *time.Duration) *setting {
+	return &setting{env: env, usage: usage,
+		set: func(v string) error {
+			d, err := time.ParseDuration(v)
+			if err != nil || d < 0 {
+				return fmt.Errorf("must be a duration such as 30s or 1h, got %q", v)
+			}
+			*target = d
+			return nil
+		},
+		get: func() string { return target.String() }}
+}
+
+// A comma-separated list. Blank items are dropped
+func listSetting(env string, usage string, target *[]string) *setting {
+	return &setting{env: env, usage: usage,
+		set: func(v string) error {
+			*target = nil
+			for _, item := range strings.Split(v, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					*target = append(*target, item)
+				}
+			}
+			return nil
+		},
+		get: func() string { return strings.Join(*target, ",") }}
+}
+
+// Multipliers of the units accepted by sizeSetting, largest first
+var sizeUnits = []struct {
+	suffix string
+	bytes  int64
+}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}}
+
+// A size in bytes, written as a number with an optional KB, MB or GB suffix (powers of 1024)
+func sizeSetting(env string, usage string, target *int64) *setting {
+	return &setting{env: env, usage: usage,
+		set: func(v string) error {
+			number, multiplier := strings.ToUpper(strings.TrimSpace(v)), int64(1)
+			for _, unit := range sizeUnits {
+				if strings.HasSuffix(number, unit.suffix) {
+					number, multiplier = strings.TrimSpace(strings.TrimSuffix(number, unit.suffix)), unit.bytes
+					break
+				}
+			}
+			n, err := strconv.ParseInt(number, 10, 64)
+			if err != nil || n < 0 {
+				return fmt.Errorf("must be a size such as 512KB or 10MB, got %q", v)
+			}
+			*target = n * multiplier
+			return nil
+		},
+		get: func() string {
+			for _, unit := range sizeUnits {
+				if *target != 0 && *target%unit.bytes == 0 {
+					return strconv.FormatInt(*target/unit.bytes, 10) + unit.suffix
+				}
+			}
+			return "0"
+		}}
+}
+
+func floatSetting(env string, usage string, target *float64) *setting {
+	return &setting{env: env, usage: usage,
+		set: func(v string) error {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return fmt.Errorf("must be a number, got %q", v)
+			}
+			*target = f
+			return nil
+		},
+		get: func() string { return strconv.FormatFloat(*target, 'g', -1, 64) }}
+}
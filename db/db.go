@@ -1,50 +1,127 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
+	"net/url"
+	"time"
 
-	_ "github.com/jackc/pgx/v4/stdlib"
+	"github.com/jackc/pgx/v4/stdlib"
 )
 
+// Timeout for each ping while waiting for the database to come up
+const pingTimeout = 5 * time.Second
+
 type PostgresDB struct {
 	Conn        *sql.DB
 	DatabaseURL string
+	Pool        PoolConfig
 }
 
 // Constructor for PostgresDB struct
 func NewPostgresDB(databaseURL string) *PostgresDB {
 	return &PostgresDB{
 		DatabaseURL: databaseURL,
+		Pool:        DefaultPoolConfig(),
 	}
 }
 
 // Estabilishes connection with a postgreSQL database defined in the environment variable "DATABASE_URL".
-func (db *PostgresDB) Connect() {
-    log.Println(fmt.Sprintf("Connecting to Database URL %s", db.DatabaseURL))
+// Retries with exponential backoff while the database is unreachable, so the API survives Postgres starting late.
+func (db *PostgresDB) Connect() error {
+	log.Printf("Connecting to Database URL %s", RedactURL(db.DatabaseURL))
 
-	// Establish connection to postgres DB
-	conn, err := sql.Open("pgx", db.DatabaseURL)
+	connConfig, err := db.Pool.connConfig(db.DatabaseURL)
 	if err != nil {
-        log.Fatalf("Unable to connect to database: %v", err)
+		return fmt.Errorf("invalid database URL: %w", err)
 	}
 
-	err = testDB(conn)
-	if err != nil {
-        log.Fatalf("Unable to ping database: %v", err)
+	// Establish connection pool to postgres DB
+	conn := stdlib.OpenDB(*connConfig)
+	conn.SetMaxOpenConns(db.Pool.MaxConns)
+	// Keep the warmed up connections when they go back to the pool
+	conn.SetMaxIdleConns(max(db.Pool.WarmUpConns, 2))
+	conn.SetConnMaxLifetime(db.Pool.MaxConnLifetime)
+	conn.SetConnMaxIdleTime(db.Pool.MaxConnIdleTime)
+
+	for attempt := 0; ; attempt++ {
+		err = testDB(conn)
+		if err == nil {
+			break
+		}
+		if attempt >= db.Pool.ConnectRetries {
+			conn.Close()
+			return fmt.Errorf("unable to ping database after %d attempts: %w", attempt+1, err)
+		}
+		wait := db.Pool.backoff(attempt + 1)
+		log.Printf("Database not ready (%v). Retrying in %s...", err, wait)
+		time.Sleep(wait)
+	}
+
+	if err := warmUp(conn, db.Pool.WarmUpConns); err != nil {
+		log.Printf("Unable to open %d idle connections: %v", db.Pool.WarmUpConns, err)
 	}
 
 	log.Println("Connected to Database successfully!")
 	db.Conn = conn
+	return nil
+}
+
+// Closes all connections of the pool
+func (db *PostgresDB) Close() error {
+	if db.Conn == nil {
+		return nil
+	}
+	log.Println("Closing database connections...")
+	return db.Conn.Close()
+}
+
+// Returns statistics of the connection pool
+func (db *PostgresDB) Stats() sql.DBStats {
+	return db.Conn.Stats()
+}
+
+// Hides the password of a database URL so it can be logged
+func RedactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.User == nil {
+		return raw
+	}
+	return u.Redacted()
 }
 
 // Test connection with database by sending a ping
 func testDB(conn *sql.DB) error {
-	err := conn.Ping()
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+
+	err := conn.PingContext(ctx)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// Opens n connections and returns them to the pool so the first requests don't pay for the handshake
+func warmUp(conn *sql.DB, n int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+
+	conns := make([]*sql.Conn, 0, n)
+	defer func() {
+		for _, c := range conns {
+			c.Close()
+		}
+	}()
+	for i := 0; i < n; i++ {
+		c, err := conn.Conn(ctx)
+		if err != nil {
+			return err
+		}
+		conns = append(conns, c)
+	}
+	return nil
+}
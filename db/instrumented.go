@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracer for database spans. Spans are only recorded once tracing.Setup installs a provider
+var tracer = otel.Tracer("github.com/pacific-theta-tau/tt-db/db")
+
+// Receives the duration and error of every query, labelled with the function that made it
+type QueryObserver func(method string, duration time.Duration, err error)
+
+// DB wraps *sql.DB and reports every QueryContext, QueryRowContext and ExecContext call
+// to an observer and as an OpenTelemetry span. Queries inside transactions are not reported individually
+type DB struct {
+	*sql.DB
+	observe QueryObserver
+}
+
+// Wraps conn so its queries are reported to observe
+func Instrument(conn *sql.DB, observe QueryObserver) *DB {
+	return &DB{DB: conn, observe: observe}
+}
+
+func (db *DB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, done := db.start(ctx, callerName(), query)
+	rows, err := db.DB.QueryContext(ctx, query, args...)
+	done(err)
+	return rows, err
+}
+
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, done := db.start(ctx, callerName(), query)
+	row := db.DB.QueryRowContext(ctx, query, args...)
+	done(row.Err())
+	return row
+}
+
+func (db *DB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, done := db.start(ctx, callerName(), query)
+	result, err := db.DB.ExecContext(ctx, query, args...)
+	done(err)
+	return result, err
+}
+
+// Starts a span for a query made by method. The returned function ends the span
+// and reports the query's duration and error
+func (db *DB) start(ctx context.Context, method string, query string) (context.Context, func(error)) {
+	start := time.Now()
+	ctx, span := tracer.Start(ctx, method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBQueryText(query),
+		),
+	)
+	return ctx, func(err error) {
+		if err != nil && err != sql.ErrNoRows {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+		db.observe(method, time.Since(start), err)
+	}
+}
+
+// Names Go gives closures, e.g. the func1 and 2 in GetAllBrothers.func1.2
+var closureName = regexp.MustCompile(`^(func)?\d+$`)
+
+// Name of the function that called the DB method, without package and receiver
+// (e.g. "GetAllBrothers"). Closures are reported as their enclosing function
+func callerName() string {
+	pc, _, _, ok := runtime.Caller(2)
+	if !ok {
+		return "unknown"
+	}
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return "unknown"
+	}
+	name := fn.Name()
+	name = name[strings.LastIndex(name, "/")+1:]
+	parts := strings.Split(name, ".")
+	// Drop the package, the receiver and closure suffixes like func1
+	for i := len(parts) - 1; i > 0; i-- {
+		if !closureName.MatchString(parts[i]) {
+			return parts[i]
+		}
+	}
+	return name
+}
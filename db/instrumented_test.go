@@ -0,0 +1,28 @@
+package db
+
+import "testing"
+
+func reportCaller() string {
+	return callerNameFromHere()
+}
+
+// Mirrors how DB methods call callerName, one frame below the function being labelled
+func callerNameFromHere() string {
+	return callerName()
+}
+
+func TestCallerName(t *testing.T) {
+	if name := reportCaller(); name != "reportCaller" {
+		t.Errorf("Expected reportCaller. Got %s", name)
+	}
+
+	closure := func() string { return reportCallerFromClosure() }
+	if name := closure(); name != "reportCallerFromClosure" {
+		t.Errorf("Expected reportCallerFromClosure. Got %s", name)
+	}
+}
+
+func reportCallerFromClosure() string {
+	inner := func() string { return callerNameFromHere() }
+	return inner()
+}
@@ -0,0 +1,190 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// SQL migrations applied on top of the schema in db/scripts/init.sql.
+// Files are named `<version>_<name>.up.sql` with a matching `.down.sql` to revert them
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(\w+)\.up\.sql$`)
+
+// Migration is a single versioned schema change
+type Migration struct {
+	Version int
+	Name    string
+	SQL     string
+}
+
+// Load all versioned migrations sorted by version
+func loadMigrations() ([]Migration, error) {
+	entries, err := migrationFiles.ReadDir("migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []Migration
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		version, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+		content, err := migrationFiles.ReadFile(path.Join("migrations", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, Migration{Version: version, Name: match[2], SQL: string(content)})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Highest migration version embedded in this build
+func LatestSchemaVersion() (int, error) {
+	migrations, err := loadMigrations()
+	if err != nil || len(migrations) == 0 {
+		return 0, err
+	}
+	return migrations[len(migrations)-1].Version, nil
+}
+
+// Create the table that tracks applied migrations if it doesn't exist yet
+func ensureMigrationsTable(ctx context.Context, conn *sql.DB) error {
+	_, err := conn.ExecContext(ctx, `
+	CREATE TABLE IF NOT EXISTS schema_migrations(
+		version INT PRIMARY KEY,
+		name TEXT NOT NULL,
+		appliedAt TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`)
+	return err
+}
+
+// Get the versions of all applied migrations
+func appliedVersions(ctx context.Context, conn *sql.DB) (map[int]bool, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]bool{}
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		applied[version] = true
+	}
+	return applied, rows.Err()
+}
+
+// Migrate applies every pending migration in version order. Each migration runs in its own
+// transaction, so a failing migration leaves the schema at the previous version
+func (db *PostgresDB) Migrate(ctx context.Context) error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return fmt.Errorf("loading migrations: %w", err)
+	}
+	if err := ensureMigrationsTable(ctx, db.Conn); err != nil {
+		return fmt.Errorf("creating schema_migrations table: %w", err)
+	}
+	applied, err := appliedVersions(ctx, db.Conn)
+	if err != nil {
+		return fmt.Errorf("reading applied migrations: %w", err)
+	}
+
+	for _, migration := range migrations {
+		if applied[migration.Version] {
+			continue
+		}
+		log.Printf("Applying migration %06d_%s", migration.Version, migration.Name)
+		if err := applyMigration(ctx, db.Conn, migration); err != nil {
+			return fmt.Errorf("migration %06d_%s: %w", migration.Version, migration.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Run a single migration and record it in schema_migrations
+func applyMigration(ctx context.Context, conn *sql.DB, migration Migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, migration.SQL); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(
+		ctx,
+		"INSERT INTO schema_migrations (version, name) VALUES ($1, $2)",
+		migration.Version,
+		migration.Name,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// SchemaStatus compares the migrations applied to a database with the ones embedded in this build
+type SchemaStatus struct {
+	// Highest applied migration version, 0 if none
+	Version int
+	// Highest migration version known to this build
+	Latest int
+	// Migrations not applied yet
+	Pending []Migration
+}
+
+// Get the applied schema version and pending migrations without changing the database
+func GetSchemaStatus(ctx context.Context, conn *sql.DB) (SchemaStatus, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return SchemaStatus{}, fmt.Errorf("loading migrations: %w", err)
+	}
+
+	applied := map[int]bool{}
+	var exists bool
+	err = conn.QueryRowContext(ctx, "SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists)
+	if err != nil {
+		return SchemaStatus{}, err
+	}
+	if exists {
+		applied, err = appliedVersions(ctx, conn)
+		if err != nil {
+			return SchemaStatus{}, fmt.Errorf("reading applied migrations: %w", err)
+		}
+	}
+
+	var status SchemaStatus
+	for version := range applied {
+		status.Version = max(status.Version, version)
+	}
+	for _, migration := range migrations {
+		status.Latest = max(status.Latest, migration.Version)
+		if !applied[migration.Version] {
+			status.Pending = append(status.Pending, migration)
+		}
+	}
+	return status, nil
+}
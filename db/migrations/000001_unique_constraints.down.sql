@@ -0,0 +1,3 @@
+ALTER TABLE eventsCategory DROP CONSTRAINT IF EXISTS eventscategory_categoryname_key;
+ALTER TABLE semester DROP CONSTRAINT IF EXISTS semester_semesterlabel_key;
+ALTER TABLE brothers DROP CONSTRAINT IF EXISTS brothers_rollcall_key;
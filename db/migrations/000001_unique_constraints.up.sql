@@ -0,0 +1,35 @@
+-- Unique constraints on values used to look rows up by handlers
+-- (RemoveBrother, CreateAttendanceRecordForEvent, GetSemesterIdBySemesterLabel, CreateEvent).
+
+-- Pre-flight: abort with a report of every duplicate value that would violate the new constraints.
+-- The same report can be generated without migrating with db/scripts/report_duplicates.sql
+DO $$
+DECLARE
+    report TEXT;
+BEGIN
+    SELECT string_agg(line, E'\n') INTO report
+    FROM (
+        SELECT format('brothers.rollCall %s is used by brotherIDs %s', rollCall, string_agg(brotherID::TEXT, ', ' ORDER BY brotherID)) AS line
+        FROM brothers
+        GROUP BY rollCall
+        HAVING COUNT(*) > 1
+        UNION ALL
+        SELECT format('semester.semesterLabel %L is used by semesterIDs %s', semesterLabel, string_agg(semesterID::TEXT, ', ' ORDER BY semesterID))
+        FROM semester
+        GROUP BY semesterLabel
+        HAVING COUNT(*) > 1
+        UNION ALL
+        SELECT format('eventsCategory.categoryName %L is used by categoryIDs %s', categoryName, string_agg(categoryID::TEXT, ', ' ORDER BY categoryID))
+        FROM eventsCategory
+        GROUP BY categoryName
+        HAVING COUNT(*) > 1
+    ) duplicates;
+
+    IF report IS NOT NULL THEN
+        RAISE EXCEPTION E'Cannot add unique constraints. Resolve these duplicates first:\n%', report;
+    END IF;
+END $$;
+
+ALTER TABLE brothers ADD CONSTRAINT brothers_rollcall_key UNIQUE (rollCall);
+ALTER TABLE semester ADD CONSTRAINT semester_semesterlabel_key UNIQUE (semesterLabel);
+ALTER TABLE eventsCategory ADD CONSTRAINT eventscategory_categoryname_key UNIQUE (categoryName);
@@ -0,0 +1,12 @@
+-- History of duplicate brother records merged into a surviving record.
+-- The merged brother row is deleted, so a snapshot of it is kept in mergedBrother
+CREATE TABLE IF NOT EXISTS brotherMerges(
+    mergeID SERIAL PRIMARY KEY,
+    survivorID INT REFERENCES brothers(brotherID) ON DELETE CASCADE ON UPDATE CASCADE,
+    mergedBrotherID INT NOT NULL,
+    mergedBrother JSONB NOT NULL,
+    attendanceMoved INT NOT NULL DEFAULT 0,
+    statusesMoved INT NOT NULL DEFAULT 0,
+    conflictsResolved INT NOT NULL DEFAULT 0,
+    mergedAt TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,9 @@
+-- Accounts for people who administer the database. Created with `ttdb create-user`.
+-- Passwords are stored as bcrypt hashes
+CREATE TABLE IF NOT EXISTS users(
+    userID SERIAL PRIMARY KEY,
+    email TEXT NOT NULL UNIQUE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('admin', 'officer', 'member')),
+    passwordHash TEXT NOT NULL,
+    createdAt TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS brotherNoteRevisions;
+DROP TABLE IF EXISTS brotherNotes;
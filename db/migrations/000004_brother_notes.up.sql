@@ -0,0 +1,24 @@
+-- Notes officers keep on brother records (reasons for Inactive, co-op company, contact attempts...).
+-- Authors are stored as text because users are not part of backups.
+-- Editing a note copies its previous version into brotherNoteRevisions
+CREATE TABLE IF NOT EXISTS brotherNotes(
+    noteID SERIAL PRIMARY KEY,
+    brotherID INT NOT NULL REFERENCES brothers(brotherID) ON DELETE CASCADE ON UPDATE CASCADE,
+    body TEXT NOT NULL CHECK (body <> ''),
+    visibility VARCHAR(20) NOT NULL DEFAULT 'officers' CHECK (visibility IN ('officers', 'all')),
+    author TEXT NOT NULL,
+    createdAt TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updatedBy TEXT NOT NULL,
+    updatedAt TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS brothernotes_brotherid_idx ON brotherNotes(brotherID);
+
+CREATE TABLE IF NOT EXISTS brotherNoteRevisions(
+    revisionID SERIAL PRIMARY KEY,
+    noteID INT NOT NULL REFERENCES brotherNotes(noteID) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    visibility VARCHAR(20) NOT NULL,
+    editedBy TEXT NOT NULL,
+    editedAt TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS brothernoterevisions_noteid_idx ON brotherNoteRevisions(noteID);
@@ -0,0 +1,17 @@
+-- Files attached to a brother (headshots, resumes) or an event (sign-in sheets).
+-- Contents live in the blob store under blobKey; this table only has their metadata
+CREATE TABLE IF NOT EXISTS attachments(
+    attachmentID SERIAL PRIMARY KEY,
+    brotherID INT REFERENCES brothers(brotherID) ON DELETE CASCADE ON UPDATE CASCADE,
+    eventID INT REFERENCES events(eventID) ON DELETE CASCADE ON UPDATE CASCADE,
+    fileName TEXT NOT NULL,
+    contentType TEXT NOT NULL,
+    size BIGINT NOT NULL,
+    blobKey TEXT NOT NULL UNIQUE,
+    uploadedBy TEXT NOT NULL DEFAULT '',
+    createdAt TIMESTAMPTZ NOT NULL DEFAULT now(),
+    -- Every attachment belongs to exactly one brother or event
+    CONSTRAINT attachments_owner_check CHECK ((brotherID IS NULL) <> (eventID IS NULL))
+);
+CREATE INDEX IF NOT EXISTS attachments_brotherid_idx ON attachments(brotherID);
+CREATE INDEX IF NOT EXISTS attachments_eventid_idx ON attachments(eventID);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS brothers_customfields_idx;
+ALTER TABLE brothers DROP COLUMN IF EXISTS customFields;
+DROP TABLE IF EXISTS customFields;
@@ -0,0 +1,16 @@
+-- Fields admins define to track chapter-specific data (t-shirt size, LinkedIn, graduation year...)
+-- without schema changes. Values are stored per brother in brothers.customFields, keyed by name
+CREATE TABLE IF NOT EXISTS customFields(
+    fieldID SERIAL PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE CHECK (name ~ '^[A-Za-z][A-Za-z0-9_]*$'),
+    label TEXT NOT NULL,
+    type VARCHAR(10) NOT NULL CHECK (type IN ('text', 'number', 'date', 'enum')),
+    -- Allowed values of enum fields
+    options JSONB NOT NULL DEFAULT '[]',
+    required BOOLEAN NOT NULL DEFAULT false,
+    createdAt TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+ALTER TABLE brothers ADD COLUMN IF NOT EXISTS customFields JSONB NOT NULL DEFAULT '{}';
+-- Serves containment filters such as customFields @> '{"tshirtSize": "M"}'
+CREATE INDEX IF NOT EXISTS brothers_customfields_idx ON brothers USING GIN (customFields);
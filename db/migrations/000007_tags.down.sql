@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS eventTags;
+DROP TABLE IF EXISTS brotherTags;
+DROP TABLE IF EXISTS tags;
@@ -0,0 +1,23 @@
+-- Ad-hoc groupings of brothers and events ("Executive Board 2024", "Rush Committee", "Mandatory").
+-- Brothers and events share the same tags
+CREATE TABLE IF NOT EXISTS tags(
+    tagID SERIAL PRIMARY KEY,
+    name VARCHAR(50) NOT NULL CHECK (btrim(name) <> ''),
+    createdAt TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+-- Names are unique regardless of case, and ?tag= filters match them regardless of case
+CREATE UNIQUE INDEX IF NOT EXISTS tags_name_key ON tags(lower(name));
+
+CREATE TABLE IF NOT EXISTS brotherTags(
+    brotherID INT NOT NULL REFERENCES brothers(brotherID) ON DELETE CASCADE ON UPDATE CASCADE,
+    tagID INT NOT NULL REFERENCES tags(tagID) ON DELETE CASCADE,
+    PRIMARY KEY (brotherID, tagID)
+);
+CREATE INDEX IF NOT EXISTS brothertags_tagid_idx ON brotherTags(tagID);
+
+CREATE TABLE IF NOT EXISTS eventTags(
+    eventID INT NOT NULL REFERENCES events(eventID) ON DELETE CASCADE ON UPDATE CASCADE,
+    tagID INT NOT NULL REFERENCES tags(tagID) ON DELETE CASCADE,
+    PRIMARY KEY (eventID, tagID)
+);
+CREATE INDEX IF NOT EXISTS eventtags_tagid_idx ON eventTags(tagID);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS positionTerms;
+DROP TABLE IF EXISTS positions;
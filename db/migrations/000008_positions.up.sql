@@ -0,0 +1,28 @@
+-- Chapter offices (Regent, Vice Regent, Scribe, Treasurer...) and committees (Rush, Philanthropy...)
+CREATE TABLE IF NOT EXISTS positions(
+    positionID SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL CHECK (btrim(name) <> ''),
+    kind VARCHAR(20) NOT NULL DEFAULT 'office' CHECK (kind IN ('office', 'committee')),
+    -- User role `ttdb sync-roles` gives holders while they serve. Empty grants none
+    role VARCHAR(20) NOT NULL DEFAULT '' CHECK (role IN ('', 'admin', 'officer')),
+    createdAt TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE UNIQUE INDEX IF NOT EXISTS positions_name_key ON positions(lower(name));
+
+-- A brother holding a position from startSemester to endSemester, both included.
+-- Semesters are labels like "Fall 2024"; startsOn and endsBefore are their dates so terms
+-- can be compared. An empty endSemester (NULL endsBefore) is a term that hasn't ended
+CREATE TABLE IF NOT EXISTS positionTerms(
+    termID SERIAL PRIMARY KEY,
+    positionID INT NOT NULL REFERENCES positions(positionID) ON DELETE CASCADE,
+    brotherID INT NOT NULL REFERENCES brothers(brotherID) ON DELETE CASCADE ON UPDATE CASCADE,
+    -- Role within the position, e.g. "Chair" of a committee
+    title VARCHAR(50) NOT NULL DEFAULT '',
+    startSemester VARCHAR(20) NOT NULL,
+    startsOn DATE NOT NULL,
+    endSemester VARCHAR(20) NOT NULL DEFAULT '',
+    endsBefore DATE,
+    CONSTRAINT positionterms_range_check CHECK (endsBefore IS NULL OR endsBefore > startsOn)
+);
+CREATE INDEX IF NOT EXISTS positionterms_positionid_idx ON positionTerms(positionID);
+CREATE INDEX IF NOT EXISTS positionterms_brotherid_idx ON positionTerms(brotherID);
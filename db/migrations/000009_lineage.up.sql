@@ -0,0 +1,7 @@
+-- Big/little relationships. A brother has at most one big; the application rejects links that would form a cycle
+CREATE TABLE IF NOT EXISTS lineage(
+    littleID INT PRIMARY KEY REFERENCES brothers(brotherID) ON DELETE CASCADE ON UPDATE CASCADE,
+    bigID INT NOT NULL REFERENCES brothers(brotherID) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT lineage_self_check CHECK (littleID <> bigID)
+);
+CREATE INDEX IF NOT EXISTS lineage_bigid_idx ON lineage(bigID);
@@ -0,0 +1,2 @@
+ALTER TABLE brothers DROP COLUMN IF EXISTS classID;
+DROP TABLE IF EXISTS pledgeClasses;
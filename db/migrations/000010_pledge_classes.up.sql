@@ -0,0 +1,31 @@
+-- Pledge classes, named with Greek letters ("Omicron", "Alpha Beta"). Semester is a label like "Fall 2024",
+-- empty for classes created from the old free-text className. Roll calls of members must fall in the range, if set
+CREATE TABLE IF NOT EXISTS pledgeClasses(
+    classID SERIAL PRIMARY KEY,
+    name VARCHAR(50) NOT NULL CHECK (btrim(name) <> ''),
+    semester VARCHAR(20) NOT NULL DEFAULT '',
+    initiationDate DATE,
+    firstRollCall INT,
+    lastRollCall INT,
+    createdAt TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT pledgeclasses_rollcall_check CHECK (
+        (firstRollCall IS NULL AND lastRollCall IS NULL) OR (firstRollCall >= 1 AND lastRollCall >= firstRollCall)
+    )
+);
+CREATE UNIQUE INDEX IF NOT EXISTS pledgeclasses_name_key ON pledgeClasses(lower(name));
+
+-- brothers.className is kept as a copy of the class name for existing readers
+ALTER TABLE brothers ADD COLUMN IF NOT EXISTS classID INT
+    CONSTRAINT brothers_classid_fkey REFERENCES pledgeClasses(classID) ON DELETE RESTRICT;
+CREATE INDEX IF NOT EXISTS brothers_classid_idx ON brothers(classID);
+
+-- One class per distinct class name, regardless of case and surrounding spaces
+INSERT INTO pledgeClasses (name)
+SELECT min(btrim(className)) FROM brothers
+WHERE btrim(COALESCE(className, '')) <> ''
+GROUP BY lower(btrim(className))
+ON CONFLICT DO NOTHING;
+
+UPDATE brothers b SET classID = p.classID, className = p.name
+FROM pledgeClasses p
+WHERE lower(btrim(b.className)) = lower(p.name);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS candidateAttendance;
+DROP TABLE IF EXISTS candidates;
@@ -0,0 +1,26 @@
+-- Prospective members during rush, from first interest until they pledge and become brothers.
+-- Semester is the rush semester label, e.g. "Fall 2024". brotherID is set when the candidate is converted
+CREATE TABLE IF NOT EXISTS candidates(
+    candidateID SERIAL PRIMARY KEY,
+    firstName VARCHAR(50) NOT NULL CHECK (btrim(firstName) <> ''),
+    lastName VARCHAR(50) NOT NULL CHECK (btrim(lastName) <> ''),
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    phoneNumber VARCHAR(30) NOT NULL DEFAULT '',
+    major VARCHAR(100) NOT NULL DEFAULT '',
+    semester VARCHAR(20) NOT NULL DEFAULT '',
+    stage VARCHAR(20) NOT NULL DEFAULT 'interested'
+        CHECK (stage IN ('interested', 'attended', 'bid_extended', 'accepted', 'pledged')),
+    brotherID INT UNIQUE REFERENCES brothers(brotherID) ON DELETE SET NULL ON UPDATE CASCADE,
+    createdAt TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updatedAt TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS candidates_stage_idx ON candidates(stage);
+
+-- Attendance of candidates at rush events, which are regular events. Copied into attendance on conversion
+CREATE TABLE IF NOT EXISTS candidateAttendance(
+    candidateID INT REFERENCES candidates(candidateID) ON DELETE CASCADE ON UPDATE CASCADE,
+    eventID INT REFERENCES events(eventID) ON DELETE CASCADE ON UPDATE CASCADE,
+    attendanceStatus VARCHAR(20) NOT NULL CHECK (attendanceStatus IN ('Present', 'Absent', 'Excused')),
+    PRIMARY KEY (candidateID, eventID)
+);
+CREATE INDEX IF NOT EXISTS candidateattendance_eventid_idx ON candidateAttendance(eventID);
@@ -0,0 +1,3 @@
+ALTER TABLE brotherMerges DROP CONSTRAINT IF EXISTS brothermerges_survivorid_fkey;
+ALTER TABLE brotherMerges ADD CONSTRAINT brothermerges_survivorid_fkey
+    FOREIGN KEY (survivorID) REFERENCES brothers(brotherID) ON DELETE CASCADE ON UPDATE CASCADE;
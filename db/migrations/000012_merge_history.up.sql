@@ -0,0 +1,5 @@
+-- Merge history outlives the survivor. mergeBrothers re-points earlier merges
+-- to the new survivor, and deleting a brother only clears survivorID
+ALTER TABLE brotherMerges DROP CONSTRAINT IF EXISTS brothermerges_survivorid_fkey;
+ALTER TABLE brotherMerges ADD CONSTRAINT brothermerges_survivorid_fkey
+    FOREIGN KEY (survivorID) REFERENCES brothers(brotherID) ON DELETE SET NULL ON UPDATE CASCADE;
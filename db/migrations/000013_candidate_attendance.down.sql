@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS candidateAttendance(
+    candidateID INT REFERENCES candidates(candidateID) ON DELETE CASCADE ON UPDATE CASCADE,
+    eventID INT REFERENCES events(eventID) ON DELETE CASCADE ON UPDATE CASCADE,
+    attendanceStatus VARCHAR(20) NOT NULL CHECK (attendanceStatus IN ('Present', 'Absent', 'Excused')),
+    PRIMARY KEY (candidateID, eventID)
+);
+CREATE INDEX IF NOT EXISTS candidateattendance_eventid_idx ON candidateAttendance(eventID);
+
+INSERT INTO candidateAttendance (candidateID, eventID, attendanceStatus)
+SELECT candidateID, eventID, attendanceStatus FROM attendance WHERE candidateID IS NOT NULL;
+-- Rows of candidates who were never converted have no brother
+DELETE FROM attendance WHERE brotherID IS NULL;
+
+DROP INDEX IF EXISTS attendance_eventid_idx;
+ALTER TABLE attendance DROP CONSTRAINT IF EXISTS attendance_attendee_check;
+ALTER TABLE attendance DROP CONSTRAINT IF EXISTS attendance_candidateid_eventid_key;
+ALTER TABLE attendance DROP CONSTRAINT IF EXISTS attendance_brotherid_eventid_key;
+ALTER TABLE attendance DROP COLUMN IF EXISTS candidateID;
+ALTER TABLE attendance ADD PRIMARY KEY (brotherID, eventID);
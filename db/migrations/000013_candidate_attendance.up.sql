@@ -0,0 +1,21 @@
+-- Rush attendance of candidates is kept in attendance. A row belongs to a brother, a candidate, or both
+-- once the candidate is converted. Deleting a candidate keeps the rows of the brother made from them
+ALTER TABLE attendance ADD COLUMN IF NOT EXISTS candidateID INT REFERENCES candidates(candidateID) ON DELETE SET NULL ON UPDATE CASCADE;
+ALTER TABLE attendance DROP CONSTRAINT IF EXISTS attendance_pkey;
+ALTER TABLE attendance ALTER COLUMN brotherID DROP NOT NULL;
+ALTER TABLE attendance ADD CONSTRAINT attendance_brotherid_eventid_key UNIQUE (brotherID, eventID);
+ALTER TABLE attendance ADD CONSTRAINT attendance_candidateid_eventid_key UNIQUE (candidateID, eventID);
+ALTER TABLE attendance ADD CONSTRAINT attendance_attendee_check CHECK (brotherID IS NOT NULL OR candidateID IS NOT NULL);
+CREATE INDEX IF NOT EXISTS attendance_eventid_idx ON attendance(eventID);
+
+-- Conversions copied rush attendance to the brother: link those rows to the candidate
+UPDATE attendance a SET candidateID = ca.candidateID
+FROM candidateAttendance ca
+JOIN candidates c ON c.candidateID = ca.candidateID
+WHERE a.brotherID = c.brotherID AND a.eventID = ca.eventID;
+
+INSERT INTO attendance (candidateID, eventID, attendanceStatus)
+SELECT ca.candidateID, ca.eventID, ca.attendanceStatus FROM candidateAttendance ca
+WHERE NOT EXISTS (SELECT 1 FROM attendance a WHERE a.candidateID = ca.candidateID AND a.eventID = ca.eventID);
+
+DROP TABLE IF EXISTS candidateAttendance;
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgconn/stmtcache"
+	"github.com/jackc/pgx/v4"
+)
+
+// Statement cache modes accepted by DB_STATEMENT_CACHE_MODE
+const (
+	StatementCacheModePrepare  = "prepare"
+	StatementCacheModeDescribe = "describe"
+	StatementCacheModeDisabled = "disabled"
+)
+
+// Settings for the connection pool and the initial connect. Loaded by the config package
+type PoolConfig struct {
+	// Maximum number of open connections (DB_MAX_CONNS)
+	MaxConns int
+	// Connections opened at startup so the first requests skip the handshake (DB_WARMUP_CONNS).
+	// A hint, not a floor: database/sql closes them like any other idle connection after MaxConnIdleTime
+	WarmUpConns int
+	// Maximum time a connection may be reused (DB_MAX_CONN_LIFETIME)
+	MaxConnLifetime time.Duration
+	// Maximum time a connection may sit idle before it is closed (DB_MAX_CONN_IDLE_TIME)
+	MaxConnIdleTime time.Duration
+	// "prepare", "describe" (use behind PgBouncer) or "disabled" (DB_STATEMENT_CACHE_MODE)
+	StatementCacheMode string
+	// Statements cached per connection (DB_STATEMENT_CACHE_CAPACITY)
+	StatementCacheCapacity int
+	// Attempts to reach the database at startup before giving up (DB_CONNECT_RETRIES)
+	ConnectRetries int
+	// Wait before the first retry, doubled after each attempt (DB_CONNECT_BACKOFF)
+	ConnectBackoff time.Duration
+	// Upper bound for the wait between retries (DB_CONNECT_MAX_BACKOFF)
+	ConnectMaxBackoff time.Duration
+}
+
+// Returns the pool settings used when no env vars are set
+func DefaultPoolConfig() PoolConfig {
+	return PoolConfig{
+		MaxConns:               10,
+		WarmUpConns:            2,
+		MaxConnLifetime:        time.Hour,
+		MaxConnIdleTime:        30 * time.Minute,
+		StatementCacheMode:     StatementCacheModePrepare,
+		StatementCacheCapacity: 512,
+		ConnectRetries:         10,
+		ConnectBackoff:         500 * time.Millisecond,
+		ConnectMaxBackoff:      10 * time.Second,
+	}
+}
+
+// Checks that the settings are consistent
+func (c PoolConfig) Validate() error {
+	if c.MaxConns < 1 {
+		return fmt.Errorf("DB_MAX_CONNS must be at least 1")
+	}
+	if c.WarmUpConns < 0 || c.StatementCacheCapacity < 0 || c.ConnectRetries < 0 {
+		return fmt.Errorf("DB_WARMUP_CONNS, DB_STATEMENT_CACHE_CAPACITY and DB_CONNECT_RETRIES must not be negative")
+	}
+	if c.WarmUpConns > c.MaxConns {
+		return fmt.Errorf("DB_WARMUP_CONNS (%d) must not exceed DB_MAX_CONNS (%d)", c.WarmUpConns, c.MaxConns)
+	}
+	switch c.StatementCacheMode {
+	case StatementCacheModePrepare, StatementCacheModeDescribe, StatementCacheModeDisabled:
+	default:
+		return fmt.Errorf("DB_STATEMENT_CACHE_MODE must be one of prepare, describe or disabled, got %q", c.StatementCacheMode)
+	}
+	return nil
+}
+
+// Parses the database URL and applies the statement cache settings
+func (c PoolConfig) connConfig(databaseURL string) (*pgx.ConnConfig, error) {
+	connConfig, err := pgx.ParseConfig(databaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := stmtcache.ModePrepare
+	if c.StatementCacheMode == StatementCacheModeDescribe {
+		mode = stmtcache.ModeDescribe
+	}
+	capacity := c.StatementCacheCapacity
+	if c.StatementCacheMode == StatementCacheModeDisabled || capacity == 0 {
+		connConfig.BuildStatementCache = nil
+	} else {
+		connConfig.BuildStatementCache = func(conn *pgconn.PgConn) stmtcache.Cache {
+			return stmtcache.New(conn, mode, capacity)
+		}
+	}
+
+	return connConfig, nil
+}
+
+// Returns the wait before the given retry attempt (starting at 1)
+func (c PoolConfig) backoff(attempt int) time.Duration {
+	wait := c.ConnectBackoff
+	for i := 1; i < attempt; i++ {
+		wait *= 2
+		if wait >= c.ConnectMaxBackoff {
+			return c.ConnectMaxBackoff
+		}
+	}
+	return wait
+}
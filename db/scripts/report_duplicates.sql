@@ -0,0 +1,27 @@
+-- Pre-flight report for migration 000001_unique_constraints.
+-- Lists values that appear more than once in columns that are about to become unique.
+-- Usage: psql "$DATABASE_URL" -f db/scripts/report_duplicates.sql
+
+SELECT 'brothers.rollCall' AS "column", rollCall::TEXT AS value, COUNT(*) AS count,
+       string_agg(brotherID::TEXT || ' (' || firstName || ' ' || lastName || ')', ', ' ORDER BY brotherID) AS rows
+FROM brothers
+GROUP BY rollCall
+HAVING COUNT(*) > 1
+
+UNION ALL
+
+SELECT 'semester.semesterLabel', semesterLabel, COUNT(*),
+       string_agg(semesterID::TEXT, ', ' ORDER BY semesterID)
+FROM semester
+GROUP BY semesterLabel
+HAVING COUNT(*) > 1
+
+UNION ALL
+
+SELECT 'eventsCategory.categoryName', categoryName, COUNT(*),
+       string_agg(categoryID::TEXT, ', ' ORDER BY categoryID)
+FROM eventsCategory
+GROUP BY categoryName
+HAVING COUNT(*) > 1
+
+ORDER BY 1, 2;
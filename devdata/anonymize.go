@@ -0,0 +1,227 @@
+// Package devdata builds realistic development datasets as backup archives that `ttdb restore`
+// loads: anonymized copies of production exports, or synthetic chapters of any size
+package devdata
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+	"github.com/pacific-theta-tau/tt-db/store"
+)
+
+// Brother columns that identify a person. Keys are the lower-case column names used in backups
+const (
+	firstNameColumn = "firstname"
+	lastNameColumn  = "lastname"
+	emailColumn     = "email"
+	phoneColumn     = "phonenumber"
+	// Values of custom fields, keyed by field name
+	customFieldsColumn = "customfields"
+)
+
+// Anonymizer replaces names, emails and phone numbers with fake ones.
+// The same input always gets the same replacement for a given key, so records that
+// share a name or email (e.g. likely duplicates) still share them after anonymizing.
+// Without the key, replacements can't be traced back to the originals
+type Anonymizer struct {
+	key []byte
+}
+
+// Returns an Anonymizer whose replacements are derived from key
+func NewAnonymizer(key []byte) *Anonymizer {
+	return &Anonymizer{key: key}
+}
+
+// Deterministic number derived from a kind of value and the value itself
+func (a *Anonymizer) hash(kind string, value string) uint64 {
+	mac := hmac.New(sha256.New, a.key)
+	mac.Write([]byte(kind + "\x00" + strings.ToLower(strings.TrimSpace(value))))
+	return binary.BigEndian.Uint64(mac.Sum(nil))
+}
+
+func (a *Anonymizer) firstName(value string) string {
+	return firstNames[a.hash("first", value)%uint64(len(firstNames))]
+}
+
+func (a *Anonymizer) lastName(value string) string {
+	return lastNames[a.hash("last", value)%uint64(len(lastNames))]
+}
+
+// Fake address on the reserved example.com domain
+func (a *Anonymizer) email(value string) string {
+	h := a.hash("email", value)
+	first := firstNames[h%uint64(len(firstNames))]
+	last := lastNames[(h>>16)%uint64(len(lastNames))]
+	return fmt.Sprintf("%s.%s%d@example.com", strings.ToLower(first), strings.ToLower(last), (h>>32)%1000)
+}
+
+// Fake number in the 555-01xx range reserved for fiction
+func (a *Anonymizer) phone(value string) string {
+	return fmt.Sprintf("(555) 555-01%02d", a.hash("phone", value)%100)
+}
+
+// Placeholder text for a note, the same for equal notes
+func (a *Anonymizer) note(value string) string {
+	return noteBodies[a.hash("note", value)%uint64(len(noteBodies))]
+}
+
+// Placeholder for a text custom field, the same for equal values of the field
+func (a *Anonymizer) customText(field string, value string) string {
+	return fmt.Sprintf("%s-%d", field, a.hash("custom:"+field, value)%10000)
+}
+
+// Replaces the identifying columns of a brother row, and the values of its text custom fields
+// (LinkedIn profiles, addresses...). Empty values are kept empty
+func (a *Anonymizer) brother(row map[string]interface{}, textFields map[string]bool) {
+	replace := func(column string, fake func(string) string) {
+		if value, ok := row[column].(string); ok && value != "" && value != "0" {
+			row[column] = fake(value)
+		}
+	}
+	replace(firstNameColumn, a.firstName)
+	replace(lastNameColumn, a.lastName)
+	replace(emailColumn, a.email)
+	replace(phoneColumn, a.phone)
+
+	customFields, _ := row[customFieldsColumn].(map[string]interface{})
+	for name, value := range customFields {
+		if text, ok := value.(string); ok && text != "" && textFields[name] {
+			customFields[name] = a.customText(name, text)
+		}
+	}
+}
+
+// Names of the text custom fields defined in the backup. Numbers, dates and enum options don't identify anyone
+func textCustomFields(backup store.Backup) (map[string]bool, error) {
+	textFields := map[string]bool{}
+	content, ok := backup.Tables["customFields"]
+	if !ok {
+		return textFields, nil
+	}
+	var fields []struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(content, &fields); err != nil {
+		return nil, fmt.Errorf("customFields: %w", err)
+	}
+	for _, field := range fields {
+		if field.Type == models.CustomFieldText {
+			textFields[field.Name] = true
+		}
+	}
+	return textFields, nil
+}
+
+// Returns a copy of backup with every brother and candidate anonymized, including the snapshots of merged brothers
+func (a *Anonymizer) Backup(backup store.Backup) (store.Backup, error) {
+	anonymized := store.Backup{Manifest: backup.Manifest, Tables: map[string]json.RawMessage{}}
+	for name, rows := range backup.Tables {
+		anonymized.Tables[name] = rows
+	}
+
+	textFields, err := textCustomFields(backup)
+	if err != nil {
+		return store.Backup{}, err
+	}
+	anonymized.Tables["brothers"], err = a.rows(backup.Tables["brothers"], func(row map[string]interface{}) error {
+		a.brother(row, textFields)
+		return nil
+	})
+	if err != nil {
+		return store.Backup{}, fmt.Errorf("brothers: %w", err)
+	}
+
+	if _, ok := backup.Tables["brotherMerges"]; ok {
+		anonymized.Tables["brotherMerges"], err = a.rows(backup.Tables["brotherMerges"], func(row map[string]interface{}) error {
+			snapshot, ok := row["mergedbrother"].(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("merge %v has no mergedbrother snapshot", row["mergeid"])
+			}
+			a.brother(snapshot, textFields)
+			return nil
+		})
+		if err != nil {
+			return store.Backup{}, fmt.Errorf("brotherMerges: %w", err)
+		}
+	}
+
+	// Notes are free text about people, so their text is replaced and their authors anonymized like emails
+	notes := []struct{ table, body, author, editor string }{
+		{"brotherNotes", "body", "author", "updatedby"},
+		{"brotherNoteRevisions", "body", "editedby", ""},
+	}
+	for _, n := range notes {
+		if _, ok := backup.Tables[n.table]; !ok {
+			continue
+		}
+		anonymized.Tables[n.table], err = a.rows(backup.Tables[n.table], func(row map[string]interface{}) error {
+			if body, ok := row[n.body].(string); ok {
+				row[n.body] = a.note(body)
+			}
+			for _, column := range []string{n.author, n.editor} {
+				if value, ok := row[column].(string); ok && value != "" {
+					row[column] = a.email(value)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return store.Backup{}, fmt.Errorf("%s: %w", n.table, err)
+		}
+	}
+
+	// File names often contain the brother's name (e.g. John_Doe_Resume.pdf)
+	if _, ok := backup.Tables["attachments"]; ok {
+		anonymized.Tables["attachments"], err = a.rows(backup.Tables["attachments"], func(row map[string]interface{}) error {
+			if name, ok := row["filename"].(string); ok {
+				row["filename"] = fmt.Sprintf("attachment-%v%s", row["attachmentid"], path.Ext(name))
+			}
+			if uploader, ok := row["uploadedby"].(string); ok && uploader != "" {
+				row["uploadedby"] = a.email(uploader)
+			}
+			return nil
+		})
+		if err != nil {
+			return store.Backup{}, fmt.Errorf("attachments: %w", err)
+		}
+	}
+
+	// Candidates have the same contact columns as brothers, and a converted one gets the same fake name
+	if _, ok := backup.Tables["candidates"]; ok {
+		anonymized.Tables["candidates"], err = a.rows(backup.Tables["candidates"], func(row map[string]interface{}) error {
+			a.brother(row, nil)
+			return nil
+		})
+		if err != nil {
+			return store.Backup{}, fmt.Errorf("candidates: %w", err)
+		}
+	}
+	return anonymized, nil
+}
+
+// Decodes a JSON array of rows, applies fn to each row and encodes them again
+func (a *Anonymizer) rows(content json.RawMessage, fn func(map[string]interface{}) error) (json.RawMessage, error) {
+	var rows []map[string]interface{}
+	decoder := json.NewDecoder(strings.NewReader(string(content)))
+	// Keep IDs and other numbers exactly as exported
+	decoder.UseNumber()
+	if err := decoder.Decode(&rows); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if err := fn(row); err != nil {
+			return nil, err
+		}
+	}
+	if rows == nil {
+		rows = []map[string]interface{}{}
+	}
+	return json.Marshal(rows)
+}
@@ -0,0 +1,189 @@
+package devdata
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pacific-theta-tau/tt-db/store"
+)
+
+// Decodes the rows of a backup table
+func tableRows(t *testing.T, backup store.Backup, table string) []map[string]interface{} {
+	t.Helper()
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(backup.Tables[table], &rows); err != nil {
+		t.Fatalf("%s: %v", table, err)
+	}
+	return rows
+}
+
+func TestAnonymizeIsDeterministic(t *testing.T) {
+	backup := store.Backup{Tables: map[string]json.RawMessage{
+		"brothers": json.RawMessage(`[
+            {"brotherid": 1, "firstname": "John", "lastname": "Doe", "email": "john@gmail.com", "phonenumber": "(123) 456-7890", "major": "Computer Science",
+             "customfields": {"linkedin": "linkedin.com/in/john-doe", "shirtSize": "M"}},
+            {"brotherid": 2, "firstname": "john ", "lastname": "Doe", "email": "", "phonenumber": "0", "major": "Civil Engineering"}
+        ]`),
+		"brotherMerges": json.RawMessage(`[{"mergeid": 1, "mergedbrother": {"firstname": "John", "email": "john@gmail.com"}}]`),
+		"brotherNotes":  json.RawMessage(`[{"noteid": 1, "brotherid": 1, "body": "John works at Doe Inc, call (123) 456-7890", "author": "john@gmail.com", "updatedby": "john@gmail.com"}]`),
+		"customFields":  json.RawMessage(`[{"fieldid": 1, "name": "linkedin", "type": "text"}, {"fieldid": 2, "name": "shirtSize", "type": "enum"}]`),
+		"candidates":    json.RawMessage(`[{"candidateid": 1, "firstname": "John", "lastname": "Doe", "email": "john@gmail.com", "stage": "pledged", "brotherid": 1}]`),
+	}}
+
+	anonymized, err := NewAnonymizer([]byte("key")).Backup(backup)
+	if err != nil {
+		t.Fatalf("Backup: %v", err)
+	}
+	brothers := tableRows(t, anonymized, "brothers")
+	merges := tableRows(t, anonymized, "brotherMerges")
+
+	for _, original := range []string{"John", "Doe", "john@gmail.com", "(123) 456-7890"} {
+		for _, table := range []string{"brothers", "brotherMerges", "brotherNotes", "candidates"} {
+			if strings.Contains(string(anonymized.Tables[table]), original) {
+				t.Errorf("Expected %q to be replaced in %s: %s", original, table, anonymized.Tables[table])
+			}
+		}
+	}
+	if brothers[0]["firstname"] != brothers[1]["firstname"] || brothers[0]["lastname"] != brothers[1]["lastname"] {
+		t.Errorf("Expected equal names to get equal replacements. Got %v and %v", brothers[0], brothers[1])
+	}
+	if brothers[0]["email"] != merges[0]["mergedbrother"].(map[string]interface{})["email"] {
+		t.Errorf("Expected merge snapshots to be anonymized like brothers")
+	}
+	if candidates := tableRows(t, anonymized, "candidates"); candidates[0]["firstname"] != brothers[0]["firstname"] || candidates[0]["stage"] != "pledged" {
+		t.Errorf("Expected candidates to be anonymized like brothers. Got %v", candidates[0])
+	}
+	if notes := tableRows(t, anonymized, "brotherNotes"); notes[0]["author"] != brothers[0]["email"] {
+		t.Errorf("Expected note authors to be anonymized like emails. Got %v", notes[0])
+	}
+	customFields := brothers[0]["customfields"].(map[string]interface{})
+	if strings.Contains(customFields["linkedin"].(string), "doe") || customFields["shirtSize"] != "M" {
+		t.Errorf("Expected text custom fields to be replaced and enums kept. Got %v", customFields)
+	}
+	if brothers[1]["email"] != "" || brothers[1]["phonenumber"] != "0" || brothers[0]["major"] != "Computer Science" {
+		t.Errorf("Expected empty values and other columns to be kept. Got %v", brothers)
+	}
+
+	other, _ := NewAnonymizer([]byte("other key")).Backup(backup)
+	if string(other.Tables["brothers"]) == string(anonymized.Tables["brothers"]) {
+		t.Error("Expected a different key to give different replacements")
+	}
+}
+
+func TestAnonymizeOlderBackup(t *testing.T) {
+	// Backups from before merge history have only the original tables
+	backup := store.Backup{Tables: map[string]json.RawMessage{
+		"brothers": json.RawMessage(`[{"brotherid": 1, "firstname": "John", "lastname": "Doe", "email": "john@gmail.com"}]`),
+	}}
+
+	anonymized, err := NewAnonymizer([]byte("key")).Backup(backup)
+	if err != nil {
+		t.Fatalf("Backup: %v", err)
+	}
+	if _, ok := anonymized.Tables["brotherMerges"]; ok {
+		t.Errorf("Expected no brotherMerges in an older backup. Got %s", anonymized.Tables["brotherMerges"])
+	}
+	if strings.Contains(string(anonymized.Tables["brothers"]), "john@gmail.com") {
+		t.Errorf("Expected brothers to be anonymized. Got %s", anonymized.Tables["brothers"])
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	opts := Options{Brothers: 60, Semesters: 8, Until: "Fall 2024", Seed: 7, SchemaVersion: 3}
+	backup, err := Generate(opts)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	if n := backup.Manifest.Tables["brothers"]; n != 60 {
+		t.Errorf("Expected 60 brothers. Got %d", n)
+	}
+	semesters := tableRows(t, backup, "semester")
+	if len(semesters) != 8 || semesters[0]["semesterlabel"] != "Spring 2021" || semesters[7]["semesterlabel"] != "Fall 2024" {
+		t.Errorf("Expected Spring 2021 to Fall 2024. Got %v", semesters)
+	}
+	if backup.Manifest.Tables["attendance"] == 0 || backup.Manifest.Tables["brotherStatus"] == 0 {
+		t.Errorf("Expected attendance and statuses. Got %v", backup.Manifest.Tables)
+	}
+
+	// Every brother's current status is their latest semester status
+	latest := map[float64]string{}
+	for _, row := range tableRows(t, backup, "brotherStatus") {
+		latest[row["brotherid"].(float64)] = row["status"].(string)
+	}
+	for _, brother := range tableRows(t, backup, "brothers") {
+		if status := latest[brother["brotherid"].(float64)]; status == "" || brother["status"] != status {
+			t.Errorf("Brother %v has status %q but %q in their latest semester", brother["brotherid"], brother["status"], status)
+		}
+	}
+
+	// Older schemas have no lineage or pledge classes; newer ones link littles to bigs who pledged before them
+	for _, table := range []string{"lineage", "pledgeClasses"} {
+		if _, ok := backup.Tables[table]; ok {
+			t.Errorf("Expected no %s at schema version %d", table, opts.SchemaVersion)
+		}
+	}
+	current, err := Generate(Options{Brothers: 60, Semesters: 8, Until: "Fall 2024", Seed: 7, SchemaVersion: 13})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	links := tableRows(t, current, "lineage")
+	if len(links) == 0 {
+		t.Error("Expected big/little links")
+	}
+	for _, link := range links {
+		if link["bigid"].(float64) >= link["littleid"].(float64) {
+			t.Errorf("Expected bigs to pledge before their littles. Got %v", link)
+		}
+	}
+
+	// Every brother belongs to a pledge class whose roll call range holds theirs
+	classes := map[float64]map[string]interface{}{}
+	for _, class := range tableRows(t, current, "pledgeClasses") {
+		classes[class["classid"].(float64)] = class
+	}
+	if len(classes) != 8 {
+		t.Errorf("Expected a pledge class per semester. Got %d", len(classes))
+	}
+	for _, brother := range tableRows(t, current, "brothers") {
+		class, ok := classes[brother["classid"].(float64)]
+		rollCall := brother["rollcall"].(float64)
+		if !ok || class["name"] != brother["classname"] || rollCall < class["firstrollcall"].(float64) || rollCall > class["lastrollcall"].(float64) {
+			t.Errorf("Brother %v doesn't fit their class %v", brother, class)
+		}
+	}
+
+	// The latest rush has candidates who haven't pledged yet, and only those past interested went to rush events
+	stages := map[float64]string{}
+	for _, candidate := range tableRows(t, current, "candidates") {
+		if candidate["stage"] == "pledged" || candidate["semester"] != "Fall 2024" || candidate["createdat"] == nil {
+			t.Errorf("Expected an unconverted Fall 2024 candidate. Got %v", candidate)
+		}
+		stages[candidate["candidateid"].(float64)] = candidate["stage"].(string)
+	}
+	if len(stages) == 0 {
+		t.Error("Expected rush candidates")
+	}
+	rushRecords := 0
+	for _, record := range tableRows(t, current, "attendance") {
+		candidateID, ok := record["candidateid"].(float64)
+		if !ok {
+			continue
+		}
+		rushRecords++
+		if stage := stages[candidateID]; stage == "" || stage == "interested" || record["brotherid"] != nil {
+			t.Errorf("Expected attendance only for unconverted candidates past interested. Got %v at stage %q", record, stage)
+		}
+	}
+	if rushRecords == 0 {
+		t.Error("Expected candidates at rush events")
+	}
+
+	again, _ := Generate(opts)
+	for table, rows := range backup.Tables {
+		if string(again.Tables[table]) != string(rows) {
+			t.Errorf("Expected the same seed to generate the same %s", table)
+		}
+	}
+}
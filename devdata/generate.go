@@ -0,0 +1,317 @@
+package devdata
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+	"github.com/pacific-theta-tau/tt-db/store"
+)
+
+// Settings for a synthetic chapter
+type Options struct {
+	// Number of brothers
+	Brothers int
+	// Number of consecutive semesters, ending with Until
+	Semesters int
+	// Last semester, e.g. "Fall 2024"
+	Until string
+	// Same seed, same dataset
+	Seed uint64
+	// Schema version recorded in the archive. Restores require the database to be at this version
+	SchemaVersion int
+}
+
+// Rows of each table, keyed by lower-case column name like in backups
+type dataset struct {
+	categories []map[string]interface{}
+	events     []map[string]interface{}
+	brothers   []map[string]interface{}
+	semesters  []map[string]interface{}
+	attendance []map[string]interface{}
+	statuses   []map[string]interface{}
+	classes    []map[string]interface{}
+	lineage    []map[string]interface{}
+	candidates []map[string]interface{}
+	// Attendance of candidates at rush events, in attendance from candidateAttendanceSince
+	candidateAttendance []map[string]interface{}
+}
+
+// Schema version that moved candidate attendance into attendance. Older backups leave it out
+const candidateAttendanceSince = 13
+
+// Returns the label of the semester before label, e.g. "Fall 2023" for "Spring 2024"
+func previousSemester(label string) string {
+	start, _, _ := store.SemesterDates(label)
+	if start.Month() == time.January {
+		return fmt.Sprintf("Fall %d", start.Year()-1)
+	}
+	return fmt.Sprintf("Spring %d", start.Year())
+}
+
+// Generates a chapter where each semester recruits a pledge class. Brothers are Active for
+// three to five years with the odd Co-op or Inactive semester, then Pre-Alumnus and Alumnus.
+// Active brothers attend most of each semester's events, and most have a big from a recent pledge class
+func Generate(opts Options) (store.Backup, error) {
+	if opts.Brothers < 1 || opts.Semesters < 1 {
+		return store.Backup{}, errors.New("brothers and semesters must be at least 1")
+	}
+	if _, _, err := store.SemesterDates(opts.Until); err != nil {
+		return store.Backup{}, err
+	}
+	rng := rand.New(rand.NewPCG(opts.Seed, opts.Seed))
+	var data dataset
+
+	// Semesters, oldest first
+	labels := []string{opts.Until}
+	for len(labels) < opts.Semesters {
+		labels = append([]string{previousSemester(labels[0])}, labels...)
+	}
+	for i, label := range labels {
+		data.semesters = append(data.semesters, map[string]interface{}{"semesterid": i + 1, "semesterlabel": label})
+	}
+
+	categories := []string{"Professional Development", "Brotherhood", "Community Service"}
+	for i, name := range categories {
+		data.categories = append(data.categories, map[string]interface{}{"categoryid": i + 1, "categoryname": name})
+	}
+
+	// Status of every brother in every semester, "" before they joined
+	history := make([][]string, opts.Brothers)
+	// Brothers of each pledge class
+	classes := make([][]int, opts.Semesters)
+	for b := range history {
+		// Brothers are spread over the pledge classes in rollCall order
+		pledged := b * opts.Semesters / opts.Brothers
+		if len(classes[pledged]) == 0 {
+			data.classes = append(data.classes, pledgeClass(len(data.classes)+1, labels[pledged]))
+			data.classes[len(data.classes)-1]["firstrollcall"] = b + 1
+		}
+		classes[pledged] = append(classes[pledged], b)
+		class := data.classes[len(data.classes)-1]
+		class["lastrollcall"] = b + 1
+		history[b] = statusHistory(rng, pledged, opts.Semesters)
+
+		first := firstNames[rng.IntN(len(firstNames))]
+		last := lastNames[rng.IntN(len(lastNames))]
+		badStanding := 0
+		if rng.Float64() < 0.05 {
+			badStanding = 1
+		}
+		data.brothers = append(data.brothers, map[string]interface{}{
+			"brotherid":   b + 1,
+			"rollcall":    b + 1,
+			"firstname":   first,
+			"lastname":    last,
+			"major":       majors[rng.IntN(len(majors))],
+			"status":      currentStatus(history[b]),
+			"classname":   class["name"],
+			"classid":     class["classid"],
+			"email":       fmt.Sprintf("%s.%s%d@example.com", strings.ToLower(first), strings.ToLower(last), b+1),
+			"phonenumber": fmt.Sprintf("(555) 555-01%02d", rng.IntN(100)),
+			"badstanding": badStanding,
+			// Restore fills missing keys with NULL rather than the column default. Older schemas ignore it
+			"customfields": map[string]interface{}{},
+		})
+		for s, status := range history[b] {
+			if status != "" {
+				data.statuses = append(data.statuses, map[string]interface{}{"brotherid": b + 1, "semesterid": s + 1, "status": status})
+			}
+		}
+	}
+
+	// Six to ten events per semester, during the school months
+	for s, label := range labels {
+		start, _, _ := store.SemesterDates(label)
+		if start.Month() == time.July {
+			start = start.AddDate(0, 1, 0)
+		}
+		for n := 6 + rng.IntN(5); n > 0; n-- {
+			eventID := len(data.events) + 1
+			category := rng.IntN(len(categories))
+			names := eventNames[categories[category]]
+			data.events = append(data.events, map[string]interface{}{
+				"eventid":       eventID,
+				"eventname":     names[rng.IntN(len(names))],
+				"categoryid":    category + 1,
+				"eventlocation": eventLocations[rng.IntN(len(eventLocations))],
+				"eventdate":     start.AddDate(0, 0, 7+rng.IntN(130)).Format(time.DateOnly),
+			})
+
+			for b := range history {
+				if status := history[b][s]; status != "Active" && status != "Pre-Alumnus" {
+					continue
+				}
+				attendance := "Present"
+				switch roll := rng.Float64(); {
+				case roll < 0.12:
+					attendance = "Excused"
+				case roll < 0.25:
+					attendance = "Absent"
+				}
+				data.attendance = append(data.attendance, map[string]interface{}{"brotherid": b + 1, "eventid": eventID, "attendancestatus": attendance})
+			}
+		}
+	}
+
+	// Most brothers get a big from one of the two pledge classes before theirs
+	for pledged := 1; pledged < len(classes); pledged++ {
+		bigs := classes[pledged-1]
+		if pledged > 1 {
+			bigs = append(slices.Clone(classes[pledged-2]), bigs...)
+		}
+		for _, b := range classes[pledged] {
+			if len(bigs) > 0 && rng.Float64() < 0.9 {
+				data.lineage = append(data.lineage, map[string]interface{}{"littleid": b + 1, "bigid": bigs[rng.IntN(len(bigs))] + 1})
+			}
+		}
+	}
+
+	// The latest semester's rush is in progress: candidates at every stage before pledging, who went to its first events
+	rushStart, _, _ := store.SemesterDates(opts.Until)
+	var rushEvents []int
+	for _, event := range data.events {
+		if date, _ := time.Parse(time.DateOnly, event["eventdate"].(string)); !date.Before(rushStart) && len(rushEvents) < 2 {
+			rushEvents = append(rushEvents, event["eventid"].(int))
+		}
+	}
+	for c, n := 0, 8+rng.IntN(5); c < n; c++ {
+		stage := models.CandidateStages[rng.IntN(len(models.CandidateStages)-1)]
+		first := firstNames[rng.IntN(len(firstNames))]
+		last := lastNames[rng.IntN(len(lastNames))]
+		createdAt := rushStart.AddDate(0, 0, rng.IntN(21)).Format(time.RFC3339)
+		data.candidates = append(data.candidates, map[string]interface{}{
+			"candidateid": c + 1,
+			"firstname":   first,
+			"lastname":    last,
+			"email":       fmt.Sprintf("%s.%s.rush%d@example.com", strings.ToLower(first), strings.ToLower(last), c+1),
+			"phonenumber": fmt.Sprintf("(555) 555-01%02d", rng.IntN(100)),
+			"major":       majors[rng.IntN(len(majors))],
+			"semester":    opts.Until,
+			"stage":       stage,
+			"createdat":   createdAt,
+			"updatedat":   createdAt,
+		})
+		if stage == models.StageInterested {
+			continue
+		}
+		for _, eventID := range rushEvents {
+			data.candidateAttendance = append(data.candidateAttendance, map[string]interface{}{"candidateid": c + 1, "eventid": eventID, "attendancestatus": "Present"})
+		}
+	}
+
+	return data.backup(opts.SchemaVersion)
+}
+
+// Returns the pledge class with the ID, named after it: Alpha to Omega, then Alpha Alpha and so on.
+// Initiation is ten weeks into the semester. The caller sets the roll call range
+func pledgeClass(classID int, semester string) map[string]interface{} {
+	letters := store.GreekLetters
+	name := letters[(classID-1)%len(letters)]
+	if classID > len(letters) {
+		name = letters[(classID-1)/len(letters)-1] + " " + name
+	}
+	start, _, _ := store.SemesterDates(semester)
+	if start.Month() == time.July {
+		start = start.AddDate(0, 1, 0)
+	}
+	return map[string]interface{}{
+		"classid":        classID,
+		"name":           name,
+		"semester":       semester,
+		"initiationdate": start.AddDate(0, 0, 70).Format(time.DateOnly),
+		"createdat":      start.Format(time.RFC3339),
+	}
+}
+
+// Statuses of a brother who pledged in semester index pledged, for every semester
+func statusHistory(rng *rand.Rand, pledged int, semesters int) []string {
+	history := make([]string, semesters)
+	if rng.Float64() < 0.03 {
+		// Transferred out after a semester or two
+		end := min(pledged+1+rng.IntN(2), semesters-1)
+		for s := pledged; s < end; s++ {
+			history[s] = "Active"
+		}
+		history[end] = "Transferred"
+		return history
+	}
+
+	active := 6 + rng.IntN(4)
+	for s := pledged; s < semesters; s++ {
+		switch served := s - pledged; {
+		case served < active:
+			history[s] = "Active"
+			if served > 1 {
+				if roll := rng.Float64(); roll < 0.1 {
+					history[s] = "Co-op"
+				} else if roll < 0.13 {
+					history[s] = "Inactive"
+				}
+			}
+		case served == active:
+			history[s] = "Pre-Alumnus"
+		default:
+			history[s] = "Alumnus"
+		}
+	}
+	return history
+}
+
+// Latest status in a history. Brothers who transferred keep that status
+func currentStatus(history []string) string {
+	for s := len(history) - 1; s >= 0; s-- {
+		if history[s] != "" {
+			return history[s]
+		}
+	}
+	return ""
+}
+
+// Encodes the dataset as a backup that `ttdb restore` accepts
+func (data dataset) backup(schemaVersion int) (store.Backup, error) {
+	backup := store.Backup{
+		Manifest: models.BackupManifest{
+			Format:        store.BackupFormat,
+			FormatVersion: store.BackupFormatVersion,
+			SchemaVersion: schemaVersion,
+			CreatedAt:     time.Now().UTC(),
+			Tables:        map[string]int{},
+		},
+		Tables: map[string]json.RawMessage{},
+	}
+	attendance := data.attendance
+	if schemaVersion >= candidateAttendanceSince {
+		attendance = append(slices.Clip(attendance), data.candidateAttendance...)
+	}
+	tables := map[string][]map[string]interface{}{
+		"eventsCategory": data.categories,
+		"events":         data.events,
+		"brothers":       data.brothers,
+		"semester":       data.semesters,
+		"attendance":     attendance,
+		"brotherStatus":  data.statuses,
+		"pledgeClasses":  data.classes,
+		"lineage":        data.lineage,
+		"candidates":     data.candidates,
+	}
+	// Tables the generator doesn't fill (merges, notes...) are left empty
+	for _, name := range store.BackupTables(schemaVersion) {
+		rows := tables[name]
+		if rows == nil {
+			rows = []map[string]interface{}{}
+		}
+		content, err := json.Marshal(rows)
+		if err != nil {
+			return store.Backup{}, err
+		}
+		backup.Tables[name] = content
+		backup.Manifest.Tables[name] = len(rows)
+	}
+	return backup, nil
+}
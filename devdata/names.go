@@ -0,0 +1,45 @@
+package devdata
+
+// Names used for fake brothers
+var firstNames = []string{
+	"Aaron", "Adrian", "Aiden", "Alex", "Andre", "Andrew", "Anthony", "Ben", "Brandon", "Brian",
+	"Caleb", "Cameron", "Carlos", "Chris", "Daniel", "David", "Diego", "Dylan", "Eli", "Eric",
+	"Ethan", "Evan", "Gabriel", "Henry", "Isaac", "Ivan", "Jacob", "Jason", "Javier", "Jordan",
+	"Joseph", "Joshua", "Julian", "Kevin", "Kyle", "Leo", "Logan", "Lucas", "Marcus", "Mason",
+	"Matthew", "Miguel", "Nathan", "Noah", "Omar", "Owen", "Patrick", "Rafael", "Ryan", "Samuel",
+	"Sean", "Steven", "Thomas", "Tyler", "Victor", "Vincent", "William", "Xavier", "Zachary", "Zion",
+}
+
+var lastNames = []string{
+	"Adams", "Alvarez", "Bautista", "Bennett", "Brooks", "Castillo", "Chen", "Cruz", "Diaz", "Evans",
+	"Flores", "Foster", "Garcia", "Gomez", "Gupta", "Hall", "Hayes", "Huang", "Ito", "Jackson",
+	"Kim", "Kumar", "Lee", "Lopez", "Martin", "Mendoza", "Miller", "Morales", "Nguyen", "Ortiz",
+	"Park", "Patel", "Perez", "Reyes", "Rivera", "Robinson", "Sanchez", "Santos", "Shah", "Singh",
+	"Smith", "Tanaka", "Torres", "Tran", "Turner", "Vargas", "Walker", "Wang", "Watson", "Wong",
+}
+
+var majors = []string{
+	"Bioengineering", "Civil Engineering", "Computer Engineering", "Computer Science",
+	"Electrical Engineering", "Engineering Management", "Engineering Physics", "Mechanical Engineering",
+}
+
+// Event names per category
+var eventNames = map[string][]string{
+	"Professional Development": {"Resume Workshop", "CO-OP Panel", "Industry Night", "Mock Interviews", "Alumni Mixer"},
+	"Brotherhood":              {"Movies", "Bowling", "Beach Day", "Game Night", "Hike", "Potluck"},
+	"Community Service":        {"Food Bank", "Park Cleanup", "STEM Outreach", "Blood Drive"},
+}
+
+var eventLocations = []string{"Regent Room", "CTC", "Library", "Baun Hall", "UC Ballroom", "Off Campus"}
+
+// Replacement texts for anonymized notes
+var noteBodies = []string{
+	"On co-op this semester.",
+	"Reached out by text, no reply yet.",
+	"Asked to go Inactive for the semester.",
+	"Tried calling twice, left a voicemail.",
+	"Updated contact info after talking in person.",
+	"Plans to return next semester.",
+	"Excused from events for class conflicts.",
+	"Graduating early, moving to Pre-Alumnus.",
+}
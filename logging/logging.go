@@ -0,0 +1,105 @@
+// Package logging configures structured JSON logging with log/slog.
+// Log lines written with a request context carry the request's ID and trace, and
+// emails and phone numbers are redacted from every logged string
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Keys of attributes that are never redacted
+var unredactedKeys = map[string]bool{
+	slog.TimeKey:  true,
+	slog.LevelKey: true,
+	RequestIDKey:  true,
+	TraceIDKey:    true,
+	SpanIDKey:     true,
+}
+
+// Parses a level name (debug, info, warn or error). Empty means info
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if name == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return slog.LevelInfo, fmt.Errorf("invalid log level %q: use debug, info, warn or error", name)
+	}
+	return level, nil
+}
+
+// Creates a JSON logger writing to w that adds request IDs and redacts PII
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	jsonHandler := slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redactAttr,
+	})
+	return slog.New(&contextHandler{Handler: jsonHandler})
+}
+
+// Makes a logger from New the default for slog and the standard log package
+func Setup(w io.Writer, level slog.Level) {
+	slog.SetDefault(New(w, level))
+}
+
+// Redacts string attributes, including the message, and any values such as errors and structs,
+// which are logged as their redacted text. slog passes the attributes of groups one by one
+func redactAttr(groups []string, attr slog.Attr) slog.Attr {
+	if len(groups) == 0 && unredactedKeys[attr.Key] {
+		return attr
+	}
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, RedactString(attr.Value.String()))
+	case slog.KindAny:
+		if err, ok := attr.Value.Any().(error); ok {
+			return slog.String(attr.Key, RedactString(err.Error()))
+		}
+		return slog.String(attr.Key, RedactString(Body(attr.Value.Any())))
+	}
+	return attr
+}
+
+// Attribute keys linking log lines to traces
+const (
+	TraceIDKey = "trace_id"
+	SpanIDKey  = "span_id"
+)
+
+// contextHandler adds the request ID and trace stored in the record's context to every record
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := RequestIDFromContext(ctx); id != "" {
+		record.AddAttrs(slog.String(RequestIDKey, id))
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		record.AddAttrs(
+			slog.String(TraceIDKey, spanContext.TraceID().String()),
+			slog.String(SpanIDKey, spanContext.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+// Formats a value for logging with %+v, e.g. a decoded request body.
+// The result is redacted when it is logged
+func Body(v interface{}) string {
+	return strings.TrimSpace(fmt.Sprintf("%+v", v))
+}
@@ -0,0 +1,78 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRedactString(t *testing.T) {
+	cases := map[string]string{
+		"{Email:jdoe@example.com Major:CS}": "{Email:[REDACTED] Major:CS}",
+		"phone (209) 555-0100":              "phone [REDACTED]",
+		"phone +1 209.555.0100":             "phone [REDACTED]",
+		"phone 2095550100":                  "phone [REDACTED]",
+		"rollCall 123 event 45":             "rollCall 123 event 45",
+	}
+	for input, expected := range cases {
+		if actual := RedactString(input); actual != expected {
+			t.Errorf("RedactString(%q): expected %q. Got %q\n", input, expected, actual)
+		}
+	}
+}
+
+func TestLoggerAddsRequestIDAndRedacts(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, slog.LevelInfo)
+	ctx := WithRequestID(context.Background(), "abc123")
+
+	logger.InfoContext(ctx, "Request body", "body", Body(struct{ Email string }{"jdoe@example.com"}))
+
+	var line map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("Expected a JSON log line. Got %q: %v", buf.String(), err)
+	}
+	if line[RequestIDKey] != "abc123" {
+		t.Errorf("Expected %s abc123. Got %v\n", RequestIDKey, line[RequestIDKey])
+	}
+	if strings.Contains(buf.String(), "jdoe@example.com") {
+		t.Errorf("Expected email to be redacted. Got %s", buf.String())
+	}
+}
+
+func TestLoggerRedactsErrorsAndGroups(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, slog.LevelInfo)
+
+	logger.Error("Error while sending invite",
+		"error", errors.New("no account for jdoe@example.com"),
+		slog.Group("brother", "contact", struct{ Phone string }{"(209) 555-0100"}, "time", "jsmith@example.com"),
+	)
+
+	var line struct {
+		Error   string
+		Brother map[string]string
+	}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("Expected a JSON log line. Got %q: %v", buf.String(), err)
+	}
+	if line.Error != "no account for "+Redacted {
+		t.Errorf("Expected the email in the error to be redacted. Got %q", line.Error)
+	}
+	if line.Brother["contact"] != "{Phone:"+Redacted+"}" || line.Brother["time"] != Redacted {
+		t.Errorf("Expected the group's values to be redacted. Got %v", line.Brother)
+	}
+}
+
+func TestLoggerLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, slog.LevelWarn)
+	logger.Info("not logged")
+	if buf.Len() != 0 {
+		t.Errorf("Expected info logs to be dropped at warn level. Got %s", buf.String())
+	}
+}
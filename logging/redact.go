@@ -0,0 +1,18 @@
+package logging
+
+import "regexp"
+
+// Replacement for redacted values
+const Redacted = "[REDACTED]"
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	// North American numbers with optional country code and separators, e.g. (209) 555-0100 or +1 209.555.0100
+	phonePattern = regexp.MustCompile(`(?:\+?1[\s.\-]?)?\(?\b\d{3}\)?[\s.\-]?\d{3}[\s.\-]?\d{4}\b`)
+)
+
+// Replaces emails and phone numbers in s with Redacted
+func RedactString(s string) string {
+	s = emailPattern.ReplaceAllString(s, Redacted)
+	return phonePattern.ReplaceAllString(s, Redacted)
+}
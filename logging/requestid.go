@@ -0,0 +1,19 @@
+package logging
+
+import "context"
+
+// Attribute key of request IDs in log lines
+const RequestIDKey = "request_id"
+
+type requestIDContextKey struct{}
+
+// Returns a copy of ctx carrying the request ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, id)
+}
+
+// Returns the request ID stored in ctx, or "" if there is none
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey{}).(string)
+	return id
+}
@@ -2,35 +2,69 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"log"
 	"os"
+	"strconv"
 
-	"github.com/joho/godotenv"
 	"github.com/pacific-theta-tau/tt-db/api"
+	"github.com/pacific-theta-tau/tt-db/config"
 	"github.com/pacific-theta-tau/tt-db/db"
+	"github.com/pacific-theta-tau/tt-db/logging"
+	"github.com/pacific-theta-tau/tt-db/tracing"
 )
 
 func main() {
-	// Loading environment variables
-	devFlag := flag.String("env", "dev", "Environment to serve app. Options: dev (default) | prod")
-	flag.Parse()
-	err := godotenv.Load(*devFlag + ".env")
+	// Load settings from flags, environment and the optional <env>.env file. See config package
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if cfg != nil && cfg.PrintConfig {
+		cfg.Print(os.Stdout)
+		if err != nil {
+			log.Fatalf("ERROR: invalid configuration:\n%v", err)
+		}
+		return
+	}
 	if err != nil {
-		log.Fatal("Error loading .env file. Make sure to have setup the appropriate .env file")
+		log.Fatalf("ERROR: invalid configuration:\n%v", err)
+	}
+
+	// Setup JSON logging
+	logging.Setup(os.Stdout, cfg.LogLevel)
+	log.Println("** Initializing app in", cfg.Env, "environment **")
+	if cfg.File != "" {
+		log.Printf("Loaded settings from %s", cfg.File)
 	}
 
-	log.Println("** Initializing app in", *devFlag, "environment **")
-	// Setup app configurations
-	app_port := os.Getenv("APP_PORT")
-	databaseURL := os.Getenv("DATABASE_URL")
-	if databaseURL == "" {
-		log.Fatal("ERROR: environment variable DatabaseURL not set")
+	// Setup tracing. Spans are flushed when the server stops
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		log.Fatalf("ERROR: unable to setup tracing: %v", err)
 	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Printf("Error while flushing traces: %v", err)
+		}
+	}()
 
 	// Connect to DB and serve API
-	db := db.NewPostgresDB(databaseURL)
-	app := api.NewApplication(db, app_port)
+	db := db.NewPostgresDB(cfg.DatabaseURL)
+	db.Pool = cfg.Pool
+	app := api.NewApplication(db, strconv.Itoa(cfg.Port))
+	app.AutoMigrate = cfg.AutoMigrate
+	app.Timeouts = cfg.Timeouts
+	app.Server = cfg.Server
+	app.CORS = cfg.CORS
+	app.RateLimits = cfg.RateLimits
+	app.Lockout = cfg.Lockout
+	app.PublicURL = cfg.PublicURL
+	app.Blob = cfg.Blob
+	app.Attachments = cfg.Attachments
+
 	log.Printf("Serving app on port %s ...", app.Port)
 	app.Serve()
 }
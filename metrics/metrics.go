@@ -0,0 +1,97 @@
+// Package metrics defines the Prometheus metrics exposed on /metrics
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Prefix of all metric names
+const namespace = "ttdb"
+
+// Registry holding every metric of the API. A dedicated registry keeps tests and
+// multiple servers in one process from registering the same collectors twice
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by route pattern, method and status code.",
+	}, []string{"route", "method", "code"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route pattern and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method"})
+
+	jsendResponses = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "jsend_responses_total",
+		Help:      "API responses by route pattern and JSend status (success, fail or error).",
+	}, []string{"route", "status"})
+
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Database query latency by calling method and outcome (ok or error).",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"method", "outcome"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpDuration,
+		jsendResponses,
+		dbQueryDuration,
+	)
+}
+
+// Exposes the pool statistics of conn (open, in-use and idle connections, waits, closes)
+func RegisterDBStats(conn *sql.DB) error {
+	return Registry.Register(collectors.NewDBStatsCollector(conn, namespace))
+}
+
+// Handler serving all metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// Records a finished HTTP request. route is the chi route pattern (e.g. /api/brothers/{id}),
+// so requests for different IDs share the same series
+func ObserveRequest(route string, method string, statusCode int, duration time.Duration) {
+	httpRequests.WithLabelValues(route, method, strconv.Itoa(statusCode)).Inc()
+	httpDuration.WithLabelValues(route, method).Observe(duration.Seconds())
+	jsendResponses.WithLabelValues(route, JSendStatus(statusCode)).Inc()
+}
+
+// Records a finished database query made by method
+func ObserveQuery(method string, duration time.Duration, err error) {
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+	}
+	dbQueryDuration.WithLabelValues(method, outcome).Observe(duration.Seconds())
+}
+
+// JSend status the API responds with for a HTTP status code
+func JSendStatus(statusCode int) string {
+	switch {
+	case statusCode >= 500:
+		return "error"
+	case statusCode >= 400:
+		return "fail"
+	}
+	return "success"
+}
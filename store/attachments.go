@@ -0,0 +1,83 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+)
+
+// Column that links attachments to what they are attached to
+type AttachmentOwner string
+
+// Kinds of records attachments belong to
+const (
+	BrotherAttachments AttachmentOwner = "brotherID"
+	EventAttachments   AttachmentOwner = "eventID"
+)
+
+// Columns of attachments in the order ScanAttachment reads them
+const AttachmentColumns = `attachmentID, brotherID, eventID, fileName, contentType, size, uploadedBy, createdAt, blobKey`
+
+// Scans an attachments row selected with AttachmentColumns
+func ScanAttachment(row RowScanner) (models.Attachment, error) {
+	var attachment models.Attachment
+	err := row.Scan(
+		&attachment.AttachmentID,
+		&attachment.BrotherID,
+		&attachment.EventID,
+		&attachment.FileName,
+		&attachment.ContentType,
+		&attachment.Size,
+		&attachment.UploadedBy,
+		&attachment.CreatedAt,
+		&attachment.BlobKey,
+	)
+	if err != nil {
+		return models.Attachment{}, err
+	}
+	return attachment, nil
+}
+
+// Returns the attachments of a brother or event, newest first
+func ListAttachments(ctx context.Context, q Querier, owner AttachmentOwner, ownerID int) ([]models.Attachment, error) {
+	query := fmt.Sprintf(`SELECT %s FROM attachments WHERE %s = $1 ORDER BY createdAt DESC, attachmentID DESC`, AttachmentColumns, owner)
+	rows, err := q.QueryContext(ctx, query, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attachments := []models.Attachment{}
+	for rows.Next() {
+		attachment, err := ScanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+	return attachments, rows.Err()
+}
+
+// Returns an attachment of a brother or event. Returns sql.ErrNoRows if it has no such attachment
+func GetAttachment(ctx context.Context, q Querier, owner AttachmentOwner, ownerID int, attachmentID int) (models.Attachment, error) {
+	query := fmt.Sprintf(`SELECT %s FROM attachments WHERE %s = $1 AND attachmentID = $2`, AttachmentColumns, owner)
+	return ScanAttachment(q.QueryRowContext(ctx, query, ownerID, attachmentID))
+}
+
+// Records an attachment whose contents were stored under attachment.BlobKey
+func InsertAttachment(ctx context.Context, q Querier, owner AttachmentOwner, ownerID int, attachment models.Attachment) (models.Attachment, error) {
+	query := fmt.Sprintf(`
+    INSERT INTO attachments (%s, fileName, contentType, size, uploadedBy, blobKey)
+    VALUES ($1, $2, $3, $4, $5, $6)
+    RETURNING %s`, owner, AttachmentColumns)
+	row := q.QueryRowContext(ctx, query, ownerID, attachment.FileName, attachment.ContentType, attachment.Size, attachment.UploadedBy, attachment.BlobKey)
+	return ScanAttachment(row)
+}
+
+// Deletes an attachment and returns it, so its contents can be removed from the blob store.
+// Returns sql.ErrNoRows if the brother or event has no such attachment
+func DeleteAttachment(ctx context.Context, q Querier, owner AttachmentOwner, ownerID int, attachmentID int) (models.Attachment, error) {
+	query := fmt.Sprintf(`DELETE FROM attachments WHERE %s = $1 AND attachmentID = $2 RETURNING %s`, owner, AttachmentColumns)
+	return ScanAttachment(q.QueryRowContext(ctx, query, ownerID, attachmentID))
+}
@@ -0,0 +1,66 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+)
+
+// Selects attendance rows joined with brother and event data in the order expected by
+// ScanAttendance. Format with the table or CTE name to read attendance rows from
+const AttendanceRecordQuery = `
+    SELECT a.brotherID, a.eventID, a.attendanceStatus, b.rollCall, b.FirstName, b.LastName, e.EventName, e.eventLocation, e.eventDate, COALESCE(ec.categoryName, '')
+    FROM %s a
+    JOIN brothers b ON b.brotherID = a.brotherID
+    JOIN events e ON e.eventID = a.eventID
+    LEFT JOIN eventsCategory ec ON ec.categoryID = e.categoryID
+    `
+
+// Scans a row selected with AttendanceRecordQuery into an Attendance
+func ScanAttendance(row RowScanner) (models.Attendance, error) {
+	var attendance models.Attendance
+	err := row.Scan(
+		&attendance.BrotherID,
+		&attendance.EventID,
+		&attendance.AttendanceStatus,
+		&attendance.RollCall,
+		&attendance.FirstName,
+		&attendance.LastName,
+		&attendance.EventName,
+		&attendance.EventLocation,
+		&attendance.EventDate,
+		&attendance.EventCategory,
+	)
+	if err != nil {
+		return models.Attendance{}, err
+	}
+	return attendance, nil
+}
+
+// Returns the attendance of all events held during the semester, ordered by event date and roll call
+func SemesterAttendance(ctx context.Context, q Querier, semesterLabel string) ([]models.Attendance, error) {
+	start, end, err := SemesterDates(semesterLabel)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(AttendanceRecordQuery, "attendance") + `
+    WHERE e.eventDate >= $1 AND e.eventDate < $2
+    ORDER BY e.eventDate, e.eventID, b.rollCall`
+	rows, err := q.QueryContext(ctx, query, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []models.Attendance
+	for rows.Next() {
+		record, err := ScanAttendance(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
@@ -0,0 +1,248 @@
+package store
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+)
+
+// Identifies tt-db backup archives
+const BackupFormat = "tt-db-backup"
+
+// Version of the archive layout written by WriteBackup. Bump it when the layout changes
+const BackupFormatVersion = 1
+
+// Name of the manifest inside a backup archive
+const backupManifestFile = "manifest.json"
+
+// A table included in backups, the serial column whose sequence is reset after a restore,
+// and the schema version that created it. Archives of older schemas don't have the newer tables
+type backupTable struct {
+	name      string
+	serialKey string
+	orderBy   string
+	since     int
+}
+
+// Tables in backups, parents before children so rows can be restored in this order.
+// Users are not backed up: their password hashes should not leave the database
+var backupTables = []backupTable{
+	{"eventsCategory", "categoryID", "categoryID", 0},
+	{"events", "eventID", "eventID", 0},
+	// Before brothers, who reference them
+	{"pledgeClasses", "classID", "classID", 10},
+	{"brothers", "brotherID", "brotherID", 0},
+	{"semester", "semesterID", "semesterID", 0},
+	{"brotherStatus", "", "brotherID, semesterID", 0},
+	{"brotherMerges", "mergeID", "mergeID", 2},
+	{"brotherNotes", "noteID", "noteID", 4},
+	{"brotherNoteRevisions", "revisionID", "revisionID", 4},
+	// Only the metadata: attachment contents stay in the blob store
+	{"attachments", "attachmentID", "attachmentID", 5},
+	// Values are in brothers.customFields
+	{"customFields", "fieldID", "fieldID", 6},
+	{"tags", "tagID", "tagID", 7},
+	{"brotherTags", "", "brotherID, tagID", 7},
+	{"eventTags", "", "eventID, tagID", 7},
+	{"positions", "positionID", "positionID", 8},
+	{"positionTerms", "termID", "termID", 8},
+	{"lineage", "", "littleID", 9},
+	{"candidates", "candidateID", "candidateID", 11},
+	// After candidates, whose rush attendance it holds
+	{"attendance", "", "brotherID, eventID", 0},
+}
+
+// Tables backed up at a schema version
+func tablesAt(schemaVersion int) []backupTable {
+	var tables []backupTable
+	for _, table := range backupTables {
+		if table.since <= schemaVersion {
+			tables = append(tables, table)
+		}
+	}
+	return tables
+}
+
+// Names of the tables in backups of a schema version, in restore order
+func BackupTables(schemaVersion int) []string {
+	var names []string
+	for _, table := range tablesAt(schemaVersion) {
+		names = append(names, table.name)
+	}
+	return names
+}
+
+// Backup is the logical contents of the database: a manifest and every table as a JSON array of rows
+type Backup struct {
+	Manifest models.BackupManifest
+	Tables   map[string]json.RawMessage
+}
+
+// Latest migration applied to the database, 0 if none
+func schemaVersion(ctx context.Context, q Querier) (int, error) {
+	var version int
+	err := q.QueryRowContext(ctx, `SELECT COALESCE(max(version), 0) FROM schema_migrations`).Scan(&version)
+	return version, err
+}
+
+// Reads every backed up table. Run it in a read-only REPEATABLE READ transaction for a consistent snapshot
+func ExportBackup(ctx context.Context, q Querier) (Backup, error) {
+	version, err := schemaVersion(ctx, q)
+	if err != nil {
+		return Backup{}, fmt.Errorf("reading schema version: %w", err)
+	}
+
+	backup := Backup{
+		Manifest: models.BackupManifest{
+			Format:        BackupFormat,
+			FormatVersion: BackupFormatVersion,
+			SchemaVersion: version,
+			CreatedAt:     time.Now().UTC(),
+			Tables:        map[string]int{},
+		},
+		Tables: map[string]json.RawMessage{},
+	}
+	for _, table := range tablesAt(version) {
+		query := fmt.Sprintf(`SELECT COALESCE(json_agg(t ORDER BY %s), '[]'), count(*) FROM %s t`, table.orderBy, table.name)
+		var rows []byte
+		var count int
+		if err := q.QueryRowContext(ctx, query).Scan(&rows, &count); err != nil {
+			return Backup{}, fmt.Errorf("exporting %s: %w", table.name, err)
+		}
+		backup.Tables[table.name] = rows
+		backup.Manifest.Tables[table.name] = count
+	}
+	return backup, nil
+}
+
+// Writes the backup as a ZIP archive with manifest.json and one <table>.json file per table
+func WriteBackup(w io.Writer, backup Backup) error {
+	archive := zip.NewWriter(w)
+	write := func(name string, content []byte) error {
+		f, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: backup.Manifest.CreatedAt})
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(content)
+		return err
+	}
+
+	manifest, err := json.MarshalIndent(backup.Manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := write(backupManifestFile, manifest); err != nil {
+		return err
+	}
+	for _, table := range tablesAt(backup.Manifest.SchemaVersion) {
+		if err := write(table.name+".json", backup.Tables[table.name]); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// Reads a ZIP archive written by WriteBackup and checks its format version and row counts
+func ReadBackup(r io.ReaderAt, size int64) (Backup, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return Backup{}, fmt.Errorf("not a backup archive: %w", err)
+	}
+	read := func(name string) ([]byte, error) {
+		f, err := archive.Open(name)
+		if err != nil {
+			return nil, fmt.Errorf("archive has no %s", name)
+		}
+		defer f.Close()
+		return io.ReadAll(f)
+	}
+
+	backup := Backup{Tables: map[string]json.RawMessage{}}
+	manifest, err := read(backupManifestFile)
+	if err != nil {
+		return Backup{}, err
+	}
+	if err := json.Unmarshal(manifest, &backup.Manifest); err != nil {
+		return Backup{}, fmt.Errorf("invalid %s: %w", backupManifestFile, err)
+	}
+	if backup.Manifest.Format != BackupFormat {
+		return Backup{}, fmt.Errorf("not a %s archive (format %q)", BackupFormat, backup.Manifest.Format)
+	}
+	if backup.Manifest.FormatVersion != BackupFormatVersion {
+		return Backup{}, fmt.Errorf("unsupported backup format version %d, this build reads version %d", backup.Manifest.FormatVersion, BackupFormatVersion)
+	}
+
+	var errs []error
+	for _, table := range tablesAt(backup.Manifest.SchemaVersion) {
+		content, err := read(table.name + ".json")
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		var rows []json.RawMessage
+		if err := json.Unmarshal(content, &rows); err != nil {
+			errs = append(errs, fmt.Errorf("%s.json is not a JSON array: %w", table.name, err))
+			continue
+		}
+		if expected := backup.Manifest.Tables[table.name]; len(rows) != expected {
+			errs = append(errs, fmt.Errorf("%s.json has %d rows, the manifest lists %d", table.name, len(rows), expected))
+		}
+		backup.Tables[table.name] = content
+	}
+	if len(errs) > 0 {
+		return Backup{}, errors.Join(errs...)
+	}
+	return backup, nil
+}
+
+// Inserts every table of the backup, keeping the original IDs, and moves the ID sequences past them.
+// The database must be at the backup's schema version and its backed up tables must be empty.
+// Run it in a transaction so a failed restore leaves the database unchanged
+func RestoreBackup(ctx context.Context, q Querier, backup Backup) error {
+	version, err := schemaVersion(ctx, q)
+	if err != nil {
+		return fmt.Errorf("reading schema version: %w", err)
+	}
+	if version != backup.Manifest.SchemaVersion {
+		return fmt.Errorf("backup is from schema version %d but the database is at version %d. Restore into a database migrated to version %d, then migrate it",
+			backup.Manifest.SchemaVersion, version, backup.Manifest.SchemaVersion)
+	}
+
+	var notEmpty []string
+	for _, table := range tablesAt(version) {
+		var exists bool
+		query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s)`, table.name)
+		if err := q.QueryRowContext(ctx, query).Scan(&exists); err != nil {
+			return fmt.Errorf("checking %s: %w", table.name, err)
+		}
+		if exists {
+			notEmpty = append(notEmpty, table.name)
+		}
+	}
+	if len(notEmpty) > 0 {
+		return fmt.Errorf("restore needs empty tables, but these have rows: %s", strings.Join(notEmpty, ", "))
+	}
+
+	for _, table := range tablesAt(version) {
+		query := fmt.Sprintf(`INSERT INTO %[1]s SELECT * FROM json_populate_recordset(NULL::%[1]s, $1)`, table.name)
+		if _, err := q.ExecContext(ctx, query, string(backup.Tables[table.name])); err != nil {
+			return fmt.Errorf("restoring %s: %w", table.name, err)
+		}
+		if table.serialKey == "" {
+			continue
+		}
+		query = fmt.Sprintf(`SELECT setval(pg_get_serial_sequence('%[1]s', '%[2]s'), COALESCE(max(%[2]s), 0) + 1, false) FROM %[1]s`,
+			strings.ToLower(table.name), strings.ToLower(table.serialKey))
+		if _, err := q.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("resetting %s sequence: %w", table.name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package store
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+)
+
+// Returns a backup with one brother and every other table empty
+func testBackup() Backup {
+	backup := Backup{
+		Manifest: models.BackupManifest{
+			Format:        BackupFormat,
+			FormatVersion: BackupFormatVersion,
+			SchemaVersion: 3,
+			CreatedAt:     time.Date(2024, time.September, 1, 12, 0, 0, 0, time.UTC),
+			Tables:        map[string]int{},
+		},
+		Tables: map[string]json.RawMessage{},
+	}
+	for _, table := range backupTables {
+		backup.Tables[table.name] = json.RawMessage(`[]`)
+		backup.Manifest.Tables[table.name] = 0
+	}
+	backup.Tables["brothers"] = json.RawMessage(`[{"brotherid":1,"rollcall":1,"firstname":"John"}]`)
+	backup.Manifest.Tables["brothers"] = 1
+	return backup
+}
+
+func TestBackupArchiveRoundTrip(t *testing.T) {
+	var archive bytes.Buffer
+	if err := WriteBackup(&archive, testBackup()); err != nil {
+		t.Fatalf("WriteBackup: %v", err)
+	}
+
+	backup, err := ReadBackup(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+	if err != nil {
+		t.Fatalf("ReadBackup: %v", err)
+	}
+	if backup.Manifest.SchemaVersion != 3 || backup.Manifest.Tables["brothers"] != 1 {
+		t.Errorf("Unexpected manifest: %+v", backup.Manifest)
+	}
+	if !strings.Contains(string(backup.Tables["brothers"]), `"firstname":"John"`) {
+		t.Errorf("Expected brothers rows to round-trip. Got %s", backup.Tables["brothers"])
+	}
+}
+
+func TestReadBackupRejectsUnknownFormatVersion(t *testing.T) {
+	backup := testBackup()
+	backup.Manifest.FormatVersion = BackupFormatVersion + 1
+	var archive bytes.Buffer
+	if err := WriteBackup(&archive, backup); err != nil {
+		t.Fatalf("WriteBackup: %v", err)
+	}
+
+	_, err := ReadBackup(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+	if err == nil || !strings.Contains(err.Error(), "unsupported backup format version") {
+		t.Errorf("Expected format version error. Got %v", err)
+	}
+}
+
+func TestReadBackupChecksRowCounts(t *testing.T) {
+	backup := testBackup()
+	backup.Manifest.Tables["brothers"] = 2
+	var archive bytes.Buffer
+	if err := WriteBackup(&archive, backup); err != nil {
+		t.Fatalf("WriteBackup: %v", err)
+	}
+
+	_, err := ReadBackup(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+	if err == nil || !strings.Contains(err.Error(), "brothers.json has 1 rows, the manifest lists 2") {
+		t.Errorf("Expected row count error. Got %v", err)
+	}
+}
+
+func TestReadBackupRequiresEveryTable(t *testing.T) {
+	var archive bytes.Buffer
+	w := zip.NewWriter(&archive)
+	f, _ := w.Create(backupManifestFile)
+	manifest, _ := json.Marshal(testBackup().Manifest)
+	f.Write(manifest)
+	w.Close()
+
+	_, err := ReadBackup(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+	if err == nil || !strings.Contains(err.Error(), "archive has no brothers.json") {
+		t.Errorf("Expected missing table error. Got %v", err)
+	}
+}
+
+func TestBackupTablesFollowSchemaVersion(t *testing.T) {
+	var archive bytes.Buffer
+	if err := WriteBackup(&archive, testBackup()); err != nil {
+		t.Fatalf("WriteBackup: %v", err)
+	}
+	if strings.Contains(archive.String(), "brotherNotes.json") {
+		t.Errorf("Expected no brotherNotes.json in a schema 3 archive")
+	}
+	if _, err := ReadBackup(bytes.NewReader(archive.Bytes()), int64(archive.Len())); err != nil {
+		t.Errorf("Expected a schema 3 archive without notes to be valid. Got %v", err)
+	}
+
+	tables := strings.Join(BackupTables(4), ",")
+	if !strings.Contains(tables, "brotherNotes,brotherNoteRevisions") {
+		t.Errorf("Expected notes after their brothers at schema 4. Got %s", tables)
+	}
+}
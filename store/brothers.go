@@ -0,0 +1,125 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+)
+
+// Columns of the brothers table in the order expected by ScanBrother
+const BrotherColumns = "brotherID, rollCall, firstName, lastName, major, status, className, email, phoneNumber, badStanding, customFields, classID"
+
+// Scans a row selected with BrotherColumns into a Brother
+func ScanBrother(row RowScanner) (models.Brother, error) {
+	var brother models.Brother
+	var customFields []byte
+	err := row.Scan(
+		&brother.BrotherID,
+		&brother.RollCall,
+		&brother.FirstName,
+		&brother.LastName,
+		&brother.Major,
+		&brother.Status,
+		&brother.Class,
+		&brother.Email,
+		&brother.PhoneNumber,
+		&brother.BadStanding,
+		&customFields,
+		&brother.ClassID,
+	)
+	if err != nil {
+		return models.Brother{}, err
+	}
+	if err := json.Unmarshal(customFields, &brother.CustomFields); err != nil {
+		return models.Brother{}, fmt.Errorf("decoding custom fields of brother %d: %w", brother.BrotherID, err)
+	}
+	return brother, nil
+}
+
+// Inserts a brother and returns the created row. BrotherID is ignored.
+// Custom field values must have been checked with ValidateCustomFieldValues and the class set with ResolveBrotherClass
+func InsertBrother(ctx context.Context, q Querier, brother models.Brother) (models.Brother, error) {
+	customFields := []byte("{}")
+	if len(brother.CustomFields) > 0 {
+		var err error
+		if customFields, err = json.Marshal(brother.CustomFields); err != nil {
+			return models.Brother{}, err
+		}
+	}
+	query := `
+	INSERT INTO brothers (rollCall, firstName, lastName, major, status, className, email, phoneNumber, badStanding, customFields, classID)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	RETURNING ` + BrotherColumns
+	row := q.QueryRowContext(
+		ctx,
+		query,
+		brother.RollCall,
+		brother.FirstName,
+		brother.LastName,
+		brother.Major,
+		brother.Status,
+		brother.Class,
+		brother.Email,
+		brother.PhoneNumber,
+		brother.BadStanding,
+		string(customFields),
+		brother.ClassID,
+	)
+	return ScanBrother(row)
+}
+
+// Reports whether a brother with the ID exists
+func BrotherExists(ctx context.Context, q Querier, brotherID int) (bool, error) {
+	var exists bool
+	err := q.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM brothers WHERE brotherID = $1)`, brotherID).Scan(&exists)
+	return exists, err
+}
+
+// Conditions on the brothers ListBrothers returns. The zero value matches every brother
+type BrotherFilter struct {
+	CustomFields []CustomFieldFilter
+	// Names of tags brothers must all have
+	Tags []string
+}
+
+// Returns the brothers matching every condition of the filter, ordered by ID
+func ListBrothers(ctx context.Context, q Querier, filter BrotherFilter) ([]models.Brother, error) {
+	var conditions []string
+	var args []interface{}
+	for _, tag := range filter.Tags {
+		args = append(args, tag)
+		conditions = append(conditions, TagCondition(BrotherTags, "brotherID", len(args)))
+	}
+	for _, filter := range filter.CustomFields {
+		condition, filterArgs, err := filter.sql(len(args) + 1)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+		args = append(args, filterArgs...)
+	}
+	query := "SELECT " + BrotherColumns + " FROM brothers"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY brotherID"
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	brothers := []models.Brother{}
+	for rows.Next() {
+		brother, err := ScanBrother(rows)
+		if err != nil {
+			return nil, err
+		}
+		brothers = append(brothers, brother)
+	}
+	return brothers, rows.Err()
+}
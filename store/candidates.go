@@ -0,0 +1,255 @@
+package store
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+)
+
+// Status of a candidate's first semester as a brother when none is given
+const defaultConvertedStatus = "Active"
+
+// Columns of candidates in the order ScanCandidate reads them
+const CandidateColumns = `candidateID, firstName, lastName, email, phoneNumber, major, semester, stage, brotherID, createdAt, updatedAt`
+
+// Scans a candidates row selected with CandidateColumns
+func ScanCandidate(row RowScanner) (models.Candidate, error) {
+	var candidate models.Candidate
+	err := row.Scan(
+		&candidate.CandidateID,
+		&candidate.FirstName,
+		&candidate.LastName,
+		&candidate.Email,
+		&candidate.PhoneNumber,
+		&candidate.Major,
+		&candidate.Semester,
+		&candidate.Stage,
+		&candidate.BrotherID,
+		&candidate.CreatedAt,
+		&candidate.UpdatedAt,
+	)
+	if err != nil {
+		return models.Candidate{}, err
+	}
+	return candidate, nil
+}
+
+// Returns the candidates at a stage and rushing in a semester, newest first. Empty values match every candidate
+func Candidates(ctx context.Context, q Querier, stage string, semester string) ([]models.Candidate, error) {
+	query := `SELECT ` + CandidateColumns + ` FROM candidates
+    WHERE ($1 = '' OR stage = $1) AND ($2 = '' OR lower(semester) = lower($2))
+    ORDER BY candidateID DESC`
+	rows, err := q.QueryContext(ctx, query, stage, semester)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	candidates := []models.Candidate{}
+	for rows.Next() {
+		candidate, err := ScanCandidate(rows)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates, rows.Err()
+}
+
+// Returns a candidate. Returns sql.ErrNoRows if there is none with the ID
+func GetCandidate(ctx context.Context, q Querier, candidateID int) (models.Candidate, error) {
+	return ScanCandidate(q.QueryRowContext(ctx, `SELECT `+CandidateColumns+` FROM candidates WHERE candidateID = $1`, candidateID))
+}
+
+// Returns a candidate and locks it until the transaction ends. Returns sql.ErrNoRows if there is none with the ID
+func LockCandidate(ctx context.Context, tx Querier, candidateID int) (models.Candidate, error) {
+	return ScanCandidate(tx.QueryRowContext(ctx, `SELECT `+CandidateColumns+` FROM candidates WHERE candidateID = $1 FOR UPDATE`, candidateID))
+}
+
+// Creates a candidate, at the interested stage unless another one is set
+func InsertCandidate(ctx context.Context, q Querier, candidate models.Candidate) (models.Candidate, error) {
+	if candidate.Stage == "" {
+		candidate.Stage = models.StageInterested
+	}
+	query := `
+    INSERT INTO candidates (firstName, lastName, email, phoneNumber, major, semester, stage)
+    VALUES ($1, $2, $3, $4, $5, $6, $7)
+    RETURNING ` + CandidateColumns
+	row := q.QueryRowContext(ctx, query, candidate.FirstName, candidate.LastName, candidate.Email, candidate.PhoneNumber,
+		candidate.Major, candidate.Semester, candidate.Stage)
+	return ScanCandidate(row)
+}
+
+// Changes the details and stage of a candidate. Returns sql.ErrNoRows if there is none with the ID
+func UpdateCandidate(ctx context.Context, q Querier, candidate models.Candidate) (models.Candidate, error) {
+	query := `
+    UPDATE candidates
+    SET firstName = $2, lastName = $3, email = $4, phoneNumber = $5, major = $6, semester = $7, stage = $8, updatedAt = now()
+    WHERE candidateID = $1
+    RETURNING ` + CandidateColumns
+	row := q.QueryRowContext(ctx, query, candidate.CandidateID, candidate.FirstName, candidate.LastName, candidate.Email,
+		candidate.PhoneNumber, candidate.Major, candidate.Semester, candidate.Stage)
+	return ScanCandidate(row)
+}
+
+// Deletes a candidate and their rush attendance. Attendance records of the Brother converted from them stay, unlinked
+// from the candidate. Run it in a transaction. Returns sql.ErrNoRows if there is none with the ID
+func DeleteCandidate(ctx context.Context, q Querier, candidateID int) (models.Candidate, error) {
+	if _, err := q.ExecContext(ctx, `DELETE FROM attendance WHERE candidateID = $1 AND brotherID IS NULL`, candidateID); err != nil {
+		return models.Candidate{}, err
+	}
+	return ScanCandidate(q.QueryRowContext(ctx, `DELETE FROM candidates WHERE candidateID = $1 RETURNING `+CandidateColumns, candidateID))
+}
+
+// Selects candidate attendance with the candidate and event, in the order queryCandidateAttendance reads them
+const candidateAttendanceQuery = `
+    SELECT ca.candidateID, ca.eventID, ca.attendanceStatus, c.firstName, c.lastName, e.eventName, e.eventDate
+    FROM attendance ca
+    JOIN candidates c ON c.candidateID = ca.candidateID
+    JOIN events e ON e.eventID = ca.eventID`
+
+func queryCandidateAttendance(ctx context.Context, q Querier, query string, args ...interface{}) ([]models.CandidateAttendance, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []models.CandidateAttendance{}
+	for rows.Next() {
+		var record models.CandidateAttendance
+		err := rows.Scan(&record.CandidateID, &record.EventID, &record.AttendanceStatus, &record.FirstName, &record.LastName,
+			&record.EventName, &record.EventDate)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+// Returns the rush events a candidate was recorded at, oldest first
+func CandidateAttendanceOf(ctx context.Context, q Querier, candidateID int) ([]models.CandidateAttendance, error) {
+	return queryCandidateAttendance(ctx, q, candidateAttendanceQuery+` WHERE ca.candidateID = $1 ORDER BY e.eventDate, e.eventID`, candidateID)
+}
+
+// Returns the candidates recorded at an event, by name
+func EventCandidates(ctx context.Context, q Querier, eventID int) ([]models.CandidateAttendance, error) {
+	return queryCandidateAttendance(ctx, q, candidateAttendanceQuery+` WHERE ca.eventID = $1 ORDER BY c.lastName, c.firstName`, eventID)
+}
+
+// Records the attendance of a candidate at an event, replacing any previous record. A candidate at the interested
+// stage who was present moves to the attended stage. A converted candidate's record is also their Brother's.
+// Fails with attendance_eventid_fkey if the event doesn't exist
+func SetCandidateAttendance(ctx context.Context, q Querier, candidateID int, eventID int, status string) (models.CandidateAttendance, error) {
+	_, err := q.ExecContext(ctx, `
+    INSERT INTO attendance (candidateID, brotherID, eventID, attendanceStatus)
+    SELECT candidateID, brotherID, $2, $3 FROM candidates WHERE candidateID = $1
+    ON CONFLICT (candidateID, eventID) DO UPDATE SET attendanceStatus = EXCLUDED.attendanceStatus`,
+		candidateID, eventID, status)
+	if err != nil {
+		return models.CandidateAttendance{}, err
+	}
+	if status == "Present" {
+		_, err = q.ExecContext(ctx, `UPDATE candidates SET stage = $2, updatedAt = now() WHERE candidateID = $1 AND stage = $3`,
+			candidateID, models.StageAttended, models.StageInterested)
+		if err != nil {
+			return models.CandidateAttendance{}, err
+		}
+	}
+	records, err := queryCandidateAttendance(ctx, q, candidateAttendanceQuery+` WHERE ca.candidateID = $1 AND ca.eventID = $2`, candidateID, eventID)
+	if err != nil || len(records) == 0 {
+		return models.CandidateAttendance{}, err
+	}
+	return records[0], nil
+}
+
+// Removes the attendance of a candidate at an event, and of the Brother converted from them. Reports whether there was one
+func RemoveCandidateAttendance(ctx context.Context, q Querier, candidateID int, eventID int) (bool, error) {
+	result, err := q.ExecContext(ctx, `DELETE FROM attendance WHERE candidateID = $1 AND eventID = $2`, candidateID, eventID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// Returns the number of candidates at each stage, in pipeline order, for a semester or every semester if it is empty
+func StageCounts(ctx context.Context, q Querier, semester string) ([]models.StageCount, error) {
+	rows, err := q.QueryContext(ctx, `SELECT stage, count(*) FROM candidates WHERE $1 = '' OR lower(semester) = lower($1) GROUP BY stage`, semester)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := map[string]int{}
+	for rows.Next() {
+		var stage string
+		var count int
+		if err := rows.Scan(&stage, &count); err != nil {
+			return nil, err
+		}
+		counts[stage] = count
+	}
+	return stageCounts(counts), rows.Err()
+}
+
+// Lists counts by stage in pipeline order, with zeros for missing stages
+func stageCounts(counts map[string]int) []models.StageCount {
+	stages := make([]models.StageCount, 0, len(models.CandidateStages))
+	for _, stage := range models.CandidateStages {
+		stages = append(stages, models.StageCount{Stage: stage, Candidates: counts[stage]})
+	}
+	return stages
+}
+
+// Reports whether a candidate at the stage can become a brother: they must have accepted their bid
+func CanConvert(stage string) bool {
+	return slices.Contains([]string{models.StageAccepted, models.StagePledged}, stage)
+}
+
+// Returns the brother to create from a candidate, with the conversion's values overriding the candidate's.
+// The class still has to be set with ResolveBrotherClass
+func BrotherFromCandidate(candidate models.Candidate, conversion models.CandidateConversion) models.Brother {
+	brother := models.Brother{
+		RollCall:     conversion.RollCall,
+		FirstName:    strings.TrimSpace(candidate.FirstName),
+		LastName:     strings.TrimSpace(candidate.LastName),
+		Major:        strings.TrimSpace(candidate.Major),
+		Status:       conversion.Status,
+		Class:        conversion.ClassName,
+		ClassID:      conversion.ClassID,
+		Email:        candidate.Email,
+		PhoneNumber:  candidate.PhoneNumber,
+		CustomFields: conversion.CustomFields,
+	}
+	if major := strings.TrimSpace(conversion.Major); major != "" {
+		brother.Major = major
+	}
+	if brother.Status == "" {
+		brother.Status = defaultConvertedStatus
+	}
+	return brother
+}
+
+// Links a candidate to the brother created from them, moves them to the pledged stage and makes their rush
+// attendance the brother's. Returns the updated candidate and the number of attendance records linked
+func ConvertCandidate(ctx context.Context, q Querier, candidateID int, brotherID int) (models.Candidate, int, error) {
+	result, err := q.ExecContext(ctx, `
+    UPDATE attendance ca SET brotherID = $2
+    WHERE ca.candidateID = $1 AND ca.brotherID IS NULL
+        AND NOT EXISTS (SELECT 1 FROM attendance a WHERE a.brotherID = $2 AND a.eventID = ca.eventID)`, candidateID, brotherID)
+	if err != nil {
+		return models.Candidate{}, 0, err
+	}
+	linked, err := result.RowsAffected()
+	if err != nil {
+		return models.Candidate{}, 0, err
+	}
+
+	query := `UPDATE candidates SET brotherID = $2, stage = $3, updatedAt = now() WHERE candidateID = $1 RETURNING ` + CandidateColumns
+	candidate, err := ScanCandidate(q.QueryRowContext(ctx, query, candidateID, brotherID, models.StagePledged))
+	return candidate, int(linked), err
+}
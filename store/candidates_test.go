@@ -0,0 +1,63 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+)
+
+func TestCanConvert(t *testing.T) {
+	tests := map[string]bool{
+		models.StageInterested:  false,
+		models.StageAttended:    false,
+		models.StageBidExtended: false,
+		models.StageAccepted:    true,
+		models.StagePledged:     true,
+	}
+	for stage, expected := range tests {
+		if got := CanConvert(stage); got != expected {
+			t.Errorf("CanConvert(%q): expected %v. Got %v", stage, expected, got)
+		}
+	}
+}
+
+func TestBrotherFromCandidate(t *testing.T) {
+	candidate := models.Candidate{FirstName: " John ", LastName: "Doe", Email: "john@gmail.com", PhoneNumber: "(123) 456-7890",
+		Major: "Computer Science", Semester: "Fall 2024", Stage: models.StageAccepted}
+
+	brother := BrotherFromCandidate(candidate, models.CandidateConversion{RollCall: 150})
+	if brother.RollCall != 150 || brother.FirstName != "John" || brother.LastName != "Doe" || brother.Email != "john@gmail.com" ||
+		brother.PhoneNumber != "(123) 456-7890" || brother.Major != "Computer Science" {
+		t.Errorf("Expected the candidate's details. Got %+v", brother)
+	}
+	if brother.Status != "Active" || brother.Class != "" || brother.ClassID != nil {
+		t.Errorf("Expected an Active Brother without a class. Got %+v", brother)
+	}
+
+	classID := 3
+	conversion := models.CandidateConversion{RollCall: 150, Status: "Co-op", Major: " Civil Engineering", ClassName: "Chi", ClassID: &classID,
+		CustomFields: map[string]interface{}{"shirtSize": "M"}}
+	brother = BrotherFromCandidate(candidate, conversion)
+	if brother.Status != "Co-op" || brother.Major != "Civil Engineering" || brother.Class != "Chi" || *brother.ClassID != 3 || brother.CustomFields["shirtSize"] != "M" {
+		t.Errorf("Expected the conversion's values to override the candidate's. Got %+v", brother)
+	}
+}
+
+func TestStageCounts(t *testing.T) {
+	counts := stageCounts(map[string]int{models.StageAccepted: 2, models.StageInterested: 5})
+	expected := []models.StageCount{
+		{Stage: models.StageInterested, Candidates: 5},
+		{Stage: models.StageAttended},
+		{Stage: models.StageBidExtended},
+		{Stage: models.StageAccepted, Candidates: 2},
+		{Stage: models.StagePledged},
+	}
+	if len(counts) != len(expected) {
+		t.Fatalf("Expected %v. Got %v", expected, counts)
+	}
+	for i := range expected {
+		if counts[i] != expected[i] {
+			t.Errorf("Expected %v at %d. Got %v", expected[i], i, counts[i])
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+)
+
+// Letters pledge classes are named with, in order
+var GreekLetters = []string{
+	"Alpha", "Beta", "Gamma", "Delta", "Epsilon", "Zeta", "Eta", "Theta", "Iota", "Kappa", "Lambda", "Mu",
+	"Nu", "Xi", "Omicron", "Pi", "Rho", "Sigma", "Tau", "Upsilon", "Phi", "Chi", "Psi", "Omega",
+}
+
+// Returns a class name as capitalized Greek letters separated by single spaces, e.g. "Alpha Beta" for " alpha  BETA".
+// Fails if a word isn't the name of a Greek letter
+func NormalizeClassName(name string) (string, error) {
+	words := strings.Fields(name)
+	if len(words) == 0 {
+		return "", errors.New("name is required")
+	}
+	for i, word := range words {
+		letter := ""
+		for _, known := range GreekLetters {
+			if strings.EqualFold(word, known) {
+				letter = known
+				break
+			}
+		}
+		if letter == "" {
+			return "", fmt.Errorf("name must be made of Greek letters, e.g. \"Alpha Beta\", but %q isn't one", word)
+		}
+		words[i] = letter
+	}
+	return strings.Join(words, " "), nil
+}
+
+// Checks the semester and roll call range of a pledge class. The name is checked by NormalizeClassName
+func ValidatePledgeClass(class models.PledgeClass) []models.FieldError {
+	var fieldErrors []models.FieldError
+	if class.Semester != "" {
+		if _, _, err := SemesterDates(class.Semester); err != nil {
+			fieldErrors = append(fieldErrors, models.FieldError{Field: "semester", Rule: "semester", Message: err.Error()})
+		}
+	}
+	if (class.FirstRollCall == nil) != (class.LastRollCall == nil) {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "lastRollCall", Rule: "required_with",
+			Message: "firstRollCall and lastRollCall must be set together"})
+	} else if class.FirstRollCall != nil && *class.LastRollCall < *class.FirstRollCall {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "lastRollCall", Rule: "gtefield",
+			Message: "lastRollCall must be at least firstRollCall"})
+	}
+	return fieldErrors
+}
+
+// Reports whether a roll call is allowed in a class: in its range, or any if it has none
+func InRollCallRange(class models.PledgeClass, rollCall int) bool {
+	if class.FirstRollCall == nil || class.LastRollCall == nil {
+		return true
+	}
+	return rollCall >= *class.FirstRollCall && rollCall <= *class.LastRollCall
+}
+
+// Columns of pledgeClasses in the order ScanPledgeClass reads them
+const PledgeClassColumns = `classID, name, semester, COALESCE(to_char(initiationDate, 'YYYY-MM-DD'), ''), firstRollCall, lastRollCall, createdAt`
+
+// Scans a pledgeClasses row selected with PledgeClassColumns
+func ScanPledgeClass(row RowScanner) (models.PledgeClass, error) {
+	var class models.PledgeClass
+	err := row.Scan(&class.ClassID, &class.Name, &class.Semester, &class.InitiationDate, &class.FirstRollCall, &class.LastRollCall, &class.CreatedAt)
+	if err != nil {
+		return models.PledgeClass{}, err
+	}
+	return class, nil
+}
+
+// Returns every pledge class, in roll call order. Classes without a range come last
+func PledgeClasses(ctx context.Context, q Querier) ([]models.PledgeClass, error) {
+	rows, err := q.QueryContext(ctx, `SELECT `+PledgeClassColumns+` FROM pledgeClasses ORDER BY firstRollCall NULLS LAST, classID`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	classes := []models.PledgeClass{}
+	for rows.Next() {
+		class, err := ScanPledgeClass(rows)
+		if err != nil {
+			return nil, err
+		}
+		classes = append(classes, class)
+	}
+	return classes, rows.Err()
+}
+
+// Returns a pledge class. Returns sql.ErrNoRows if there is none with the ID
+func GetPledgeClass(ctx context.Context, q Querier, classID int) (models.PledgeClass, error) {
+	return ScanPledgeClass(q.QueryRowContext(ctx, `SELECT `+PledgeClassColumns+` FROM pledgeClasses WHERE classID = $1`, classID))
+}
+
+// Creates a pledge class. Fails with pledgeclasses_name_key if one already has the name, in any case
+func InsertPledgeClass(ctx context.Context, q Querier, class models.PledgeClass) (models.PledgeClass, error) {
+	query := `
+    INSERT INTO pledgeClasses (name, semester, initiationDate, firstRollCall, lastRollCall)
+    VALUES ($1, $2, NULLIF($3, '')::date, $4, $5)
+    RETURNING ` + PledgeClassColumns
+	return ScanPledgeClass(q.QueryRowContext(ctx, query, class.Name, class.Semester, class.InitiationDate, class.FirstRollCall, class.LastRollCall))
+}
+
+// Changes a pledge class and the class name of its members. Returns sql.ErrNoRows if there is none with the ID
+func UpdatePledgeClass(ctx context.Context, q Querier, class models.PledgeClass) (models.PledgeClass, error) {
+	query := `
+    WITH updated AS (
+        UPDATE pledgeClasses
+        SET name = $2, semester = $3, initiationDate = NULLIF($4, '')::date, firstRollCall = $5, lastRollCall = $6
+        WHERE classID = $1
+        RETURNING *
+    ), renamed AS (
+        UPDATE brothers b SET className = updated.name FROM updated WHERE b.classID = updated.classID
+    )
+    SELECT ` + PledgeClassColumns + ` FROM updated`
+	row := q.QueryRowContext(ctx, query, class.ClassID, class.Name, class.Semester, class.InitiationDate, class.FirstRollCall, class.LastRollCall)
+	return ScanPledgeClass(row)
+}
+
+// Deletes a pledge class. Fails with brothers_classid_fkey if it has members. Returns sql.ErrNoRows if there is none with the ID
+func DeletePledgeClass(ctx context.Context, q Querier, classID int) (models.PledgeClass, error) {
+	return ScanPledgeClass(q.QueryRowContext(ctx, `DELETE FROM pledgeClasses WHERE classID = $1 RETURNING `+PledgeClassColumns, classID))
+}
+
+// Returns the number of brothers in a pledge class
+func CountClassMembers(ctx context.Context, q Querier, classID int) (int, error) {
+	var count int
+	err := q.QueryRowContext(ctx, `SELECT count(*) FROM brothers WHERE classID = $1`, classID).Scan(&count)
+	return count, err
+}
+
+// Returns the number of members of a class whose roll call is outside its range
+func CountOutOfRange(ctx context.Context, q Querier, class models.PledgeClass) (int, error) {
+	if class.FirstRollCall == nil || class.LastRollCall == nil {
+		return 0, nil
+	}
+	var count int
+	err := q.QueryRowContext(ctx, `SELECT count(*) FROM brothers WHERE classID = $1 AND rollCall NOT BETWEEN $2 AND $3`,
+		class.ClassID, *class.FirstRollCall, *class.LastRollCall).Scan(&count)
+	return count, err
+}
+
+// Returns the name of another class whose roll call range overlaps the class's, or "" if none does
+func OverlappingClass(ctx context.Context, q Querier, class models.PledgeClass) (string, error) {
+	if class.FirstRollCall == nil || class.LastRollCall == nil {
+		return "", nil
+	}
+	var name string
+	err := q.QueryRowContext(ctx, `
+    SELECT name FROM pledgeClasses
+    WHERE classID <> $1 AND firstRollCall <= $3 AND lastRollCall >= $2
+    ORDER BY firstRollCall LIMIT 1`,
+		class.ClassID, *class.FirstRollCall, *class.LastRollCall).Scan(&name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return name, err
+}
+
+// Returns the members of a pledge class in roll call order
+func ClassBrothers(ctx context.Context, q Querier, classID int) ([]models.Brother, error) {
+	rows, err := q.QueryContext(ctx, `SELECT `+BrotherColumns+` FROM brothers WHERE classID = $1 ORDER BY rollCall`, classID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	brothers := []models.Brother{}
+	for rows.Next() {
+		brother, err := ScanBrother(rows)
+		if err != nil {
+			return nil, err
+		}
+		brothers = append(brothers, brother)
+	}
+	return brothers, rows.Err()
+}
+
+// Returns the share of the attendance records of a class's members that are Present, 0 without records
+func ClassAttendanceRate(ctx context.Context, q Querier, classID int) (float64, error) {
+	var rate float64
+	err := q.QueryRowContext(ctx, `
+    SELECT COALESCE(avg(CASE WHEN a.attendanceStatus = 'Present' THEN 1.0 ELSE 0.0 END), 0)
+    FROM attendance a
+    JOIN brothers b ON b.brotherID = a.brotherID
+    WHERE b.classID = $1`, classID).Scan(&rate)
+	return rate, err
+}
+
+// Counts the members of a class by status. AttendanceRate is left for ClassAttendanceRate
+func ClassStatsOf(brothers []models.Brother) models.ClassStats {
+	stats := models.ClassStats{Size: len(brothers), Statuses: map[string]int{}}
+	for _, brother := range brothers {
+		stats.Statuses[brother.Status]++
+		if brother.BadStanding != 0 {
+			stats.BadStanding++
+		}
+	}
+	return stats
+}
+
+// Sets the class of a brother about to be saved from ClassID, or else from the class name, creating the class if
+// none has the name. Fills in the class name and checks the roll call is in the class's range. Returns field errors
+// for an unknown ClassID, an invalid new class name or a roll call out of range
+func ResolveBrotherClass(ctx context.Context, q Querier, brother *models.Brother) ([]models.FieldError, error) {
+	var class models.PledgeClass
+	var err error
+	switch {
+	case brother.ClassID != nil:
+		class, err = GetPledgeClass(ctx, q, *brother.ClassID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return []models.FieldError{{Field: "classID", Rule: "exists", Message: fmt.Sprintf("Pledge class %d does not exist", *brother.ClassID)}}, nil
+		}
+	case strings.TrimSpace(brother.Class) != "":
+		// Classes created from the old free-text names may not be Greek letters, so look those up as they are
+		name, nameErr := NormalizeClassName(brother.Class)
+		if nameErr != nil {
+			name = strings.TrimSpace(brother.Class)
+		}
+		class, err = ScanPledgeClass(q.QueryRowContext(ctx, `SELECT `+PledgeClassColumns+` FROM pledgeClasses WHERE lower(name) = lower($1)`, name))
+		if errors.Is(err, sql.ErrNoRows) {
+			if nameErr != nil {
+				return []models.FieldError{{Field: "className", Rule: "greek", Message: nameErr.Error()}}, nil
+			}
+			class, err = InsertPledgeClass(ctx, q, models.PledgeClass{Name: name})
+		}
+	default:
+		brother.Class = ""
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	brother.ClassID = &class.ClassID
+	brother.Class = class.Name
+	if !InRollCallRange(class, brother.RollCall) {
+		return []models.FieldError{{Field: "rollCall", Rule: "range", Message: fmt.Sprintf("rollCall must be between %d and %d for the %s class",
+			*class.FirstRollCall, *class.LastRollCall, class.Name)}}, nil
+	}
+	return nil, nil
+}
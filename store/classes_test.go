@@ -0,0 +1,77 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+)
+
+func TestNormalizeClassName(t *testing.T) {
+	tests := map[string]string{
+		"Omicron":          "Omicron",
+		" alpha  BETA ":    "Alpha Beta",
+		"chi":              "Chi",
+		"Omega Alpha Beta": "Omega Alpha Beta",
+	}
+	for name, expected := range tests {
+		if got, err := NormalizeClassName(name); err != nil || got != expected {
+			t.Errorf("NormalizeClassName(%q): expected %q. Got %q, %v", name, expected, got, err)
+		}
+	}
+
+	for _, name := range []string{"", "  ", "Fall 2024", "Alpha Bet"} {
+		if _, err := NormalizeClassName(name); err == nil {
+			t.Errorf("NormalizeClassName(%q): expected an error", name)
+		}
+	}
+}
+
+func TestValidatePledgeClass(t *testing.T) {
+	first, last := 10, 20
+	valid := models.PledgeClass{Name: "Chi", Semester: "Fall 2024", FirstRollCall: &first, LastRollCall: &last}
+	if fieldErrors := ValidatePledgeClass(valid); len(fieldErrors) != 0 {
+		t.Errorf("Expected no errors. Got %v", fieldErrors)
+	}
+	if fieldErrors := ValidatePledgeClass(models.PledgeClass{Name: "Chi"}); len(fieldErrors) != 0 {
+		t.Errorf("Expected a class without semester and range to be valid. Got %v", fieldErrors)
+	}
+
+	invalid := []models.PledgeClass{
+		{Name: "Chi", Semester: "Winter 2024"},
+		{Name: "Chi", FirstRollCall: &first},
+		{Name: "Chi", FirstRollCall: &last, LastRollCall: &first},
+	}
+	for _, class := range invalid {
+		if fieldErrors := ValidatePledgeClass(class); len(fieldErrors) != 1 {
+			t.Errorf("Expected an error for %+v. Got %v", class, fieldErrors)
+		}
+	}
+}
+
+func TestInRollCallRange(t *testing.T) {
+	first, last := 10, 20
+	class := models.PledgeClass{FirstRollCall: &first, LastRollCall: &last}
+	for rollCall, expected := range map[int]bool{9: false, 10: true, 15: true, 20: true, 21: false} {
+		if got := InRollCallRange(class, rollCall); got != expected {
+			t.Errorf("InRollCallRange(10 to 20, %d): expected %v", rollCall, expected)
+		}
+	}
+	if !InRollCallRange(models.PledgeClass{}, 999) {
+		t.Error("Expected any roll call in a class without a range")
+	}
+}
+
+func TestClassStatsOf(t *testing.T) {
+	brothers := []models.Brother{
+		{Status: "Active"},
+		{Status: "Active", BadStanding: 1},
+		{Status: "Alumnus"},
+	}
+	stats := ClassStatsOf(brothers)
+	if stats.Size != 3 || stats.Statuses["Active"] != 2 || stats.Statuses["Alumnus"] != 1 || stats.BadStanding != 1 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+	if stats := ClassStatsOf([]models.Brother{}); stats.Size != 0 || stats.Statuses == nil {
+		t.Errorf("Expected empty stats with a non-nil status map. Got %+v", stats)
+	}
+}
@@ -0,0 +1,335 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+)
+
+// Names of custom fields: letters, digits and underscores, starting with a letter
+var customFieldName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// Longest value of a text custom field
+const maxCustomTextLength = 1000
+
+// Columns of customFields in the order ScanCustomField reads them
+const CustomFieldColumns = `fieldID, name, label, type, options, required, createdAt`
+
+// Scans a customFields row selected with CustomFieldColumns
+func ScanCustomField(row RowScanner) (models.CustomField, error) {
+	var field models.CustomField
+	var options []byte
+	err := row.Scan(
+		&field.FieldID,
+		&field.Name,
+		&field.Label,
+		&field.Type,
+		&options,
+		&field.Required,
+		&field.CreatedAt,
+	)
+	if err != nil {
+		return models.CustomField{}, err
+	}
+	if err := json.Unmarshal(options, &field.Options); err != nil {
+		return models.CustomField{}, fmt.Errorf("decoding options of custom field %s: %w", field.Name, err)
+	}
+	return field, nil
+}
+
+// Returns every custom field in the order they were defined
+func CustomFields(ctx context.Context, q Querier) ([]models.CustomField, error) {
+	rows, err := q.QueryContext(ctx, `SELECT `+CustomFieldColumns+` FROM customFields ORDER BY fieldID`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	fields := []models.CustomField{}
+	for rows.Next() {
+		field, err := ScanCustomField(rows)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+	return fields, rows.Err()
+}
+
+// Returns a custom field. Returns sql.ErrNoRows if there is none with the ID
+func GetCustomField(ctx context.Context, q Querier, fieldID int) (models.CustomField, error) {
+	return ScanCustomField(q.QueryRowContext(ctx, `SELECT `+CustomFieldColumns+` FROM customFields WHERE fieldID = $1`, fieldID))
+}
+
+// Defines a custom field. Check it with ValidateCustomField first
+func InsertCustomField(ctx context.Context, q Querier, field models.CustomField) (models.CustomField, error) {
+	options, err := json.Marshal(nonNil(field.Options))
+	if err != nil {
+		return models.CustomField{}, err
+	}
+	query := `
+    INSERT INTO customFields (name, label, type, options, required)
+    VALUES ($1, $2, $3, $4, $5)
+    RETURNING ` + CustomFieldColumns
+	return ScanCustomField(q.QueryRowContext(ctx, query, field.Name, field.Label, field.Type, string(options), field.Required))
+}
+
+// Changes the label, options and required flag of a custom field. Its name and type can't change.
+// Returns sql.ErrNoRows if there is none with the ID
+func UpdateCustomField(ctx context.Context, q Querier, field models.CustomField) (models.CustomField, error) {
+	options, err := json.Marshal(nonNil(field.Options))
+	if err != nil {
+		return models.CustomField{}, err
+	}
+	query := `
+    UPDATE customFields SET label = $2, options = $3, required = $4
+    WHERE fieldID = $1
+    RETURNING ` + CustomFieldColumns
+	return ScanCustomField(q.QueryRowContext(ctx, query, field.FieldID, field.Label, string(options), field.Required))
+}
+
+// Deletes a custom field and its values on every brother. Run it in a transaction.
+// Returns sql.ErrNoRows if there is none with the ID
+func DeleteCustomField(ctx context.Context, q Querier, fieldID int) (models.CustomField, error) {
+	field, err := ScanCustomField(q.QueryRowContext(ctx, `DELETE FROM customFields WHERE fieldID = $1 RETURNING `+CustomFieldColumns, fieldID))
+	if err != nil {
+		return models.CustomField{}, err
+	}
+	_, err = q.ExecContext(ctx, `UPDATE brothers SET customFields = customFields - $1 WHERE customFields ? $1`, field.Name)
+	return field, err
+}
+
+// Counts the brothers whose value of the field is one of values
+func CountCustomFieldValues(ctx context.Context, q Querier, name string, values []string) (int, error) {
+	encoded, err := json.Marshal(nonNil(values))
+	if err != nil {
+		return 0, err
+	}
+	query := `SELECT count(*) FROM brothers WHERE customFields->>$1 IN (SELECT json_array_elements_text($2::json))`
+	var count int
+	err = q.QueryRowContext(ctx, query, name, string(encoded)).Scan(&count)
+	return count, err
+}
+
+// Encodes nil slices as [] instead of null
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// Checks the parts of a custom field definition that struct tags can't: the name's characters,
+// and enum fields being the only ones with options and always having some
+func ValidateCustomField(field models.CustomField) []models.FieldError {
+	var fieldErrors []models.FieldError
+	if !customFieldName.MatchString(field.Name) {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "name", Rule: "name",
+			Message: "name must start with a letter and only contain letters, digits and underscores"})
+	}
+	if field.Type != models.CustomFieldEnum && len(field.Options) > 0 {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "options", Rule: "excluded_unless",
+			Message: "options can only be set on enum fields"})
+	}
+	if field.Type == models.CustomFieldEnum && len(field.Options) == 0 {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "options", Rule: "required",
+			Message: "options is required for enum fields"})
+	}
+	return fieldErrors
+}
+
+// Checks a JSON value against the type of a custom field and returns it in its stored form
+func CheckCustomFieldValue(field models.CustomField, value interface{}) (interface{}, error) {
+	switch field.Type {
+	case models.CustomFieldNumber:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case json.Number:
+			return v.Float64()
+		}
+		return nil, errors.New("must be a number")
+	case models.CustomFieldText, models.CustomFieldDate, models.CustomFieldEnum:
+		s, ok := value.(string)
+		if !ok {
+			return nil, errors.New("must be a string")
+		}
+		return checkCustomFieldString(field, s)
+	}
+	return nil, fmt.Errorf("has unknown type %q", field.Type)
+}
+
+// Parses a value written as text, e.g. in a CSV cell or a query parameter
+func ParseCustomFieldValue(field models.CustomField, value string) (interface{}, error) {
+	if field.Type == models.CustomFieldNumber {
+		n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || math.IsInf(n, 0) || math.IsNaN(n) {
+			return nil, fmt.Errorf("%q is not a number", value)
+		}
+		return n, nil
+	}
+	return checkCustomFieldString(field, value)
+}
+
+func checkCustomFieldString(field models.CustomField, value string) (interface{}, error) {
+	switch field.Type {
+	case models.CustomFieldText:
+		if len(value) > maxCustomTextLength {
+			return nil, fmt.Errorf("must be at most %d characters", maxCustomTextLength)
+		}
+		return value, nil
+	case models.CustomFieldDate:
+		if _, err := time.Parse(time.DateOnly, value); err != nil {
+			return nil, fmt.Errorf("%q is not a date in YYYY-MM-DD format", value)
+		}
+		return value, nil
+	case models.CustomFieldEnum:
+		for _, option := range field.Options {
+			if value == option {
+				return value, nil
+			}
+		}
+		return nil, fmt.Errorf("must be one of: %s", strings.Join(field.Options, ", "))
+	}
+	return nil, fmt.Errorf("must be a %s", field.Type)
+}
+
+// Checks custom field values against their definitions and returns them in their stored form.
+// For a new brother (partial is false) every required field must be set and null values are dropped.
+// For an update (partial is true) only the given values are checked, and null removes a value
+// unless the field is required
+func ValidateCustomFieldValues(fields []models.CustomField, values map[string]interface{}, partial bool) (map[string]interface{}, []models.FieldError) {
+	byName := make(map[string]models.CustomField, len(fields))
+	for _, field := range fields {
+		byName[field.Name] = field
+	}
+
+	checked := map[string]interface{}{}
+	var fieldErrors []models.FieldError
+	for name, value := range values {
+		jsonName := "customFields." + name
+		field, ok := byName[name]
+		if !ok {
+			fieldErrors = append(fieldErrors, models.FieldError{Field: jsonName, Rule: "unknown", Message: fmt.Sprintf("%s is not a custom field", name)})
+			continue
+		}
+		if value == nil {
+			if field.Required {
+				fieldErrors = append(fieldErrors, models.FieldError{Field: jsonName, Rule: "required", Message: fmt.Sprintf("%s is required", jsonName)})
+			} else if partial {
+				checked[name] = nil
+			}
+			continue
+		}
+		value, err := CheckCustomFieldValue(field, value)
+		if err != nil {
+			fieldErrors = append(fieldErrors, models.FieldError{Field: jsonName, Rule: field.Type, Message: fmt.Sprintf("%s %v", jsonName, err)})
+			continue
+		}
+		checked[name] = value
+	}
+	if !partial {
+		for _, field := range fields {
+			if _, ok := values[field.Name]; field.Required && !ok {
+				jsonName := "customFields." + field.Name
+				fieldErrors = append(fieldErrors, models.FieldError{Field: jsonName, Rule: "required", Message: fmt.Sprintf("%s is required", jsonName)})
+			}
+		}
+	}
+	sort.Slice(fieldErrors, func(i, j int) bool { return fieldErrors[i].Field < fieldErrors[j].Field })
+	return checked, fieldErrors
+}
+
+// Comparisons a CustomFieldFilter can make
+const (
+	FilterEqual = "eq"
+	FilterMin   = "min"
+	FilterMax   = "max"
+)
+
+// Condition on a custom field when listing brothers
+type CustomFieldFilter struct {
+	Field models.CustomField
+	Op    string
+	Value interface{}
+}
+
+// Reads filters from query parameters: cf.<name>=value matches a value exactly, and
+// cf.<name>.min / cf.<name>.max bound number and date fields (inclusive)
+func ParseCustomFieldFilters(fields []models.CustomField, query url.Values) ([]CustomFieldFilter, error) {
+	byName := make(map[string]models.CustomField, len(fields))
+	for _, field := range fields {
+		byName[field.Name] = field
+	}
+
+	var params []string
+	for param := range query {
+		if strings.HasPrefix(param, "cf.") {
+			params = append(params, param)
+		}
+	}
+	sort.Strings(params)
+
+	var filters []CustomFieldFilter
+	for _, param := range params {
+		name, op := strings.TrimPrefix(param, "cf."), FilterEqual
+		if base, ok := strings.CutSuffix(name, ".min"); ok {
+			name, op = base, FilterMin
+		} else if base, ok := strings.CutSuffix(name, ".max"); ok {
+			name, op = base, FilterMax
+		}
+		field, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("%s: %s is not a custom field", param, name)
+		}
+		if op != FilterEqual && field.Type != models.CustomFieldNumber && field.Type != models.CustomFieldDate {
+			return nil, fmt.Errorf("%s: only number and date fields have .min and .max filters", param)
+		}
+		value, err := ParseCustomFieldValue(field, query.Get(param))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", param, err)
+		}
+		filters = append(filters, CustomFieldFilter{Field: field, Op: op, Value: value})
+	}
+	return filters, nil
+}
+
+// SQL condition of the filter, with its arguments numbered from $n
+func (f CustomFieldFilter) sql(n int) (string, []interface{}, error) {
+	if f.Op == FilterEqual {
+		// Containment can use the GIN index on customFields
+		match, err := json.Marshal(map[string]interface{}{f.Field.Name: f.Value})
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("customFields @> $%d::jsonb", n), []interface{}{string(match)}, nil
+	}
+
+	comparison := ">="
+	if f.Op == FilterMax {
+		comparison = "<="
+	}
+	value := fmt.Sprintf("customFields->>$%d", n)
+	if f.Field.Type == models.CustomFieldNumber {
+		value = "(" + value + ")::numeric"
+	}
+	// Dates are stored as YYYY-MM-DD, so comparing them as text orders them by date
+	return fmt.Sprintf("%s %s $%d", value, comparison, n+1), []interface{}{f.Field.Name, f.Value}, nil
+}
+
+// Counts the brothers without a value for the field
+func CountMissingCustomField(ctx context.Context, q Querier, name string) (int, error) {
+	var count int
+	err := q.QueryRowContext(ctx, `SELECT count(*) FROM brothers WHERE NOT customFields ? $1`, name).Scan(&count)
+	return count, err
+}
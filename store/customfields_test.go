@@ -0,0 +1,115 @@
+package store
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+)
+
+var testCustomFields = []models.CustomField{
+	{Name: "shirtSize", Type: models.CustomFieldEnum, Options: []string{"S", "M", "L"}, Required: true},
+	{Name: "gradYear", Type: models.CustomFieldNumber},
+	{Name: "initiated", Type: models.CustomFieldDate},
+	{Name: "linkedin", Type: models.CustomFieldText},
+}
+
+func TestValidateCustomFieldValues(t *testing.T) {
+	values := map[string]interface{}{"shirtSize": "M", "gradYear": 2026.0, "initiated": "2023-02-11", "linkedin": nil}
+	checked, fieldErrors := ValidateCustomFieldValues(testCustomFields, values, false)
+	if len(fieldErrors) > 0 {
+		t.Fatalf("Expected no errors. Got %v", fieldErrors)
+	}
+	if _, ok := checked["linkedin"]; ok || checked["gradYear"] != 2026.0 {
+		t.Errorf("Expected nulls to be dropped on create. Got %v", checked)
+	}
+
+	values = map[string]interface{}{"gradYear": "2026", "initiated": "02/11/2023", "nickname": "JD"}
+	_, fieldErrors = ValidateCustomFieldValues(testCustomFields, values, false)
+	var got []string
+	for _, fe := range fieldErrors {
+		got = append(got, fe.Field+" "+fe.Rule)
+	}
+	expected := []string{"customFields.gradYear number", "customFields.initiated date", "customFields.nickname unknown", "customFields.shirtSize required"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v. Got %v", expected, got)
+	}
+}
+
+func TestValidateCustomFieldValuesPartial(t *testing.T) {
+	checked, fieldErrors := ValidateCustomFieldValues(testCustomFields, map[string]interface{}{"linkedin": nil}, true)
+	if len(fieldErrors) > 0 {
+		t.Fatalf("Expected updates to skip missing required fields. Got %v", fieldErrors)
+	}
+	if value, ok := checked["linkedin"]; !ok || value != nil {
+		t.Errorf("Expected null to be kept to remove the value. Got %v", checked)
+	}
+	if _, fieldErrors := ValidateCustomFieldValues(testCustomFields, map[string]interface{}{"shirtSize": nil}, true); len(fieldErrors) != 1 {
+		t.Errorf("Expected required fields not to be removable. Got %v", fieldErrors)
+	}
+}
+
+func TestParseCustomFieldFilters(t *testing.T) {
+	query := url.Values{
+		"cf.shirtSize":     {"M"},
+		"cf.gradYear.min":  {"2025"},
+		"cf.initiated.max": {"2024-12-31"},
+		"semester":         {"Fall 2024"},
+	}
+	filters, err := ParseCustomFieldFilters(testCustomFields, query)
+	if err != nil {
+		t.Fatalf("ParseCustomFieldFilters: %v", err)
+	}
+
+	var conditions []string
+	var args []interface{}
+	for _, filter := range filters {
+		condition, filterArgs, err := filter.sql(len(args) + 1)
+		if err != nil {
+			t.Fatalf("sql: %v", err)
+		}
+		conditions = append(conditions, condition)
+		args = append(args, filterArgs...)
+	}
+	expectedConditions := []string{
+		"(customFields->>$1)::numeric >= $2",
+		"customFields->>$3 <= $4",
+		"customFields @> $5::jsonb",
+	}
+	expectedArgs := []interface{}{"gradYear", 2025.0, "initiated", "2024-12-31", `{"shirtSize":"M"}`}
+	if !reflect.DeepEqual(conditions, expectedConditions) || !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("Unexpected filters:\n%v\n%v", conditions, args)
+	}
+}
+
+func TestParseCustomFieldFiltersErrors(t *testing.T) {
+	for _, query := range []url.Values{
+		{"cf.nickname": {"JD"}},
+		{"cf.shirtSize.min": {"M"}},
+		{"cf.gradYear": {"soon"}},
+		{"cf.shirtSize": {"XL"}},
+	} {
+		if _, err := ParseCustomFieldFilters(testCustomFields, query); err == nil {
+			t.Errorf("Expected an error for %v", query)
+		}
+	}
+}
+
+func TestValidateCustomField(t *testing.T) {
+	tests := []struct {
+		field models.CustomField
+		valid bool
+	}{
+		{models.CustomField{Name: "tshirt_size", Type: models.CustomFieldEnum, Options: []string{"S"}}, true},
+		{models.CustomField{Name: "2024dues", Type: models.CustomFieldNumber}, false},
+		{models.CustomField{Name: "linked-in", Type: models.CustomFieldText}, false},
+		{models.CustomField{Name: "notes", Type: models.CustomFieldText, Options: []string{"a"}}, false},
+		{models.CustomField{Name: "size", Type: models.CustomFieldEnum}, false},
+	}
+	for _, tt := range tests {
+		if valid := len(ValidateCustomField(tt.field)) == 0; valid != tt.valid {
+			t.Errorf("%+v: expected valid to be %v", tt.field, tt.valid)
+		}
+	}
+}
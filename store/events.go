@@ -0,0 +1,10 @@
+package store
+
+import "context"
+
+// Reports whether an event with the ID exists
+func EventExists(ctx context.Context, q Querier, eventID int) (bool, error) {
+	var exists bool
+	err := q.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM events WHERE eventID = $1)`, eventID).Scan(&exists)
+	return exists, err
+}
@@ -0,0 +1,217 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+)
+
+// Status of brothers counted as active in family stats
+const activeStatus = "Active"
+
+// Selects brothers with their big, in the order scanLineageBrother reads them. Queries add the generation column
+const lineageSelect = `SELECT b.brotherID, b.rollCall, b.firstName, b.lastName, b.status, l.bigID`
+
+func scanLineageBrother(row RowScanner, withGeneration bool) (models.LineageBrother, error) {
+	var brother models.LineageBrother
+	dest := []interface{}{&brother.BrotherID, &brother.RollCall, &brother.FirstName, &brother.LastName, &brother.Status, &brother.BigBrotherID}
+	if withGeneration {
+		dest = append(dest, &brother.Generation)
+	}
+	if err := row.Scan(dest...); err != nil {
+		return models.LineageBrother{}, err
+	}
+	return brother, nil
+}
+
+func queryLineage(ctx context.Context, q Querier, withGeneration bool, query string, args ...interface{}) ([]models.LineageBrother, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	brothers := []models.LineageBrother{}
+	for rows.Next() {
+		brother, err := scanLineageBrother(rows, withGeneration)
+		if err != nil {
+			return nil, err
+		}
+		brothers = append(brothers, brother)
+	}
+	return brothers, rows.Err()
+}
+
+// Returns the big of a brother, their big and so on, closest first. maxDepth limits the generations, 0 returns all
+func Ancestors(ctx context.Context, q Querier, brotherID int, maxDepth int) ([]models.LineageBrother, error) {
+	query := `
+    WITH RECURSIVE a(brotherID, generation) AS (
+        SELECT bigID, 1 FROM lineage WHERE littleID = $1
+        UNION ALL
+        SELECT l.bigID, a.generation + 1 FROM lineage l JOIN a ON l.littleID = a.brotherID
+        WHERE $2 = 0 OR a.generation < $2
+    )
+    ` + lineageSelect + `, a.generation
+    FROM a
+    JOIN brothers b ON b.brotherID = a.brotherID
+    LEFT JOIN lineage l ON l.littleID = b.brotherID
+    ORDER BY a.generation`
+	return queryLineage(ctx, q, true, query, brotherID, maxDepth)
+}
+
+// Returns the littles of a brother, their littles and so on, closest first. maxDepth limits the generations, 0 returns all
+func Descendants(ctx context.Context, q Querier, brotherID int, maxDepth int) ([]models.LineageBrother, error) {
+	query := `
+    WITH RECURSIVE d(brotherID, generation) AS (
+        SELECT littleID, 1 FROM lineage WHERE bigID = $1
+        UNION ALL
+        SELECT l.littleID, d.generation + 1 FROM lineage l JOIN d ON l.bigID = d.brotherID
+        WHERE $2 = 0 OR d.generation < $2
+    )
+    ` + lineageSelect + `, d.generation
+    FROM d
+    JOIN brothers b ON b.brotherID = d.brotherID
+    JOIN lineage l ON l.littleID = b.brotherID
+    ORDER BY d.generation, b.rollCall`
+	return queryLineage(ctx, q, true, query, brotherID, maxDepth)
+}
+
+// Returns every brother who has a big or a little, ordered by rollCall
+func Lineage(ctx context.Context, q Querier) ([]models.LineageBrother, error) {
+	query := lineageSelect + `
+    FROM brothers b
+    LEFT JOIN lineage l ON l.littleID = b.brotherID
+    WHERE l.littleID IS NOT NULL OR EXISTS (SELECT 1 FROM lineage x WHERE x.bigID = b.brotherID)
+    ORDER BY b.rollCall, b.brotherID`
+	return queryLineage(ctx, q, false, query)
+}
+
+// Locks the lineage against other writers until the transaction ends, so a cycle check stays valid until the link is saved
+func LockLineage(ctx context.Context, tx Querier) error {
+	_, err := tx.ExecContext(ctx, `LOCK TABLE lineage IN SHARE ROW EXCLUSIVE MODE`)
+	return err
+}
+
+// Reports whether ancestorID is brotherID or one of their ancestors. Making brotherID the big of ancestorID would then form a cycle
+func IsAncestorOrSelf(ctx context.Context, q Querier, ancestorID int, brotherID int) (bool, error) {
+	query := `
+    WITH RECURSIVE d(brotherID) AS (
+        SELECT $1::int
+        UNION
+        SELECT l.littleID FROM lineage l JOIN d ON l.bigID = d.brotherID
+    )
+    SELECT EXISTS (SELECT 1 FROM d WHERE brotherID = $2)`
+	var found bool
+	err := q.QueryRowContext(ctx, query, ancestorID, brotherID).Scan(&found)
+	return found, err
+}
+
+// Makes bigID the big of littleID, replacing their previous big. Check IsAncestorOrSelf first
+func SetBig(ctx context.Context, q Querier, littleID int, bigID int) error {
+	_, err := q.ExecContext(ctx, `
+    INSERT INTO lineage (littleID, bigID) VALUES ($1, $2)
+    ON CONFLICT (littleID) DO UPDATE SET bigID = EXCLUDED.bigID`, littleID, bigID)
+	return err
+}
+
+// Removes the big of a brother. Reports whether they had one
+func RemoveBig(ctx context.Context, q Querier, littleID int) (bool, error) {
+	result, err := q.ExecContext(ctx, `DELETE FROM lineage WHERE littleID = $1`, littleID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// Builds the family trees of brothers returned by Lineage, one per founder: a brother without a big among them.
+// Founders and littles are ordered by rollCall
+func FamilyTree(brothers []models.LineageBrother) []models.FamilyTreeNode {
+	known := map[int]bool{}
+	for _, brother := range brothers {
+		known[brother.BrotherID] = true
+	}
+	littles := map[int][]models.LineageBrother{}
+	var founders []models.LineageBrother
+	for _, brother := range brothers {
+		if brother.BigBrotherID != nil && known[*brother.BigBrotherID] {
+			littles[*brother.BigBrotherID] = append(littles[*brother.BigBrotherID], brother)
+		} else {
+			founders = append(founders, brother)
+		}
+	}
+
+	var build func(brother models.LineageBrother) models.FamilyTreeNode
+	build = func(brother models.LineageBrother) models.FamilyTreeNode {
+		node := models.FamilyTreeNode{
+			BrotherID: brother.BrotherID,
+			RollCall:  brother.RollCall,
+			FirstName: brother.FirstName,
+			LastName:  brother.LastName,
+			Status:    brother.Status,
+			Littles:   []models.FamilyTreeNode{},
+		}
+		children := littles[brother.BrotherID]
+		sort.SliceStable(children, func(i, j int) bool { return children[i].RollCall < children[j].RollCall })
+		for _, little := range children {
+			node.Littles = append(node.Littles, build(little))
+		}
+		return node
+	}
+
+	sort.SliceStable(founders, func(i, j int) bool { return founders[i].RollCall < founders[j].RollCall })
+	trees := []models.FamilyTreeNode{}
+	for _, founder := range founders {
+		trees = append(trees, build(founder))
+	}
+	return trees
+}
+
+// Returns the size, active count and depth of each family tree, largest first
+func FamilyStats(trees []models.FamilyTreeNode) []models.Family {
+	var count func(node models.FamilyTreeNode, generation int, family *models.Family)
+	count = func(node models.FamilyTreeNode, generation int, family *models.Family) {
+		family.Size++
+		if node.Status == activeStatus {
+			family.Active++
+		}
+		family.Generations = max(family.Generations, generation)
+		for _, little := range node.Littles {
+			count(little, generation+1, family)
+		}
+	}
+
+	families := []models.Family{}
+	for _, tree := range trees {
+		family := models.Family{FounderID: tree.BrotherID, RollCall: tree.RollCall, FirstName: tree.FirstName, LastName: tree.LastName}
+		count(tree, 1, &family)
+		families = append(families, family)
+	}
+	sort.SliceStable(families, func(i, j int) bool { return families[i].Size > families[j].Size })
+	return families
+}
+
+// Writes family trees as a Graphviz DOT digraph with an edge from each big to their littles
+func WriteLineageDOT(w io.Writer, trees []models.FamilyTreeNode) error {
+	quote := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	var b strings.Builder
+	b.WriteString("digraph lineage {\n    rankdir=TB;\n    node [shape=box];\n")
+	var write func(node models.FamilyTreeNode)
+	write = func(node models.FamilyTreeNode) {
+		fmt.Fprintf(&b, "    b%d [label=\"%s %s\\n#%d\"];\n", node.BrotherID, quote.Replace(node.FirstName), quote.Replace(node.LastName), node.RollCall)
+		for _, little := range node.Littles {
+			fmt.Fprintf(&b, "    b%d -> b%d;\n", node.BrotherID, little.BrotherID)
+			write(little)
+		}
+	}
+	for _, tree := range trees {
+		write(tree)
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
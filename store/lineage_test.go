@@ -0,0 +1,70 @@
+package store
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+)
+
+func lineageBrother(id int, big int, status string) models.LineageBrother {
+	brother := models.LineageBrother{BrotherID: id, RollCall: 100 + id, FirstName: "First", LastName: "Last", Status: status}
+	if big != 0 {
+		brother.BigBrotherID = &big
+	}
+	return brother
+}
+
+func TestFamilyTree(t *testing.T) {
+	// 1 -> 2 -> 4, 1 -> 3 and 5 -> 6
+	brothers := []models.LineageBrother{
+		lineageBrother(4, 2, "Active"),
+		lineageBrother(1, 0, "Alumnus"),
+		lineageBrother(3, 1, "Active"),
+		lineageBrother(2, 1, "Pre-Alumnus"),
+		lineageBrother(5, 0, "Alumnus"),
+		lineageBrother(6, 5, "Active"),
+	}
+	trees := FamilyTree(brothers)
+	if len(trees) != 2 || trees[0].BrotherID != 1 || trees[1].BrotherID != 5 {
+		t.Fatalf("Expected families founded by 1 and 5. Got %+v", trees)
+	}
+	littles := trees[0].Littles
+	if len(littles) != 2 || littles[0].BrotherID != 2 || littles[1].BrotherID != 3 || len(littles[0].Littles) != 1 || littles[0].Littles[0].BrotherID != 4 {
+		t.Errorf("Expected 1 to have littles 2 (with little 4) and 3. Got %+v", littles)
+	}
+
+	families := FamilyStats(trees)
+	expected := []models.Family{
+		{FounderID: 1, RollCall: 101, FirstName: "First", LastName: "Last", Size: 4, Active: 2, Generations: 3},
+		{FounderID: 5, RollCall: 105, FirstName: "First", LastName: "Last", Size: 2, Active: 1, Generations: 2},
+	}
+	if len(families) != len(expected) {
+		t.Fatalf("Expected %d families. Got %+v", len(expected), families)
+	}
+	for i := range expected {
+		if families[i] != expected[i] {
+			t.Errorf("Expected %+v. Got %+v", expected[i], families[i])
+		}
+	}
+}
+
+func TestWriteLineageDOT(t *testing.T) {
+	brothers := []models.LineageBrother{lineageBrother(1, 0, "Alumnus"), lineageBrother(2, 1, "Active")}
+	brothers[1].FirstName = `Jo "JJ"`
+
+	var graph bytes.Buffer
+	if err := WriteLineageDOT(&graph, FamilyTree(brothers)); err != nil {
+		t.Fatalf("WriteLineageDOT: %v", err)
+	}
+	expected := "digraph lineage {\n" +
+		"    rankdir=TB;\n" +
+		"    node [shape=box];\n" +
+		"    b1 [label=\"First Last\\n#101\"];\n" +
+		"    b1 -> b2;\n" +
+		"    b2 [label=\"Jo \\\"JJ\\\" Last\\n#102\"];\n" +
+		"}\n"
+	if graph.String() != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, graph.String())
+	}
+}
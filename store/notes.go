@@ -0,0 +1,133 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+)
+
+// Columns of brotherNotes in the order ScanNote reads them
+const NoteColumns = `noteID, brotherID, body, visibility, author, createdAt, updatedBy, updatedAt`
+
+// Scans a brotherNotes row selected with NoteColumns
+func ScanNote(row RowScanner) (models.Note, error) {
+	var note models.Note
+	err := row.Scan(
+		&note.NoteID,
+		&note.BrotherID,
+		&note.Body,
+		&note.Visibility,
+		&note.Author,
+		&note.CreatedAt,
+		&note.UpdatedBy,
+		&note.UpdatedAt,
+	)
+	if err != nil {
+		return models.Note{}, err
+	}
+	return note, nil
+}
+
+// Returns the notes on a brother, newest first. Officers-only notes are left out unless includeOfficers is set
+func BrotherNotes(ctx context.Context, q Querier, brotherID int, includeOfficers bool) ([]models.Note, error) {
+	query := `SELECT ` + NoteColumns + `
+    FROM brotherNotes
+    WHERE brotherID = $1 AND ($2 OR visibility = 'all')
+    ORDER BY createdAt DESC, noteID DESC`
+	rows, err := q.QueryContext(ctx, query, brotherID, includeOfficers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notes := []models.Note{}
+	for rows.Next() {
+		note, err := ScanNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, note)
+	}
+	return notes, rows.Err()
+}
+
+// Returns a note on a brother. Returns sql.ErrNoRows if the brother has no such note
+func GetNote(ctx context.Context, q Querier, brotherID int, noteID int) (models.Note, error) {
+	query := `SELECT ` + NoteColumns + ` FROM brotherNotes WHERE brotherID = $1 AND noteID = $2`
+	return ScanNote(q.QueryRowContext(ctx, query, brotherID, noteID))
+}
+
+// Adds a note to a brother
+func InsertNote(ctx context.Context, q Querier, brotherID int, body string, visibility string, author string) (models.Note, error) {
+	query := `
+    INSERT INTO brotherNotes (brotherID, body, visibility, author, updatedBy)
+    VALUES ($1, $2, $3, $4, $4)
+    RETURNING ` + NoteColumns
+	return ScanNote(q.QueryRowContext(ctx, query, brotherID, body, visibility, author))
+}
+
+// Replaces the body and visibility of a note, keeping the previous version as a revision.
+// Returns sql.ErrNoRows if the brother has no such note
+func UpdateNote(ctx context.Context, q Querier, brotherID int, noteID int, body string, visibility string, editor string) (models.Note, error) {
+	query := `
+    WITH previous AS (
+        SELECT noteID, body, visibility, updatedBy, updatedAt
+        FROM brotherNotes
+        WHERE brotherID = $1 AND noteID = $2
+        FOR UPDATE
+    ), revision AS (
+        INSERT INTO brotherNoteRevisions (noteID, body, visibility, editedBy, editedAt)
+        SELECT noteID, body, visibility, updatedBy, updatedAt FROM previous
+    )
+    UPDATE brotherNotes n
+    SET body = $3, visibility = $4, updatedBy = $5, updatedAt = now()
+    FROM previous p
+    WHERE n.noteID = p.noteID
+    RETURNING n.noteID, n.brotherID, n.body, n.visibility, n.author, n.createdAt, n.updatedBy, n.updatedAt`
+	return ScanNote(q.QueryRowContext(ctx, query, brotherID, noteID, body, visibility, editor))
+}
+
+// Deletes a note and its revisions. Returns sql.ErrNoRows if the brother has no such note
+func DeleteNote(ctx context.Context, q Querier, brotherID int, noteID int) error {
+	result, err := q.ExecContext(ctx, `DELETE FROM brotherNotes WHERE brotherID = $1 AND noteID = $2`, brotherID, noteID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Returns every version of a note, oldest first. The last one is the current version
+func NoteHistory(ctx context.Context, q Querier, note models.Note) ([]models.NoteRevision, error) {
+	query := `
+    SELECT body, visibility, editedBy, editedAt
+    FROM brotherNoteRevisions
+    WHERE noteID = $1
+    ORDER BY editedAt, revisionID`
+	rows, err := q.QueryContext(ctx, query, note.NoteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []models.NoteRevision{}
+	for rows.Next() {
+		var revision models.NoteRevision
+		if err := rows.Scan(&revision.Body, &revision.Visibility, &revision.EditedBy, &revision.EditedAt); err != nil {
+			return nil, err
+		}
+		history = append(history, revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	current := models.NoteRevision{Body: note.Body, Visibility: note.Visibility, EditedBy: note.UpdatedBy, EditedAt: note.UpdatedAt}
+	return append(history, current), nil
+}
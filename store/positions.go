@@ -0,0 +1,278 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+)
+
+// Columns of positions in the order ScanPosition reads them
+const PositionColumns = `positionID, name, kind, role, createdAt`
+
+// Scans a positions row selected with PositionColumns
+func ScanPosition(row RowScanner) (models.Position, error) {
+	var position models.Position
+	err := row.Scan(&position.PositionID, &position.Name, &position.Kind, &position.Role, &position.CreatedAt)
+	if err != nil {
+		return models.Position{}, err
+	}
+	return position, nil
+}
+
+// Returns the positions of a kind, or of every kind if kind is empty. Offices come before committees
+func Positions(ctx context.Context, q Querier, kind string) ([]models.Position, error) {
+	query := `SELECT ` + PositionColumns + ` FROM positions WHERE ($1 = '' OR kind = $1) ORDER BY kind DESC, lower(name)`
+	rows, err := q.QueryContext(ctx, query, kind)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	positions := []models.Position{}
+	for rows.Next() {
+		position, err := ScanPosition(rows)
+		if err != nil {
+			return nil, err
+		}
+		positions = append(positions, position)
+	}
+	return positions, rows.Err()
+}
+
+// Returns a position. Returns sql.ErrNoRows if there is none with the ID
+func GetPosition(ctx context.Context, q Querier, positionID int) (models.Position, error) {
+	return ScanPosition(q.QueryRowContext(ctx, `SELECT `+PositionColumns+` FROM positions WHERE positionID = $1`, positionID))
+}
+
+// Creates a position. Fails with positions_name_key if one already has the name, in any case
+func InsertPosition(ctx context.Context, q Querier, position models.Position) (models.Position, error) {
+	query := `INSERT INTO positions (name, kind, role) VALUES ($1, $2, $3) RETURNING ` + PositionColumns
+	return ScanPosition(q.QueryRowContext(ctx, query, position.Name, position.Kind, position.Role))
+}
+
+// Changes the name, kind and role of a position. Returns sql.ErrNoRows if there is none with the ID
+func UpdatePosition(ctx context.Context, q Querier, position models.Position) (models.Position, error) {
+	query := `UPDATE positions SET name = $2, kind = $3, role = $4 WHERE positionID = $1 RETURNING ` + PositionColumns
+	return ScanPosition(q.QueryRowContext(ctx, query, position.PositionID, position.Name, position.Kind, position.Role))
+}
+
+// Deletes a position and its terms. Returns sql.ErrNoRows if there is none with the ID
+func DeletePosition(ctx context.Context, q Querier, positionID int) (models.Position, error) {
+	return ScanPosition(q.QueryRowContext(ctx, `DELETE FROM positions WHERE positionID = $1 RETURNING `+PositionColumns, positionID))
+}
+
+// Dates of a term from its first and last semester: the first day of the first semester and the day
+// after the last one, nil if the term hasn't ended. Fails if a label isn't a semester or the term ends before it starts
+func TermDates(startSemester string, endSemester string) (startsOn time.Time, endsBefore *time.Time, err error) {
+	startsOn, _, err = SemesterDates(startSemester)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	if strings.TrimSpace(endSemester) == "" {
+		return startsOn, nil, nil
+	}
+	_, end, err := SemesterDates(endSemester)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	if !end.After(startsOn) {
+		return time.Time{}, nil, fmt.Errorf("term ends in %s, before it starts in %s", endSemester, startSemester)
+	}
+	return startsOn, &end, nil
+}
+
+// Selects terms with the position and brother they link, in the order scanTerm reads them
+const termQuery = `
+    SELECT pt.termID, p.positionID, p.name, p.kind, b.brotherID, b.rollCall, b.firstName, b.lastName,
+        pt.title, pt.startSemester, pt.endSemester
+    FROM positionTerms pt
+    JOIN positions p ON p.positionID = pt.positionID
+    JOIN brothers b ON b.brotherID = pt.brotherID`
+
+func scanTerm(row RowScanner) (models.PositionTerm, error) {
+	var term models.PositionTerm
+	err := row.Scan(
+		&term.TermID,
+		&term.PositionID,
+		&term.PositionName,
+		&term.Kind,
+		&term.BrotherID,
+		&term.RollCall,
+		&term.FirstName,
+		&term.LastName,
+		&term.Title,
+		&term.StartSemester,
+		&term.EndSemester,
+	)
+	if err != nil {
+		return models.PositionTerm{}, err
+	}
+	return term, nil
+}
+
+func queryTerms(ctx context.Context, q Querier, query string, args ...interface{}) ([]models.PositionTerm, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	terms := []models.PositionTerm{}
+	for rows.Next() {
+		term, err := scanTerm(rows)
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+	return terms, rows.Err()
+}
+
+// Returns a term of a position. Returns sql.ErrNoRows if the position has none with the ID
+func GetTerm(ctx context.Context, q Querier, positionID int, termID int) (models.PositionTerm, error) {
+	return scanTerm(q.QueryRowContext(ctx, termQuery+` WHERE pt.positionID = $1 AND pt.termID = $2`, positionID, termID))
+}
+
+// Returns every term of a position, latest first
+func PositionTerms(ctx context.Context, q Querier, positionID int) ([]models.PositionTerm, error) {
+	return queryTerms(ctx, q, termQuery+` WHERE pt.positionID = $1 ORDER BY pt.startsOn DESC, pt.termID DESC`, positionID)
+}
+
+// Returns every position a brother has held, latest first
+func BrotherTerms(ctx context.Context, q Querier, brotherID int) ([]models.PositionTerm, error) {
+	return queryTerms(ctx, q, termQuery+` WHERE pt.brotherID = $1 ORDER BY pt.startsOn DESC, pt.termID DESC`, brotherID)
+}
+
+// Condition matching the terms served on the date of the $n argument
+func servingOn(n int) string {
+	return fmt.Sprintf(`pt.startsOn <= $%[1]d AND (pt.endsBefore IS NULL OR pt.endsBefore > $%[1]d)`, n)
+}
+
+// Returns the terms served during a semester, offices first, for positions of a kind or of every kind if kind is empty
+func SemesterTerms(ctx context.Context, q Querier, semesterLabel string, kind string) ([]models.PositionTerm, error) {
+	start, _, err := SemesterDates(semesterLabel)
+	if err != nil {
+		return nil, err
+	}
+	query := termQuery + ` WHERE ` + servingOn(1) + ` AND ($2 = '' OR p.kind = $2)
+    ORDER BY p.kind DESC, lower(p.name), pt.title = '', lower(pt.title), b.lastName, b.firstName`
+	return queryTerms(ctx, q, query, start, kind)
+}
+
+// Reports whether the brother has another term of the same position that shares a semester with term.
+// term.TermID is left out, so a term being changed doesn't overlap itself
+func TermOverlaps(ctx context.Context, q Querier, term models.PositionTerm) (bool, error) {
+	startsOn, endsBefore, err := TermDates(term.StartSemester, term.EndSemester)
+	if err != nil {
+		return false, err
+	}
+	query := `
+    SELECT EXISTS (
+        SELECT 1 FROM positionTerms
+        WHERE positionID = $1 AND brotherID = $2 AND termID <> $3
+            AND daterange(startsOn, endsBefore) && daterange($4::date, $5::date)
+    )`
+	var overlaps bool
+	err = q.QueryRowContext(ctx, query, term.PositionID, term.BrotherID, term.TermID, startsOn, endsBefore).Scan(&overlaps)
+	return overlaps, err
+}
+
+// Adds a term to a position. The semesters must be valid for TermDates
+func InsertTerm(ctx context.Context, q Querier, term models.PositionTerm) (models.PositionTerm, error) {
+	startsOn, endsBefore, err := TermDates(term.StartSemester, term.EndSemester)
+	if err != nil {
+		return models.PositionTerm{}, err
+	}
+	var termID int
+	err = q.QueryRowContext(ctx, `
+    INSERT INTO positionTerms (positionID, brotherID, title, startSemester, startsOn, endSemester, endsBefore)
+    VALUES ($1, $2, $3, $4, $5, $6, $7)
+    RETURNING termID`,
+		term.PositionID, term.BrotherID, term.Title, term.StartSemester, startsOn, term.EndSemester, endsBefore,
+	).Scan(&termID)
+	if err != nil {
+		return models.PositionTerm{}, err
+	}
+	return GetTerm(ctx, q, term.PositionID, termID)
+}
+
+// Changes the title and semesters of a term. Returns sql.ErrNoRows if the position has no term with the ID
+func UpdateTerm(ctx context.Context, q Querier, term models.PositionTerm) (models.PositionTerm, error) {
+	startsOn, endsBefore, err := TermDates(term.StartSemester, term.EndSemester)
+	if err != nil {
+		return models.PositionTerm{}, err
+	}
+	var termID int
+	err = q.QueryRowContext(ctx, `
+    UPDATE positionTerms SET title = $3, startSemester = $4, startsOn = $5, endSemester = $6, endsBefore = $7
+    WHERE positionID = $1 AND termID = $2
+    RETURNING termID`,
+		term.PositionID, term.TermID, term.Title, term.StartSemester, startsOn, term.EndSemester, endsBefore,
+	).Scan(&termID)
+	if err != nil {
+		return models.PositionTerm{}, err
+	}
+	return GetTerm(ctx, q, term.PositionID, termID)
+}
+
+// Deletes a term of a position. Returns sql.ErrNoRows if the position has no term with the ID
+func DeleteTerm(ctx context.Context, q Querier, positionID int, termID int) error {
+	var deleted int
+	return q.QueryRowContext(ctx, `DELETE FROM positionTerms WHERE positionID = $1 AND termID = $2 RETURNING termID`, positionID, termID).Scan(&deleted)
+}
+
+// Returns the role each brother's email is granted by the positions they serve on the date, keyed by
+// lower-case email. Brothers serving several positions get the most privileged role
+func PositionRoles(ctx context.Context, q Querier, on time.Time) (map[string]string, error) {
+	query := `
+    SELECT lower(b.email), p.role
+    FROM positionTerms pt
+    JOIN positions p ON p.positionID = pt.positionID
+    JOIN brothers b ON b.brotherID = pt.brotherID
+    WHERE p.role <> '' AND b.email <> '' AND ` + servingOn(1)
+	rows, err := q.QueryContext(ctx, query, on)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := map[string]string{}
+	for rows.Next() {
+		var email, role string
+		if err := rows.Scan(&email, &role); err != nil {
+			return nil, err
+		}
+		if models.MorePrivileged(role, roles[email]) {
+			roles[email] = role
+		}
+	}
+	return roles, rows.Err()
+}
+
+// Returns the lower-case emails of brothers who have ever held a position that grants a role
+func RoleHolderEmails(ctx context.Context, q Querier) (map[string]bool, error) {
+	query := `
+    SELECT DISTINCT lower(b.email)
+    FROM positionTerms pt
+    JOIN positions p ON p.positionID = pt.positionID
+    JOIN brothers b ON b.brotherID = pt.brotherID
+    WHERE p.role <> '' AND b.email <> ''`
+	rows, err := q.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	emails := map[string]bool{}
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			return nil, err
+		}
+		emails[email] = true
+	}
+	return emails, rows.Err()
+}
@@ -0,0 +1,32 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTermDates(t *testing.T) {
+	startsOn, endsBefore, err := TermDates("Fall 2024", "Spring 2025")
+	if err != nil {
+		t.Fatalf("TermDates: %v", err)
+	}
+	if startsOn.Format(time.DateOnly) != "2024-07-01" || endsBefore == nil || endsBefore.Format(time.DateOnly) != "2025-07-01" {
+		t.Errorf("Expected 2024-07-01 to 2025-07-01. Got %v to %v", startsOn, endsBefore)
+	}
+
+	// A term of a single semester
+	if _, endsBefore, err := TermDates("Spring 2025", "Spring 2025"); err != nil || endsBefore.Format(time.DateOnly) != "2025-07-01" {
+		t.Errorf("Expected a single semester term to end before 2025-07-01. Got %v, %v", endsBefore, err)
+	}
+
+	// A term that hasn't ended
+	if _, endsBefore, err := TermDates("Spring 2025", ""); err != nil || endsBefore != nil {
+		t.Errorf("Expected an open term. Got %v, %v", endsBefore, err)
+	}
+
+	for _, semesters := range [][2]string{{"Fall 2025", "Spring 2025"}, {"Winter 2025", ""}, {"Fall 2025", "Fall"}} {
+		if _, _, err := TermDates(semesters[0], semesters[1]); err == nil {
+			t.Errorf("TermDates(%q, %q): expected an error", semesters[0], semesters[1])
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package store
+
+import (
+	"context"
+	_ "embed"
+)
+
+//go:embed seed.sql
+var seedSQL string
+
+// Inserts the sample brothers, events, semesters and statuses from seed.sql.
+// Rows that already exist are left unchanged
+func Seed(ctx context.Context, q Querier) error {
+	_, err := q.ExecContext(ctx, seedSQL)
+	return err
+}
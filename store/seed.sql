@@ -0,0 +1,45 @@
+-- Sample data for development databases, the same as the mock entries in db/scripts/init.sql.
+-- Safe to run more than once: rows that already exist are skipped.
+-- Relies on the unique constraints added by migration 000001
+INSERT INTO brothers (rollCall, firstName, lastName, major, status, className, email, phoneNumber, badStanding)
+VALUES
+    (1, 'John', 'Doe', 'Computer Science', 'Alumnus', 'Omicron', 'john@gmail.com', '(123) 456-7890', 0),
+    (2, 'Peter', 'Parker', 'Electrical Engineering', 'Co-op', 'Alpha', 'peter@yahoo.com', '(209)', 0),
+    (3, 'Nick', 'Ahn', 'Computer Science', 'Alumnus', 'Chi', 'na@gmail.com', '(209)', 0)
+ON CONFLICT (rollCall) DO NOTHING;
+
+INSERT INTO eventsCategory (categoryName)
+VALUES ('Professional Development'), ('Brotherhood'), ('Community Service')
+ON CONFLICT (categoryName) DO NOTHING;
+
+INSERT INTO events (eventName, categoryID, eventLocation, eventDate)
+SELECT e.eventName, ec.categoryID, e.eventLocation, e.eventDate
+FROM (VALUES
+    ('CO-OP Panel', 'Professional Development', 'Regent Room', DATE '2024-01-28'),
+    ('Movies', 'Brotherhood', 'CTC', DATE '2024-03-14')
+) AS e(eventName, categoryName, eventLocation, eventDate)
+JOIN eventsCategory ec ON ec.categoryName = e.categoryName
+WHERE NOT EXISTS (
+    SELECT 1 FROM events x WHERE x.eventName = e.eventName AND x.eventDate = e.eventDate
+);
+
+INSERT INTO semester (semesterLabel)
+VALUES ('Spring 2023'), ('Fall 2023'), ('Spring 2024'), ('Fall 2024')
+ON CONFLICT (semesterLabel) DO NOTHING;
+
+INSERT INTO brotherStatus (brotherID, semesterID, status)
+SELECT b.brotherID, s.semesterID, v.status::status
+FROM (VALUES
+    (1, 'Spring 2023', 'Active'),
+    (1, 'Fall 2023', 'Co-op'),
+    (1, 'Spring 2024', 'Active'),
+    (1, 'Fall 2024', 'Alumnus'),
+    (2, 'Spring 2024', 'Active'),
+    (2, 'Fall 2024', 'Co-op'),
+    (3, 'Fall 2023', 'Active'),
+    (3, 'Spring 2024', 'Alumnus'),
+    (3, 'Fall 2024', 'Alumnus')
+) AS v(rollCall, semesterLabel, status)
+JOIN brothers b ON b.rollCall = v.rollCall
+JOIN semester s ON s.semesterLabel = v.semesterLabel
+ON CONFLICT (brotherID, semesterID) DO NOTHING;
@@ -0,0 +1,93 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+)
+
+// Returns the first day of the semester and the first day after it for labels like
+// "Spring 2024" (January to June) or "Fall 2024" (July to December)
+func SemesterDates(label string) (start time.Time, end time.Time, err error) {
+	season, yearText, ok := strings.Cut(strings.TrimSpace(label), " ")
+	year, yearErr := strconv.Atoi(yearText)
+	if !ok || yearErr != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("semester %q must look like \"Spring 2024\" or \"Fall 2024\"", label)
+	}
+
+	switch strings.ToLower(season) {
+	case "spring":
+		start = time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	case "fall":
+		start = time.Date(year, time.July, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf("semester %q must start with Spring or Fall", label)
+	}
+	return start, start.AddDate(0, 6, 0), nil
+}
+
+// Returns the ID of the semester with the given label, or sql.ErrNoRows
+func SemesterIDByLabel(ctx context.Context, q Querier, semesterLabel string) (int, error) {
+	query := `
+    SELECT semesterID
+    FROM semester
+    WHERE semesterLabel = $1
+    `
+	var semesterID int
+	err := q.QueryRowContext(ctx, query, semesterLabel).Scan(&semesterID)
+	return semesterID, err
+}
+
+// Inserts a semester label and returns the created row
+func CreateSemester(ctx context.Context, q Querier, semesterLabel string) (models.Semester, error) {
+	query := `INSERT INTO semester (semesterLabel) VALUES ($1) RETURNING semesterID, semesterLabel`
+	var semester models.Semester
+	err := q.QueryRowContext(ctx, query, semesterLabel).Scan(
+		&semester.SemesterID,
+		&semester.SemesterLabel,
+	)
+	return semester, err
+}
+
+// Returns the label of the latest semester that starts before semesterLabel,
+// or "" if there is none. Labels that aren't "Spring|Fall <year>" are skipped
+func PreviousSemester(ctx context.Context, q Querier, semesterLabel string) (string, error) {
+	start, _, err := SemesterDates(semesterLabel)
+	if err != nil {
+		return "", err
+	}
+
+	rows, err := q.QueryContext(ctx, `SELECT semesterLabel FROM semester`)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	type semester struct {
+		label string
+		start time.Time
+	}
+	var earlier []semester
+	for rows.Next() {
+		var label string
+		if err := rows.Scan(&label); err != nil {
+			return "", err
+		}
+		if s, _, err := SemesterDates(label); err == nil && s.Before(start) {
+			earlier = append(earlier, semester{label, s})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+	if len(earlier) == 0 {
+		return "", nil
+	}
+	sort.Slice(earlier, func(i, j int) bool { return earlier[i].start.Before(earlier[j].start) })
+	return earlier[len(earlier)-1].label, nil
+}
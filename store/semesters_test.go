@@ -0,0 +1,34 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSemesterDates(t *testing.T) {
+	tests := []struct {
+		label string
+		start string
+		end   string
+	}{
+		{"Spring 2024", "2024-01-01", "2024-07-01"},
+		{"Fall 2024", "2024-07-01", "2025-01-01"},
+		{" fall 2023 ", "2023-07-01", "2024-01-01"},
+	}
+	for _, test := range tests {
+		start, end, err := SemesterDates(test.label)
+		if err != nil {
+			t.Errorf("SemesterDates(%q): %v", test.label, err)
+			continue
+		}
+		if start.Format(time.DateOnly) != test.start || end.Format(time.DateOnly) != test.end {
+			t.Errorf("SemesterDates(%q): expected %s to %s. Got %s to %s", test.label, test.start, test.end, start.Format(time.DateOnly), end.Format(time.DateOnly))
+		}
+	}
+
+	for _, label := range []string{"Summer 2024", "Fall", "2024", "Fall twenty"} {
+		if _, _, err := SemesterDates(label); err == nil {
+			t.Errorf("SemesterDates(%q): expected an error", label)
+		}
+	}
+}
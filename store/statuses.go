@@ -0,0 +1,129 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+)
+
+// Scans a brotherStatus row joined with its semester label into a StatusRecord
+func ScanStatusRecord(row RowScanner) (models.StatusRecord, error) {
+	var record models.StatusRecord
+	err := row.Scan(
+		&record.BrotherID,
+		&record.SemesterID,
+		&record.SemesterLabel,
+		&record.Status,
+	)
+	if err != nil {
+		return models.StatusRecord{}, err
+	}
+	return record, nil
+}
+
+// Inserts a brotherStatus row and returns it joined with its semester label
+func InsertBrotherStatus(ctx context.Context, q Querier, brotherID int, semesterID int, status string) (models.StatusRecord, error) {
+	query := `
+    WITH inserted AS (
+        INSERT INTO brotherStatus (brotherID, semesterID, status)
+        VALUES ($1, $2, $3)
+        RETURNING brotherID, semesterID, status
+    )
+    SELECT i.brotherID, i.semesterID, s.semesterLabel, i.status
+    FROM inserted i
+    JOIN semester s ON s.semesterID = i.semesterID
+    `
+	return ScanStatusRecord(q.QueryRowContext(ctx, query, brotherID, semesterID, status))
+}
+
+// Updates a brotherStatus row and returns it joined with its semester label.
+// Returns sql.ErrNoRows if the brother has no status for the semester
+func UpdateBrotherStatus(ctx context.Context, q Querier, brotherID int, semesterID int, status string) (models.StatusRecord, error) {
+	query := `
+    WITH updated AS (
+        UPDATE brotherStatus SET status = $1
+        WHERE brotherID = $2 AND semesterID = $3
+        RETURNING brotherID, semesterID, status
+    )
+    SELECT u.brotherID, u.semesterID, s.semesterLabel, u.status
+    FROM updated u
+    JOIN semester s ON s.semesterID = u.semesterID
+    `
+	return ScanStatusRecord(q.QueryRowContext(ctx, query, status, brotherID, semesterID))
+}
+
+// Outcome of a semester rollover
+type Rollover struct {
+	From string
+	To   string
+	// The target semester did not exist and was created
+	CreatedSemester bool
+	// Statuses copied into the target semester
+	Copied int
+	// Brothers that already had a status in the target semester and were left unchanged
+	Skipped int
+}
+
+// Starts semester `to` by copying every brother's status from semester `from`, creating `to` if needed.
+// When from is empty, the latest semester before `to` is used. Brothers that already have a status
+// for `to` keep it. Run it inside a transaction to apply all or nothing
+func RolloverStatuses(ctx context.Context, q Querier, from string, to string) (Rollover, error) {
+	result := Rollover{From: from, To: to}
+	if _, _, err := SemesterDates(to); err != nil {
+		return result, err
+	}
+
+	if result.From == "" {
+		previous, err := PreviousSemester(ctx, q, to)
+		if err != nil {
+			return result, err
+		}
+		if previous == "" {
+			return result, fmt.Errorf("no semester before %s to copy statuses from", to)
+		}
+		result.From = previous
+	}
+	fromID, err := SemesterIDByLabel(ctx, q, result.From)
+	if errors.Is(err, sql.ErrNoRows) {
+		return result, fmt.Errorf("semester %s not found", result.From)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	toID, err := SemesterIDByLabel(ctx, q, to)
+	if errors.Is(err, sql.ErrNoRows) {
+		var semester models.Semester
+		semester, err = CreateSemester(ctx, q, to)
+		if err == nil {
+			result.CreatedSemester = true
+			toID, err = strconv.Atoi(semester.SemesterID)
+		}
+	}
+	if err != nil {
+		return result, err
+	}
+
+	var total int
+	if err := q.QueryRowContext(ctx, `SELECT count(*) FROM brotherStatus WHERE semesterID = $1`, fromID).Scan(&total); err != nil {
+		return result, err
+	}
+	copied, err := q.ExecContext(ctx, `
+    INSERT INTO brotherStatus (brotherID, semesterID, status)
+    SELECT brotherID, $2, status FROM brotherStatus WHERE semesterID = $1
+    ON CONFLICT (brotherID, semesterID) DO NOTHING`, fromID, toID)
+	if err != nil {
+		return result, err
+	}
+	affected, err := copied.RowsAffected()
+	if err != nil {
+		return result, err
+	}
+	result.Copied = int(affected)
+	result.Skipped = total - result.Copied
+	return result, nil
+}
@@ -0,0 +1,21 @@
+// Package store holds the queries shared by the HTTP handlers and the ttdb admin CLI
+package store
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Querier runs queries. It is implemented by *db.DB, *sql.DB and *sql.Tx, so store
+// functions can run on their own or as part of a caller's transaction
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// RowScanner is implemented by both *sql.Row and *sql.Rows, so row helpers
+// can be shared between single-row (RETURNING) and multi-row queries
+type RowScanner interface {
+	Scan(dest ...interface{}) error
+}
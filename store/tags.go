@@ -0,0 +1,136 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+)
+
+// Join table linking tags to brothers or events, and its column holding their ID
+type TagTarget struct {
+	table  string
+	column string
+}
+
+// Kinds of records tags are put on
+var (
+	BrotherTags = TagTarget{"brotherTags", "brotherID"}
+	EventTags   = TagTarget{"eventTags", "eventID"}
+)
+
+// Columns of tags in the order ScanTag reads them
+const TagColumns = `tagID, name, createdAt`
+
+// Scans a tags row selected with TagColumns
+func ScanTag(row RowScanner) (models.Tag, error) {
+	var tag models.Tag
+	err := row.Scan(&tag.TagID, &tag.Name, &tag.CreatedAt)
+	if err != nil {
+		return models.Tag{}, err
+	}
+	return tag, nil
+}
+
+func queryTags(ctx context.Context, q Querier, query string, args ...interface{}) ([]models.Tag, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []models.Tag{}
+	for rows.Next() {
+		tag, err := ScanTag(rows)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// Returns every tag sorted by name
+func Tags(ctx context.Context, q Querier) ([]models.Tag, error) {
+	return queryTags(ctx, q, `SELECT `+TagColumns+` FROM tags ORDER BY lower(name)`)
+}
+
+// Returns a tag. Returns sql.ErrNoRows if there is none with the ID
+func GetTag(ctx context.Context, q Querier, tagID int) (models.Tag, error) {
+	return ScanTag(q.QueryRowContext(ctx, `SELECT `+TagColumns+` FROM tags WHERE tagID = $1`, tagID))
+}
+
+// Creates a tag. Fails with tags_name_key if one already has the name, in any case
+func InsertTag(ctx context.Context, q Querier, name string) (models.Tag, error) {
+	return ScanTag(q.QueryRowContext(ctx, `INSERT INTO tags (name) VALUES ($1) RETURNING `+TagColumns, name))
+}
+
+// Renames a tag. Returns sql.ErrNoRows if there is none with the ID
+func RenameTag(ctx context.Context, q Querier, tagID int, name string) (models.Tag, error) {
+	return ScanTag(q.QueryRowContext(ctx, `UPDATE tags SET name = $2 WHERE tagID = $1 RETURNING `+TagColumns, tagID, name))
+}
+
+// Deletes a tag and removes it from every brother and event. Returns sql.ErrNoRows if there is none with the ID
+func DeleteTag(ctx context.Context, q Querier, tagID int) (models.Tag, error) {
+	return ScanTag(q.QueryRowContext(ctx, `DELETE FROM tags WHERE tagID = $1 RETURNING `+TagColumns, tagID))
+}
+
+// Returns how many brothers and events have each tag, sorted by tag name
+func TagCounts(ctx context.Context, q Querier) ([]models.TagCount, error) {
+	rows, err := q.QueryContext(ctx, `
+    SELECT t.tagID, t.name,
+        (SELECT count(*) FROM brotherTags b WHERE b.tagID = t.tagID),
+        (SELECT count(*) FROM eventTags e WHERE e.tagID = t.tagID)
+    FROM tags t
+    ORDER BY lower(t.name)`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []models.TagCount{}
+	for rows.Next() {
+		var count models.TagCount
+		if err := rows.Scan(&count.TagID, &count.Name, &count.Brothers, &count.Events); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+	return counts, rows.Err()
+}
+
+// Returns the tags of a brother or event, sorted by name
+func TagsOf(ctx context.Context, q Querier, target TagTarget, id int) ([]models.Tag, error) {
+	query := fmt.Sprintf(`
+    SELECT t.tagID, t.name, t.createdAt
+    FROM tags t
+    JOIN %s x ON x.tagID = t.tagID
+    WHERE x.%s = $1
+    ORDER BY lower(t.name)`, target.table, target.column)
+	return queryTags(ctx, q, query, id)
+}
+
+// Puts a tag on a brother or event. Tagging twice is not an error
+func AddTag(ctx context.Context, q Querier, target TagTarget, id int, tagID int) error {
+	query := fmt.Sprintf(`INSERT INTO %s (%s, tagID) VALUES ($1, $2) ON CONFLICT DO NOTHING`, target.table, target.column)
+	_, err := q.ExecContext(ctx, query, id, tagID)
+	return err
+}
+
+// Takes a tag off a brother or event. Returns false if it didn't have the tag
+func RemoveTag(ctx context.Context, q Querier, target TagTarget, id int, tagID int) (bool, error) {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE %s = $1 AND tagID = $2`, target.table, target.column)
+	result, err := q.ExecContext(ctx, query, id, tagID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// SQL condition matching the brothers or events whose ID is in idColumn and that have the tag named by
+// the $n argument, in any case
+func TagCondition(target TagTarget, idColumn string, n int) string {
+	return fmt.Sprintf(`%s IN (SELECT x.%s FROM %s x JOIN tags t ON t.tagID = x.tagID WHERE lower(t.name) = lower($%d))`,
+		idColumn, target.column, target.table, n)
+}
@@ -0,0 +1,11 @@
+package store
+
+import "testing"
+
+func TestTagCondition(t *testing.T) {
+	got := TagCondition(EventTags, "e.eventID", 3)
+	expected := `e.eventID IN (SELECT x.eventID FROM eventTags x JOIN tags t ON t.tagID = x.tagID WHERE lower(t.name) = lower($3))`
+	if got != expected {
+		t.Errorf("Expected %s. Got %s", expected, got)
+	}
+}
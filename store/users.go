@@ -0,0 +1,62 @@
+package store
+
+import (
+	"context"
+
+	"github.com/pacific-theta-tau/tt-db/api/models"
+)
+
+// Inserts a user with an already hashed password and returns the created row
+func CreateUser(ctx context.Context, q Querier, user models.User, passwordHash string) (models.User, error) {
+	query := `
+    INSERT INTO users (email, role, passwordHash)
+    VALUES ($1, $2, $3)
+    RETURNING userID, email, role, createdAt`
+	var created models.User
+	err := q.QueryRowContext(ctx, query, user.Email, user.Role, passwordHash).Scan(
+		&created.UserID,
+		&created.Email,
+		&created.Role,
+		&created.CreatedAt,
+	)
+	return created, err
+}
+
+// Returns every user, ordered by ID
+func Users(ctx context.Context, q Querier) ([]models.User, error) {
+	rows, err := q.QueryContext(ctx, `SELECT userID, email, role, createdAt FROM users ORDER BY userID`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.UserID, &user.Email, &user.Role, &user.CreatedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+// Changes the role of a user
+func SetUserRole(ctx context.Context, q Querier, userID int, role string) error {
+	_, err := q.ExecContext(ctx, `UPDATE users SET role = $2 WHERE userID = $1`, userID, role)
+	return err
+}
+
+// Returns the user with an email, regardless of case, and their password hash. Returns sql.ErrNoRows if there is none
+func UserByEmail(ctx context.Context, q Querier, email string) (models.User, string, error) {
+	var user models.User
+	var passwordHash string
+	err := q.QueryRowContext(ctx, `SELECT userID, email, role, createdAt, passwordHash FROM users WHERE lower(email) = lower($1)`, email).Scan(
+		&user.UserID,
+		&user.Email,
+		&user.Role,
+		&user.CreatedAt,
+		&passwordHash,
+	)
+	return user, passwordHash, err
+}
@@ -0,0 +1,101 @@
+// Package tracing sets up OpenTelemetry tracing with W3C trace-context propagation
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/pacific-theta-tau/tt-db/version"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// Exporters accepted by TRACING_EXPORTER
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// Settings for tracing
+type Config struct {
+	// "none" (default), "stdout" or "otlp" (TRACING_EXPORTER)
+	Exporter string
+	// Name of this service in traces (OTEL_SERVICE_NAME)
+	ServiceName string
+	// Fraction of new traces to sample, between 0 and 1 (TRACING_SAMPLE_RATIO).
+	// Requests that arrive with a sampled parent are always traced
+	SampleRatio float64
+}
+
+// Returns the tracing settings used when none are configured
+func DefaultConfig() Config {
+	return Config{
+		Exporter:    ExporterNone,
+		ServiceName: "tt-db",
+		SampleRatio: 1,
+	}
+}
+
+// Checks that the settings are consistent. The OTLP exporter also reads the standard
+// OTEL_EXPORTER_OTLP_* variables, e.g. OTEL_EXPORTER_OTLP_ENDPOINT=http://localhost:4318
+func (c Config) Validate() error {
+	switch c.Exporter {
+	case ExporterNone, ExporterStdout, ExporterOTLP:
+	default:
+		return fmt.Errorf("TRACING_EXPORTER must be one of none, stdout or otlp, got %q", c.Exporter)
+	}
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		return fmt.Errorf("TRACING_SAMPLE_RATIO must be between 0 and 1, got %g", c.SampleRatio)
+	}
+	return nil
+}
+
+// Installs the global tracer provider and W3C trace-context propagator.
+// The returned function flushes pending spans and must be called on shutdown.
+// With the "none" exporter, spans are not recorded but incoming trace context is still propagated
+func Setup(ctx context.Context, config Config) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	switch config.Exporter {
+	case ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterOTLP:
+		exporter, err = otlptracehttp.New(ctx)
+	default:
+		err = fmt.Errorf("unknown exporter %q", config.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("creating %s exporter: %w", config.Exporter, err)
+	}
+
+	commit, _, _ := version.Get()
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(config.ServiceName),
+		semconv.ServiceVersion(commit),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("creating resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
@@ -0,0 +1,44 @@
+// Package version holds build information of the running binary.
+// Commit and BuildTime are set at build time with:
+//
+//	go build -ldflags "-X github.com/pacific-theta-tau/tt-db/version.Commit=$(git rev-parse HEAD) -X github.com/pacific-theta-tau/tt-db/version.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// When they are not set, the VCS information Go embeds in module builds is used instead
+package version
+
+import "runtime/debug"
+
+var (
+	// Git commit the binary was built from
+	Commit = ""
+	// Time the binary was built, in RFC 3339
+	BuildTime = ""
+)
+
+const unknown = "unknown"
+
+// Returns the commit, build time and Go version of the running binary
+func Get() (commit string, buildTime string, goVersion string) {
+	commit, buildTime, goVersion = Commit, BuildTime, unknown
+
+	info, ok := debug.ReadBuildInfo()
+	if ok {
+		goVersion = info.GoVersion
+		for _, setting := range info.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && commit == "":
+				commit = setting.Value
+			case setting.Key == "vcs.time" && buildTime == "":
+				buildTime = setting.Value
+			}
+		}
+	}
+
+	if commit == "" {
+		commit = unknown
+	}
+	if buildTime == "" {
+		buildTime = unknown
+	}
+	return commit, buildTime, goVersion
+}